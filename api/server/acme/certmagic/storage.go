@@ -31,7 +31,8 @@ type storage struct {
 }
 
 func (s *storage) Lock(key string) error {
-	return s.lock.Lock(key, sync.LockTTL(10*time.Minute))
+	_, err := s.lock.Lock(key, sync.LockTTL(10*time.Minute))
+	return err
 }
 
 func (s *storage) Unlock(key string) error {
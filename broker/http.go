@@ -50,12 +50,13 @@ type httpBroker struct {
 }
 
 type httpSubscriber struct {
-	opts  SubscribeOptions
-	id    string
-	topic string
-	fn    Handler
-	svc   *registry.Service
-	hb    *httpBroker
+	opts   SubscribeOptions
+	id     string
+	topic  string
+	fn     Handler
+	cancel context.CancelFunc
+	svc    *registry.Service
+	hb     *httpBroker
 }
 
 type httpEvent struct {
@@ -178,6 +179,7 @@ func (h *httpSubscriber) Topic() string {
 }
 
 func (h *httpSubscriber) Unsubscribe() error {
+	h.cancel()
 	return h.hb.unsubscribe(h)
 }
 
@@ -534,7 +536,11 @@ func (h *httpBroker) Publish(topic string, msg *Message, opts ...PublishOption)
 	// now attempt to get the service
 	h.RLock()
 	s, err := h.r.GetService(serviceName)
-	if err != nil {
+	if err == registry.ErrNotFound {
+		// there are no subscribers
+		h.RUnlock()
+		return nil
+	} else if err != nil {
 		h.RUnlock()
 		return err
 	}
@@ -683,13 +689,15 @@ func (h *httpBroker) Subscribe(topic string, handler Handler, opts ...SubscribeO
 	}
 
 	// generate subscriber
+	ropts, cancel := WithCancel(options)
 	subscriber := &httpSubscriber{
-		opts:  options,
-		hb:    h,
-		id:    node.Id,
-		topic: topic,
-		fn:    handler,
-		svc:   service,
+		opts:   options,
+		hb:     h,
+		id:     node.Id,
+		topic:  topic,
+		fn:     Redeliver(h, topic, handler, ropts),
+		cancel: cancel,
+		svc:    service,
 	}
 
 	// subscribe now
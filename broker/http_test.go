@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/micro/go-micro/v2/broker"
+	"github.com/micro/go-micro/v2/broker/test"
 	"github.com/micro/go-micro/v2/registry"
 	"github.com/micro/go-micro/v2/registry/memory"
 )
@@ -234,6 +235,12 @@ func TestBroker(t *testing.T) {
 	}
 }
 
+func TestBrokerConformance(t *testing.T) {
+	test.Run(t, func() broker.Broker {
+		return broker.NewBroker(broker.Registry(memory.NewRegistry()))
+	})
+}
+
 func TestConcurrentSubBroker(t *testing.T) {
 	m := newTestRegistry()
 	b := broker.NewBroker(broker.Registry(m))
@@ -36,7 +36,9 @@ type memorySubscriber struct {
 	topic   string
 	exit    chan bool
 	handler broker.Handler
+	cancel  context.CancelFunc
 	opts    broker.SubscribeOptions
+	broker  *memoryBroker
 }
 
 func (m *memoryBroker) Options() broker.Options {
@@ -142,40 +144,40 @@ func (m *memoryBroker) Subscribe(topic string, handler broker.Handler, opts ...b
 	}
 	m.RUnlock()
 
-	var options broker.SubscribeOptions
-	for _, o := range opts {
-		o(&options)
-	}
+	options := broker.NewSubscribeOptions(opts...)
+	ropts, cancel := broker.WithCancel(options)
 
 	sub := &memorySubscriber{
-		exit:    make(chan bool, 1),
+		exit:    make(chan bool),
 		id:      uuid.New().String(),
 		topic:   topic,
-		handler: handler,
+		handler: broker.Redeliver(m, topic, handler, ropts),
+		cancel:  cancel,
 		opts:    options,
+		broker:  m,
 	}
 
 	m.Lock()
 	m.Subscribers[topic] = append(m.Subscribers[topic], sub)
 	m.Unlock()
 
-	go func() {
-		<-sub.exit
-		m.Lock()
-		var newSubscribers []*memorySubscriber
-		for _, sb := range m.Subscribers[topic] {
-			if sb.id == sub.id {
-				continue
-			}
-			newSubscribers = append(newSubscribers, sb)
-		}
-		m.Subscribers[topic] = newSubscribers
-		m.Unlock()
-	}()
-
 	return sub, nil
 }
 
+func (m *memoryBroker) unsubscribe(sub *memorySubscriber) {
+	m.Lock()
+	defer m.Unlock()
+
+	var newSubscribers []*memorySubscriber
+	for _, sb := range m.Subscribers[sub.topic] {
+		if sb.id == sub.id {
+			continue
+		}
+		newSubscribers = append(newSubscribers, sb)
+	}
+	m.Subscribers[sub.topic] = newSubscribers
+}
+
 func (m *memoryBroker) String() string {
 	return "memory"
 }
@@ -219,7 +221,15 @@ func (m *memorySubscriber) Topic() string {
 }
 
 func (m *memorySubscriber) Unsubscribe() error {
-	m.exit <- true
+	select {
+	case <-m.exit:
+		return nil
+	default:
+		close(m.exit)
+	}
+
+	m.cancel()
+	m.broker.unsubscribe(m)
 	return nil
 }
 
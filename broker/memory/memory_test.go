@@ -1,12 +1,21 @@
 package memory
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/micro/go-micro/v2/broker"
+	"github.com/micro/go-micro/v2/broker/test"
 )
 
+func TestMemoryBrokerConformance(t *testing.T) {
+	test.Run(t, func() broker.Broker {
+		return NewBroker()
+	})
+}
+
 func TestMemoryBroker(t *testing.T) {
 	b := NewBroker()
 
@@ -48,3 +57,105 @@ func TestMemoryBroker(t *testing.T) {
 		t.Fatalf("Unexpected connect error %v", err)
 	}
 }
+
+func TestMemoryBrokerDeadLetter(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+	attempts := 0
+
+	fn := func(p broker.Event) error {
+		attempts++
+		return errors.New("failed")
+	}
+
+	sub, err := b.Subscribe(topic, fn,
+		broker.MaxAttempts(3),
+		broker.RedeliveryBackoff(func(int) time.Duration { return 0 }),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	var dead *broker.Message
+	dlq, err := b.Subscribe(broker.DeadLetterTopic(topic), func(p broker.Event) error {
+		dead = p.Message()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer dlq.Unsubscribe()
+
+	message := &broker.Message{
+		Header: map[string]string{"foo": "bar"},
+		Body:   []byte(`hello world`),
+	}
+
+	if err := b.Publish(topic, message); err != nil {
+		t.Fatalf("Unexpected error publishing %v", err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", attempts)
+	}
+	if dead == nil {
+		t.Fatal("Expected message on the dead letter topic")
+	}
+	if dead.Header["foo"] != "bar" || string(dead.Body) != "hello world" {
+		t.Fatalf("Unexpected dead letter %+v", dead)
+	}
+	if dead.Header[broker.DeadLetterErrorHeader] != "failed" {
+		t.Fatalf("Expected error header, got %q", dead.Header[broker.DeadLetterErrorHeader])
+	}
+	if dead.Header[broker.DeadLetterAttemptsHeader] != "3" {
+		t.Fatalf("Expected attempts header, got %q", dead.Header[broker.DeadLetterAttemptsHeader])
+	}
+}
+
+func TestMemoryBrokerRedeliveryStopped(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+	failed := make(chan bool, 1)
+
+	sub, err := b.Subscribe(topic, func(p broker.Event) error {
+		failed <- true
+		return errors.New("failed")
+	},
+		broker.MaxAttempts(3),
+		broker.RedeliveryBackoff(func(int) time.Duration { return time.Hour }),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+
+	published := make(chan error, 1)
+	go func() {
+		published <- b.Publish(topic, &broker.Message{Body: []byte(`hello world`)})
+	}()
+
+	// the redelivery waiting for the next attempt stops once unsubscribed
+	<-failed
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unexpected error unsubscribing %v", err)
+	}
+
+	select {
+	case err := <-published:
+		if err == nil {
+			t.Fatal("Expected the error of the last attempt")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the publish to return once unsubscribed")
+	}
+}
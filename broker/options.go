@@ -3,6 +3,7 @@ package broker
 import (
 	"context"
 	"crypto/tls"
+	"time"
 
 	"github.com/micro/go-micro/v2/codec"
 	"github.com/micro/go-micro/v2/registry"
@@ -39,6 +40,13 @@ type SubscribeOptions struct {
 	// will create a shared subscription where each
 	// receives a subset of messages.
 	Queue string
+	// MaxAttempts is the number of times a message is handed to
+	// the handler before it is published to the dead letter topic.
+	// Zero disables redelivery.
+	MaxAttempts int
+	// Backoff returns the delay before the next attempt,
+	// defaults to util/backoff.Do
+	Backoff func(attempts int) time.Duration
 
 	// Other options for implementations of the interface
 	// can be stored in a context
@@ -101,6 +109,21 @@ func ErrorHandler(h Handler) Option {
 	}
 }
 
+// MaxAttempts redelivers a message up to n times in total when the handler
+// returns an error, after which it's published to the dead letter topic
+func MaxAttempts(n int) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.MaxAttempts = n
+	}
+}
+
+// RedeliveryBackoff sets the delay between attempts to handle a message
+func RedeliveryBackoff(fn func(attempts int) time.Duration) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.Backoff = fn
+	}
+}
+
 // Queue sets the name of the queue to share messages on
 func Queue(name string) SubscribeOption {
 	return func(o *SubscribeOptions) {
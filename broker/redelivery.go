@@ -0,0 +1,107 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/micro/go-micro/v2/util/backoff"
+)
+
+const (
+	// DeadLetterTopicHeader holds the topic a dead letter was originally published to
+	DeadLetterTopicHeader = "Micro-Dead-Letter-Topic"
+	// DeadLetterErrorHeader holds the error returned by the last attempt
+	DeadLetterErrorHeader = "Micro-Dead-Letter-Error"
+	// DeadLetterAttemptsHeader holds the number of attempts made
+	DeadLetterAttemptsHeader = "Micro-Dead-Letter-Attempts"
+)
+
+// DeadLetterTopic returns the topic failed messages of a topic are published to
+func DeadLetterTopic(topic string) string {
+	return topic + ".dlq"
+}
+
+// WithCancel returns a copy of the options with a context which is cancelled by calling
+// cancel, for subscribers to stop redelivering their messages once they unsubscribe
+func WithCancel(opts SubscribeOptions) (SubscribeOptions, context.CancelFunc) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	opts.Context = ctx
+	return opts, cancel
+}
+
+// Redeliver wraps a handler so that a message which fails is retried as
+// configured by MaxAttempts and Backoff. Once all attempts have failed the
+// message is published to the dead letter topic via the given broker along
+// with its error and attempt count. Retrying stops with the last error once
+// the context of the options is done, see WithCancel. Handlers are returned
+// unchanged when redelivery is not enabled.
+func Redeliver(b Broker, topic string, h Handler, opts SubscribeOptions) Handler {
+	if opts.MaxAttempts <= 0 {
+		return h
+	}
+
+	delay := opts.Backoff
+	if delay == nil {
+		delay = backoff.Do
+	}
+
+	var done <-chan struct{}
+	if opts.Context != nil {
+		done = opts.Context.Done()
+	}
+
+	return func(e Event) error {
+		var err error
+
+		for attempt := 1; ; attempt++ {
+			if err = h(e); err == nil {
+				return nil
+			}
+			if attempt >= opts.MaxAttempts {
+				break
+			}
+
+			// wait for the next attempt unless the subscriber is stopped
+			t := time.NewTimer(delay(attempt))
+			select {
+			case <-t.C:
+			case <-done:
+				t.Stop()
+				return err
+			}
+		}
+
+		msg := e.Message()
+		if msg == nil {
+			return err
+		}
+
+		header := make(map[string]string, len(msg.Header)+4)
+		for k, v := range msg.Header {
+			header[k] = v
+		}
+		header["Micro-Topic"] = DeadLetterTopic(topic)
+		header[DeadLetterTopicHeader] = topic
+		header[DeadLetterErrorHeader] = err.Error()
+		header[DeadLetterAttemptsHeader] = fmt.Sprintf("%d", opts.MaxAttempts)
+
+		if perr := b.Publish(DeadLetterTopic(topic), &Message{
+			Header: header,
+			Body:   msg.Body,
+		}); perr != nil {
+			return err
+		}
+
+		// the message is now owned by the dead letter topic
+		if !opts.AutoAck {
+			return e.Ack()
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/go-micro/v2/broker"
+	"github.com/micro/go-micro/v2/store"
+)
+
+type storeKey struct{}
+type tableKey struct{}
+type retentionKey struct{}
+type pollKey struct{}
+type startKey struct{}
+
+// start describes where a subscription begins reading a topic
+type start struct {
+	earliest bool
+	offset   *uint64
+	time     time.Time
+}
+
+// Store sets the store used to persist messages. Defaults to the memory store.
+func Store(s store.Store) broker.Option {
+	return setBrokerOption(storeKey{}, s)
+}
+
+// Table sets the table of the store messages are persisted to, so that they're
+// kept apart from the other data of the store. Defaults to DefaultTable.
+func Table(t string) broker.Option {
+	return setBrokerOption(tableKey{}, t)
+}
+
+// Retention sets how long messages are kept in the store. Zero keeps them forever.
+func Retention(d time.Duration) broker.Option {
+	return setBrokerOption(retentionKey{}, d)
+}
+
+// PollInterval sets how often subscribers which are caught up look for the messages
+// published by other brokers sharing the store. Defaults to DefaultPollInterval.
+func PollInterval(d time.Duration) broker.Option {
+	return setBrokerOption(pollKey{}, d)
+}
+
+// FromEarliest starts the subscription at the oldest retained message
+func FromEarliest() broker.SubscribeOption {
+	return setSubscribeOption(startKey{}, start{earliest: true})
+}
+
+// FromLatest starts the subscription after the newest message, only
+// delivering messages published from now on. This is the default.
+func FromLatest() broker.SubscribeOption {
+	return setSubscribeOption(startKey{}, start{})
+}
+
+// FromOffset starts the subscription at the given topic offset
+func FromOffset(o uint64) broker.SubscribeOption {
+	return setSubscribeOption(startKey{}, start{offset: &o})
+}
+
+// FromTime starts the subscription at the first message published at or after t
+func FromTime(t time.Time) broker.SubscribeOption {
+	return setSubscribeOption(startKey{}, start{time: t})
+}
+
+// setBrokerOption returns a function to setup a context with given value
+func setBrokerOption(k, v interface{}) broker.Option {
+	return func(o *broker.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// setSubscribeOption returns a function to setup a context with given value
+func setSubscribeOption(k, v interface{}) broker.SubscribeOption {
+	return func(o *broker.SubscribeOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
@@ -0,0 +1,709 @@
+// Package store provides a durable broker which persists messages to a store
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v2/broker"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/memory"
+)
+
+var (
+	// DefaultTable is the table of the store the broker uses
+	DefaultTable = "broker"
+
+	// messagePrefix is the key prefix messages are stored under,
+	// followed by the topic and the zero padded offset
+	messagePrefix = "message/"
+	// offsetPrefix is the key prefix for committed queue offsets,
+	// followed by the topic and the queue name
+	offsetPrefix = "offset/"
+	// retryTime is how long to wait before delivering a message again
+	// after the handler failed or it couldn't be read
+	retryTime = time.Second
+	// DefaultPollInterval is how often caught up subscribers look for the
+	// messages published by other brokers sharing the store
+	DefaultPollInterval = time.Second
+)
+
+type storeBroker struct {
+	opts      broker.Options
+	store     store.Store
+	database  string
+	table     string
+	retention time.Duration
+	poll      time.Duration
+
+	sync.RWMutex
+	connected bool
+	// next offset to be written per topic
+	offsets map[string]uint64
+	// subscribers per topic
+	subscribers map[string][]*storeSubscriber
+	// shared cursors of queue subscribers keyed by topic and queue
+	cursors map[string]*cursor
+}
+
+// message is the persisted form of a broker.Message
+type message struct {
+	Offset    uint64            `json:"offset"`
+	Timestamp time.Time         `json:"timestamp"`
+	Header    map[string]string `json:"header"`
+	Body      []byte            `json:"body"`
+}
+
+// cursor tracks the position of one or more subscribers in a topic
+type cursor struct {
+	sync.Mutex
+	// key the committed offset is persisted under, empty if not persisted
+	key string
+	// next offset to be handed out
+	next uint64
+	// offset up to which messages have been acked
+	committed uint64
+	// offsets acked after the committed one, waiting for those before them
+	acked map[uint64]bool
+	// offsets handed out which failed, to be handed out again
+	failed []uint64
+}
+
+type storeSubscriber struct {
+	id      string
+	topic   string
+	opts    broker.SubscribeOptions
+	handler broker.Handler
+	cancel  context.CancelFunc
+	broker  *storeBroker
+	cursor  *cursor
+	notify  chan bool
+	exit    chan bool
+}
+
+type storeEvent struct {
+	topic   string
+	offset  uint64
+	message *broker.Message
+	err     error
+	sub     *storeSubscriber
+}
+
+func messageKey(topic string, offset uint64) string {
+	return fmt.Sprintf("%s%s/%020d", messagePrefix, topic, offset)
+}
+
+func cursorKey(topic, queue string) string {
+	return offsetPrefix + topic + "/" + queue
+}
+
+func newCursor(key string, offset uint64) *cursor {
+	return &cursor{
+		key:       key,
+		next:      offset,
+		committed: offset,
+		acked:     make(map[uint64]bool),
+	}
+}
+
+// claim returns the next offset to deliver, the ones which failed first
+func (c *cursor) claim(head uint64) (uint64, bool) {
+	c.Lock()
+	defer c.Unlock()
+	if len(c.failed) > 0 {
+		offset := c.failed[0]
+		c.failed = c.failed[1:]
+		return offset, true
+	}
+	if c.next >= head {
+		return 0, false
+	}
+	offset := c.next
+	c.next++
+	return offset, true
+}
+
+// release an offset which failed so it's delivered again
+func (c *cursor) release(offset uint64) {
+	c.Lock()
+	defer c.Unlock()
+	c.failed = append(c.failed, offset)
+}
+
+// commit acks the offset. Only the offsets acked without a gap are committed,
+// so that a queue resumes from the first message which wasn't acked.
+func (c *cursor) commit(b *storeBroker, offset uint64) error {
+	c.Lock()
+	defer c.Unlock()
+	if offset < c.committed || c.acked[offset] {
+		return nil
+	}
+
+	c.acked[offset] = true
+	committed := c.committed
+	for c.acked[c.committed] {
+		delete(c.acked, c.committed)
+		c.committed++
+	}
+
+	if c.committed == committed || len(c.key) == 0 {
+		return nil
+	}
+	return b.store.Write(&store.Record{
+		Key:   c.key,
+		Value: []byte(strconv.FormatUint(c.committed, 10)),
+	}, store.WriteTo(b.database, b.table))
+}
+
+func (b *storeBroker) configure() {
+	if s, ok := b.opts.Context.Value(storeKey{}).(store.Store); ok {
+		b.store = s
+	}
+	if t, ok := b.opts.Context.Value(tableKey{}).(string); ok {
+		b.table = t
+	}
+	if d, ok := b.opts.Context.Value(retentionKey{}).(time.Duration); ok {
+		b.retention = d
+	}
+	if d, ok := b.opts.Context.Value(pollKey{}).(time.Duration); ok {
+		b.poll = d
+	}
+	b.database = b.store.Options().Database
+}
+
+// list returns the stored offsets of a topic in ascending order
+func (b *storeBroker) list(topic string) ([]uint64, error) {
+	prefix := messagePrefix + topic + "/"
+
+	keys, err := b.store.List(store.ListPrefix(prefix), store.ListFrom(b.database, b.table))
+	if err != nil {
+		return nil, err
+	}
+
+	var offsets []uint64
+	for _, k := range keys {
+		// skip the keys of topics nested below this one
+		offset, err := strconv.ParseUint(strings.TrimPrefix(k, prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		offsets = append(offsets, offset)
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
+
+// earliest returns the oldest retained offset of a topic
+func (b *storeBroker) earliest(topic string) (uint64, error) {
+	offsets, err := b.list(topic)
+	if err != nil {
+		return 0, err
+	}
+	if len(offsets) == 0 {
+		return b.head(topic)
+	}
+	return offsets[0], nil
+}
+
+// head returns the next offset to be written to a topic as last seen by this broker
+func (b *storeBroker) head(topic string) (uint64, error) {
+	b.RLock()
+	offset, ok := b.offsets[topic]
+	b.RUnlock()
+	if ok {
+		return offset, nil
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	return b.loadHead(topic)
+}
+
+// loadHead returns the head of a topic, reading it from the store
+// on first use. Must be called with the lock held.
+func (b *storeBroker) loadHead(topic string) (uint64, error) {
+	if offset, ok := b.offsets[topic]; ok {
+		return offset, nil
+	}
+
+	offsets, err := b.list(topic)
+	if err != nil {
+		return 0, err
+	}
+
+	var offset uint64
+	if len(offsets) > 0 {
+		offset = offsets[len(offsets)-1] + 1
+	}
+	b.offsets[topic] = offset
+	return offset, nil
+}
+
+// setHead moves the head of a topic forward to the offset
+func (b *storeBroker) setHead(topic string, offset uint64) {
+	b.Lock()
+	if offset > b.offsets[topic] {
+		b.offsets[topic] = offset
+	}
+	b.Unlock()
+}
+
+// refresh returns the head of a topic after the messages published
+// since it was last seen, including those of other brokers
+func (b *storeBroker) refresh(topic string) (uint64, error) {
+	head, err := b.head(topic)
+	if err != nil {
+		return 0, err
+	}
+
+	// offsets are written without gaps, so the head is the first missing one
+	for {
+		_, err := b.read(topic, head)
+		if err == store.ErrNotFound {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		head++
+	}
+
+	b.setHead(topic, head)
+	return head, nil
+}
+
+// seek returns the first offset of a topic published at or after t
+func (b *storeBroker) seek(topic string, t time.Time) (uint64, error) {
+	first, err := b.earliest(topic)
+	if err != nil {
+		return 0, err
+	}
+	head, err := b.head(topic)
+	if err != nil {
+		return 0, err
+	}
+
+	// offsets are assigned in publish order so timestamps are ascending
+	var serr error
+	i := sort.Search(int(head-first), func(i int) bool {
+		msg, err := b.read(topic, first+uint64(i))
+		if err == store.ErrNotFound {
+			return false
+		} else if err != nil {
+			serr = err
+			return true
+		}
+		return !msg.Timestamp.Before(t)
+	})
+
+	return first + uint64(i), serr
+}
+
+func (b *storeBroker) read(topic string, offset uint64) (*message, error) {
+	recs, err := b.store.Read(messageKey(topic, offset), store.ReadFrom(b.database, b.table))
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	msg := &message{}
+	if err := json.Unmarshal(recs[0].Value, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// position returns the offset a new subscription starts from
+func (b *storeBroker) position(topic string, options broker.SubscribeOptions) (uint64, error) {
+	var st start
+	if options.Context != nil {
+		st, _ = options.Context.Value(startKey{}).(start)
+	}
+
+	switch {
+	case st.earliest:
+		return b.earliest(topic)
+	case st.offset != nil:
+		return *st.offset, nil
+	case !st.time.IsZero():
+		return b.seek(topic, st.time)
+	}
+
+	// resume a queue from its last committed offset
+	if len(options.Queue) > 0 {
+		recs, err := b.store.Read(cursorKey(topic, options.Queue), store.ReadFrom(b.database, b.table))
+		if err == nil && len(recs) > 0 {
+			return strconv.ParseUint(string(recs[0].Value), 10, 64)
+		} else if err != nil && err != store.ErrNotFound {
+			return 0, err
+		}
+	}
+
+	return b.head(topic)
+}
+
+func (b *storeBroker) Options() broker.Options {
+	return b.opts
+}
+
+func (b *storeBroker) Address() string {
+	return strings.Join(b.store.Options().Nodes, ",")
+}
+
+func (b *storeBroker) Connect() error {
+	b.Lock()
+	defer b.Unlock()
+	b.connected = true
+	return nil
+}
+
+func (b *storeBroker) Disconnect() error {
+	b.Lock()
+	defer b.Unlock()
+
+	// stop the subscribers, queues resume from their committed offsets
+	for _, subs := range b.subscribers {
+		for _, sub := range subs {
+			sub.cancel()
+			close(sub.exit)
+		}
+	}
+	b.subscribers = make(map[string][]*storeSubscriber)
+	b.cursors = make(map[string]*cursor)
+
+	b.connected = false
+	return nil
+}
+
+func (b *storeBroker) Init(opts ...broker.Option) error {
+	for _, o := range opts {
+		o(&b.opts)
+	}
+	b.configure()
+	return nil
+}
+
+func (b *storeBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	b.RLock()
+	connected := b.connected
+	b.RUnlock()
+	if !connected {
+		return errors.New("not connected")
+	}
+
+	offset, err := b.head(topic)
+	if err != nil {
+		return err
+	}
+
+	wopts := []store.WriteOption{store.WriteTo(b.database, b.table), store.WriteIfAbsent()}
+	if b.retention > 0 {
+		wopts = append(wopts, store.WriteTTL(b.retention))
+	}
+
+	// the offset may be taken by another broker sharing the store,
+	// in which case the message is written at the next one
+	for {
+		buf, err := json.Marshal(&message{
+			Offset:    offset,
+			Timestamp: time.Now(),
+			Header:    msg.Header,
+			Body:      msg.Body,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = b.store.Write(&store.Record{
+			Key:   messageKey(topic, offset),
+			Value: buf,
+		}, wopts...)
+		if err == nil {
+			break
+		} else if err != store.ErrConflict {
+			return err
+		}
+		offset++
+	}
+
+	b.setHead(topic, offset+1)
+
+	b.RLock()
+	subs := b.subscribers[topic]
+	b.RUnlock()
+
+	// wake up the subscribers of the topic
+	for _, sub := range subs {
+		select {
+		case sub.notify <- true:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (b *storeBroker) Subscribe(topic string, handler broker.Handler, opts ...broker.SubscribeOption) (broker.Subscriber, error) {
+	b.RLock()
+	if !b.connected {
+		b.RUnlock()
+		return nil, errors.New("not connected")
+	}
+	b.RUnlock()
+
+	options := broker.NewSubscribeOptions(opts...)
+	ropts, cancel := broker.WithCancel(options)
+
+	sub := &storeSubscriber{
+		id:      uuid.New().String(),
+		topic:   topic,
+		opts:    options,
+		handler: broker.Redeliver(b, topic, handler, ropts),
+		cancel:  cancel,
+		broker:  b,
+		notify:  make(chan bool, 1),
+		exit:    make(chan bool),
+	}
+
+	// queue subscribers share a cursor so each message is handled once
+	var key string
+	if len(options.Queue) > 0 {
+		key = cursorKey(topic, options.Queue)
+		b.RLock()
+		sub.cursor = b.cursors[key]
+		b.RUnlock()
+	}
+
+	if sub.cursor == nil {
+		offset, err := b.position(topic, options)
+		if err != nil {
+			return nil, err
+		}
+		c := newCursor(key, offset)
+
+		b.Lock()
+		if len(key) > 0 {
+			// another subscriber may have raced us here
+			if cur, ok := b.cursors[key]; ok {
+				c = cur
+			} else {
+				b.cursors[key] = c
+			}
+		}
+		sub.cursor = c
+		b.Unlock()
+	}
+
+	b.Lock()
+	b.subscribers[topic] = append(b.subscribers[topic], sub)
+	b.Unlock()
+
+	go sub.run()
+
+	return sub, nil
+}
+
+func (b *storeBroker) String() string {
+	return "store"
+}
+
+func (s *storeSubscriber) run() {
+	b := s.broker
+
+	for {
+		select {
+		case <-s.exit:
+			return
+		default:
+		}
+
+		head, err := b.head(s.topic)
+		if err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[store] failed to read head of %s: %v", s.topic, err)
+			}
+		}
+
+		offset, ok := s.cursor.claim(head)
+		if !ok {
+			// look for the messages published by other brokers
+			if head, err = b.refresh(s.topic); err == nil {
+				offset, ok = s.cursor.claim(head)
+			} else if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[store] failed to read head of %s: %v", s.topic, err)
+			}
+		}
+		if !ok {
+			select {
+			case <-s.notify:
+			case <-time.After(b.poll):
+			case <-s.exit:
+				return
+			}
+			continue
+		}
+
+		msg, err := b.read(s.topic, offset)
+		if err == store.ErrNotFound {
+			// the message expired before we got to it
+			s.cursor.commit(b, offset)
+			continue
+		} else if err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[store] failed to read %s offset %d: %v", s.topic, offset, err)
+			}
+			s.retry(offset)
+			continue
+		}
+
+		p := &storeEvent{
+			topic:  s.topic,
+			offset: offset,
+			sub:    s,
+			message: &broker.Message{
+				Header: msg.Header,
+				Body:   msg.Body,
+			},
+		}
+
+		if err := s.handler(p); err != nil {
+			p.err = err
+			if eh := b.opts.ErrorHandler; eh != nil {
+				eh(p)
+			} else if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[store] handler failed for %s offset %d: %v", s.topic, offset, err)
+			}
+			s.retry(offset)
+			continue
+		}
+
+		if s.opts.AutoAck {
+			if err := p.Ack(); err != nil {
+				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+					logger.Errorf("[store] failed to commit %s offset %d: %v", s.topic, offset, err)
+				}
+			}
+		}
+	}
+}
+
+// retry delivers the offset again after a while, unless the subscriber is stopped
+func (s *storeSubscriber) retry(offset uint64) {
+	s.cursor.release(offset)
+
+	select {
+	case <-s.exit:
+	case <-time.After(retryTime):
+	}
+}
+
+func (s *storeSubscriber) Options() broker.SubscribeOptions {
+	return s.opts
+}
+
+func (s *storeSubscriber) Topic() string {
+	return s.topic
+}
+
+func (s *storeSubscriber) Unsubscribe() error {
+	s.cancel()
+
+	b := s.broker
+
+	b.Lock()
+	defer b.Unlock()
+
+	var found bool
+	var subs []*storeSubscriber
+	for _, sub := range b.subscribers[s.topic] {
+		if sub.id == s.id {
+			found = true
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	if !found {
+		return nil
+	}
+	b.subscribers[s.topic] = subs
+
+	// drop the shared cursor once the last queue member leaves
+	if key := s.cursor.key; len(key) > 0 {
+		var shared bool
+		for _, sub := range subs {
+			if sub.cursor == s.cursor {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			delete(b.cursors, key)
+		}
+	}
+
+	close(s.exit)
+	return nil
+}
+
+func (e *storeEvent) Topic() string {
+	return e.topic
+}
+
+func (e *storeEvent) Message() *broker.Message {
+	return e.message
+}
+
+// Ack commits the offset of the event so a queue resumes after it
+func (e *storeEvent) Ack() error {
+	return e.sub.cursor.commit(e.sub.broker, e.offset)
+}
+
+func (e *storeEvent) Error() error {
+	return e.err
+}
+
+// Offset returns the position of an event within its topic. It
+// returns false if the event was not delivered by the store broker.
+func Offset(e broker.Event) (uint64, bool) {
+	se, ok := e.(*storeEvent)
+	if !ok {
+		return 0, false
+	}
+	return se.offset, true
+}
+
+// NewBroker returns a broker which persists every message to a store,
+// allowing subscribers to replay a topic from any offset or time.
+func NewBroker(opts ...broker.Option) broker.Broker {
+	options := broker.Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	b := &storeBroker{
+		opts:        options,
+		store:       memory.NewStore(),
+		table:       DefaultTable,
+		poll:        DefaultPollInterval,
+		offsets:     make(map[string]uint64),
+		subscribers: make(map[string][]*storeSubscriber),
+		cursors:     make(map[string]*cursor),
+	}
+	b.configure()
+
+	return b
+}
@@ -0,0 +1,323 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/broker"
+	"github.com/micro/go-micro/v2/broker/test"
+	"github.com/micro/go-micro/v2/store/memory"
+)
+
+func publish(t *testing.T, b broker.Broker, topic string, from, to int) {
+	for i := from; i < to; i++ {
+		msg := &broker.Message{
+			Header: map[string]string{"id": fmt.Sprintf("%d", i)},
+			Body:   []byte(`hello world`),
+		}
+		if err := b.Publish(topic, msg); err != nil {
+			t.Fatalf("Unexpected error publishing %d: %v", i, err)
+		}
+	}
+}
+
+func consume(t *testing.T, b broker.Broker, topic string, count int, opts ...broker.SubscribeOption) []string {
+	ids := make(chan string, count)
+
+	sub, err := b.Subscribe(topic, func(e broker.Event) error {
+		ids <- e.Message().Header["id"]
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	var got []string
+	for len(got) < count {
+		select {
+		case id := <-ids:
+			got = append(got, id)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d messages, got %d", count, len(got))
+		}
+	}
+	return got
+}
+
+func TestStoreBrokerConformance(t *testing.T) {
+	test.Run(t, func() broker.Broker {
+		return NewBroker()
+	})
+	test.RunQueue(t, func() broker.Broker {
+		return NewBroker()
+	})
+}
+
+func TestStoreBrokerReplay(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+	publish(t, b, topic, 0, 10)
+
+	testData := []struct {
+		name  string
+		opts  []broker.SubscribeOption
+		first int
+	}{
+		{"earliest", []broker.SubscribeOption{FromEarliest()}, 0},
+		{"offset", []broker.SubscribeOption{FromOffset(5)}, 5},
+		{"time", []broker.SubscribeOption{FromTime(time.Now().Add(-time.Minute))}, 0},
+	}
+
+	for _, d := range testData {
+		got := consume(t, b, topic, 10-d.first, d.opts...)
+		for i, id := range got {
+			if id != fmt.Sprintf("%d", d.first+i) {
+				t.Fatalf("%s: expected message %d at position %d, got %s", d.name, d.first+i, i, id)
+			}
+		}
+	}
+
+	if err := b.Disconnect(); err != nil {
+		t.Fatalf("Unexpected disconnect error %v", err)
+	}
+}
+
+func TestStoreBrokerLatest(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+	publish(t, b, topic, 0, 5)
+
+	ids := make(chan string, 10)
+	sub, err := b.Subscribe(topic, func(e broker.Event) error {
+		ids <- e.Message().Header["id"]
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	publish(t, b, topic, 5, 6)
+
+	select {
+	case id := <-ids:
+		if id != "5" {
+			t.Fatalf("Expected only new messages, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a message")
+	}
+}
+
+func TestStoreBrokerQueueResume(t *testing.T) {
+	s := memory.NewStore()
+	topic := "test"
+
+	b := NewBroker(Store(s))
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	// the first consumer of the queue sees everything from the start
+	publish(t, b, topic, 0, 5)
+	consume(t, b, topic, 5, broker.Queue("q"), FromEarliest())
+
+	// messages published while the queue is offline are not lost
+	publish(t, b, topic, 5, 8)
+
+	// a new broker on the same store resumes from the committed offset
+	b = NewBroker(Store(s))
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	got := consume(t, b, topic, 3, broker.Queue("q"))
+	if got[0] != "5" {
+		t.Fatalf("Expected queue to resume at 5, got %s", got[0])
+	}
+}
+
+func TestStoreBrokerRedeliver(t *testing.T) {
+	retryTime = 10 * time.Millisecond
+
+	s := memory.NewStore()
+	b := NewBroker(Store(s))
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+	publish(t, b, topic, 0, 3)
+
+	// the message which failed is delivered again
+	var failed bool
+	ids := make(chan string, 10)
+	sub, err := b.Subscribe(topic, func(e broker.Event) error {
+		id := e.Message().Header["id"]
+		if id == "1" && !failed {
+			failed = true
+			return fmt.Errorf("failed")
+		}
+		ids <- id
+		return nil
+	}, broker.Queue("q"), FromEarliest())
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+
+	got := make(map[string]bool)
+	for len(got) < 3 {
+		select {
+		case id := <-ids:
+			got[id] = true
+		case <-time.After(time.Second):
+			t.Fatalf("Expected 3 messages, got %v", got)
+		}
+	}
+	sub.Unsubscribe()
+
+	// the messages are kept in their own table
+	keys, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) > 0 {
+		t.Fatalf("Expected no keys in the default table, got %v", keys)
+	}
+}
+
+func TestStoreBrokerUnacked(t *testing.T) {
+	s := memory.NewStore()
+	topic := "test"
+
+	b := NewBroker(Store(s))
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	publish(t, b, topic, 0, 3)
+
+	// the second message is never acked
+	ids := make(chan string, 3)
+	_, err := b.Subscribe(topic, func(e broker.Event) error {
+		id := e.Message().Header["id"]
+		if id != "1" {
+			e.Ack()
+		}
+		ids <- id
+		return nil
+	}, broker.Queue("q"), FromEarliest(), broker.DisableAutoAck())
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ids:
+		case <-time.After(time.Second):
+			t.Fatal("Expected 3 messages")
+		}
+	}
+
+	// disconnecting stops the subscriber
+	if err := b.Disconnect(); err != nil {
+		t.Fatalf("Unexpected disconnect error %v", err)
+	}
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	publish(t, b, topic, 3, 4)
+
+	select {
+	case id := <-ids:
+		t.Fatalf("Expected no message after disconnecting, got %s", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// the queue resumes from the message which wasn't acked
+	b = NewBroker(Store(s))
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	got := consume(t, b, topic, 3, broker.Queue("q"))
+	if got[0] != "1" {
+		t.Fatalf("Expected queue to resume at 1, got %s", got[0])
+	}
+}
+
+func TestStoreBrokerShared(t *testing.T) {
+	s := memory.NewStore()
+	topic := "test"
+
+	var brokers []broker.Broker
+	for i := 0; i < 2; i++ {
+		b := NewBroker(Store(s), PollInterval(10*time.Millisecond))
+		if err := b.Connect(); err != nil {
+			t.Fatalf("Unexpected connect error %v", err)
+		}
+		brokers = append(brokers, b)
+	}
+
+	// both brokers publish to the topic without overwriting each other
+	errs := make(chan error, len(brokers))
+	for i, b := range brokers {
+		go func(b broker.Broker, from int) {
+			for j := from; j < from+20; j++ {
+				msg := &broker.Message{Header: map[string]string{"id": fmt.Sprintf("%d", j)}}
+				if err := b.Publish(topic, msg); err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}(b, i*20)
+	}
+	for range brokers {
+		if err := <-errs; err != nil {
+			t.Fatalf("Unexpected error publishing %v", err)
+		}
+	}
+
+	got := consume(t, brokers[0], topic, 40, FromEarliest())
+	seen := make(map[string]bool)
+	for _, id := range got {
+		if seen[id] {
+			t.Fatalf("Message %s delivered twice", id)
+		}
+		seen[id] = true
+	}
+
+	// a subscriber of one broker sees the messages published by the other
+	ids := make(chan string, 1)
+	sub, err := brokers[0].Subscribe(topic, func(e broker.Event) error {
+		ids <- e.Message().Header["id"]
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	publish(t, brokers[1], topic, 40, 41)
+
+	select {
+	case id := <-ids:
+		if id != "40" {
+			t.Fatalf("Expected message 40, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the message published by the other broker")
+	}
+}
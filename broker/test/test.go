@@ -0,0 +1,279 @@
+// Package test provides a conformance test suite for broker implementations
+package test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v2/broker"
+)
+
+var (
+	// the time to wait for a message to be delivered
+	deliveryTimeout = 5 * time.Second
+	// the time to wait for messages which shouldn't be delivered
+	quietTime = 200 * time.Millisecond
+)
+
+// Run the conformance tests against the brokers returned by newBroker. Each call
+// to newBroker must return a new, initialised broker which isn't yet connected.
+// The broker is disconnected at the end of each test.
+func Run(t *testing.T, newBroker func() broker.Broker) {
+	tests := []struct {
+		name string
+		test func(*testing.T, broker.Broker)
+	}{
+		{"PublishSubscribe", testPublishSubscribe},
+		{"Topics", testTopics},
+		{"Subscribers", testSubscribers},
+		{"Unsubscribe", testUnsubscribe},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBroker()
+			if err := b.Connect(); err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer b.Disconnect()
+			tt.test(t, b)
+		})
+	}
+}
+
+// RunQueue runs the tests of the subscribers sharing a queue against the brokers
+// returned by newBroker, for the brokers which deliver each message to one
+// subscriber of a queue. The broker is disconnected at the end of the test.
+func RunQueue(t *testing.T, newBroker func() broker.Broker) {
+	b := newBroker()
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer b.Disconnect()
+	testQueue(t, b)
+}
+
+// receiver counts the messages delivered to a subscriber
+type receiver struct {
+	sync.Mutex
+	messages []*broker.Message
+	topics   []string
+	ch       chan bool
+}
+
+func newReceiver() *receiver {
+	return &receiver{ch: make(chan bool, 1000)}
+}
+
+func (r *receiver) handler(e broker.Event) error {
+	r.Lock()
+	r.messages = append(r.messages, e.Message())
+	r.topics = append(r.topics, e.Topic())
+	r.Unlock()
+	r.ch <- true
+	return nil
+}
+
+func (r *receiver) count() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.messages)
+}
+
+// wait for n messages to be delivered
+func (r *receiver) wait(t *testing.T, n int) {
+	t.Helper()
+	timeout := time.After(deliveryTimeout)
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.ch:
+		case <-timeout:
+			t.Fatalf("Expected %d messages, got %d after %v", n, r.count(), deliveryTimeout)
+		}
+	}
+}
+
+func subscribe(t *testing.T, b broker.Broker, topic string, r *receiver, opts ...broker.SubscribeOption) broker.Subscriber {
+	t.Helper()
+	sub, err := b.Subscribe(topic, r.handler, opts...)
+	if err != nil {
+		t.Fatalf("Subscribe %s: %v", topic, err)
+	}
+	return sub
+}
+
+func publish(t *testing.T, b broker.Broker, topic string, body string) {
+	t.Helper()
+	msg := &broker.Message{
+		Header: map[string]string{"Content-Type": "text/plain", "Id": body},
+		Body:   []byte(body),
+	}
+	if err := b.Publish(topic, msg); err != nil {
+		t.Fatalf("Publish %s: %v", topic, err)
+	}
+}
+
+func testPublishSubscribe(t *testing.T, b broker.Broker) {
+	topic := uuid.New().String()
+	r := newReceiver()
+
+	sub := subscribe(t, b, topic, r)
+	defer sub.Unsubscribe()
+
+	if sub.Topic() != topic {
+		t.Fatalf("Expected subscriber topic %s, got %s", topic, sub.Topic())
+	}
+
+	publish(t, b, topic, "hello")
+	r.wait(t, 1)
+
+	r.Lock()
+	defer r.Unlock()
+
+	msg := r.messages[0]
+	if msg == nil {
+		t.Fatal("Expected a message, got nil")
+	}
+	if string(msg.Body) != "hello" {
+		t.Fatalf("Expected body hello, got %s", msg.Body)
+	}
+	if msg.Header["Id"] != "hello" || msg.Header["Content-Type"] != "text/plain" {
+		t.Fatalf("Expected the published headers, got %v", msg.Header)
+	}
+	if r.topics[0] != topic {
+		t.Fatalf("Expected event topic %s, got %s", topic, r.topics[0])
+	}
+}
+
+func testTopics(t *testing.T, b broker.Broker) {
+	topic, other := uuid.New().String(), uuid.New().String()
+	r := newReceiver()
+
+	sub := subscribe(t, b, topic, r)
+	defer sub.Unsubscribe()
+
+	// publishing to a topic without subscribers is not an error
+	publish(t, b, other, "other")
+	publish(t, b, topic, "hello")
+
+	r.wait(t, 1)
+	time.Sleep(quietTime)
+
+	if n := r.count(); n != 1 {
+		t.Fatalf("Expected 1 message, got %d", n)
+	}
+}
+
+func testSubscribers(t *testing.T, b broker.Broker) {
+	topic := uuid.New().String()
+	count := 10
+
+	// every subscriber without a queue gets every message
+	receivers := []*receiver{newReceiver(), newReceiver(), newReceiver()}
+	for _, r := range receivers {
+		sub := subscribe(t, b, topic, r)
+		defer sub.Unsubscribe()
+	}
+
+	for i := 0; i < count; i++ {
+		publish(t, b, topic, fmt.Sprintf("message-%d", i))
+	}
+
+	for _, r := range receivers {
+		r.wait(t, count)
+	}
+	time.Sleep(quietTime)
+
+	for i, r := range receivers {
+		if n := r.count(); n != count {
+			t.Fatalf("Expected subscriber %d to get %d messages, got %d", i, count, n)
+		}
+	}
+}
+
+func testUnsubscribe(t *testing.T, b broker.Broker) {
+	topic := uuid.New().String()
+	r := newReceiver()
+
+	sub := subscribe(t, b, topic, r)
+	publish(t, b, topic, "hello")
+	r.wait(t, 1)
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+
+	publish(t, b, topic, "hello")
+	time.Sleep(quietTime)
+
+	if n := r.count(); n != 1 {
+		t.Fatalf("Expected 1 message after unsubscribing, got %d", n)
+	}
+}
+
+func testQueue(t *testing.T, b broker.Broker) {
+	topic := uuid.New().String()
+	count := 10
+
+	// a subscriber of each queue gets each message
+	queues := map[string][]*receiver{
+		"queue-a": {newReceiver(), newReceiver()},
+		"queue-b": {newReceiver(), newReceiver(), newReceiver()},
+	}
+	for queue, receivers := range queues {
+		for _, r := range receivers {
+			sub := subscribe(t, b, topic, r, broker.Queue(queue))
+			defer sub.Unsubscribe()
+
+			if q := sub.Options().Queue; q != queue {
+				t.Fatalf("Expected subscriber queue %s, got %s", queue, q)
+			}
+		}
+	}
+
+	// as does a subscriber without a queue
+	all := newReceiver()
+	sub := subscribe(t, b, topic, all)
+	defer sub.Unsubscribe()
+
+	for i := 0; i < count; i++ {
+		publish(t, b, topic, fmt.Sprintf("message-%d", i))
+	}
+
+	all.wait(t, count)
+
+	deadline := time.Now().Add(deliveryTimeout)
+	for queue, receivers := range queues {
+		for {
+			var n int
+			for _, r := range receivers {
+				n += r.count()
+			}
+			if n == count {
+				break
+			}
+			if n > count || time.Now().After(deadline) {
+				t.Fatalf("Expected %d messages for queue %s, got %d", count, queue, n)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	time.Sleep(quietTime)
+
+	for queue, receivers := range queues {
+		var n int
+		for _, r := range receivers {
+			n += r.count()
+		}
+		if n != count {
+			t.Fatalf("Expected %d messages for queue %s, got %d", count, queue, n)
+		}
+	}
+	if n := all.count(); n != count {
+		t.Fatalf("Expected %d messages without a queue, got %d", count, n)
+	}
+}
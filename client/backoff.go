@@ -2,13 +2,40 @@ package client
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
+	"github.com/micro/go-micro/v2/errors"
 	"github.com/micro/go-micro/v2/util/backoff"
 )
 
 type BackoffFunc func(ctx context.Context, req Request, attempts int) (time.Duration, error)
 
+type retryErrorKey struct{}
+
+// NewRetryContext returns a context carrying the error of the attempt being
+// retried. The clients pass it to the BackoffFunc so that it can back off
+// depending on the error.
+func NewRetryContext(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, retryErrorKey{}, err)
+}
+
+// RetryError returns the error of the attempt being retried, nil if there's none
+func RetryError(ctx context.Context) error {
+	err, _ := ctx.Value(retryErrorKey{}).(error)
+	return err
+}
+
 func exponentialBackoff(ctx context.Context, req Request, attempts int) (time.Duration, error) {
-	return backoff.Do(attempts), nil
+	d := backoff.Do(attempts)
+
+	// the server is shedding load, back off for longer and spread
+	// the retries so they don't come back at once
+	if err := RetryError(ctx); err != nil {
+		if e := errors.Parse(err.Error()); e != nil && e.Code == 429 {
+			d += time.Duration(rand.Int63n(int64(d) + 1))
+		}
+	}
+
+	return d, nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/micro/go-micro/v2/errors"
 )
 
 func TestBackoff(t *testing.T) {
@@ -29,3 +31,25 @@ func TestBackoff(t *testing.T) {
 		}
 	}
 }
+
+func TestBackoffTooManyRequests(t *testing.T) {
+	c := NewClient()
+	req := c.NewRequest("test", "test", nil)
+	ctx := NewRetryContext(context.TODO(), errors.TooManyRequests("test", "shed"))
+
+	// shed requests back off for longer than other errors, spread out up to twice as long
+	normal, err := exponentialBackoff(context.TODO(), req, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		d, err := exponentialBackoff(ctx, req, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d < normal || d > 2*normal {
+			t.Fatalf("Expected between %v and %v, got %v", normal, 2*normal, d)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package breaker provides per node circuit breakers for the client
+package breaker
+
+import (
+	"time"
+
+	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+// OpenId is the id of the errors of the requests not sent because of an open circuit
+const OpenId = "go.micro.client.circuit_open"
+
+// Breaker tracks the result of requests to the nodes of a service and
+// trips a node's circuit when it fails too often, so that requests stop
+// being sent to it until it has had time to recover.
+type Breaker interface {
+	Init(...Option) error
+	Options() Options
+	// Allow reports whether a request may be sent to the node. When the
+	// circuit is half open only a single trial request is allowed.
+	Allow(service string, node *registry.Node) bool
+	// Mark records the result of a request sent to the node
+	Mark(service string, node *registry.Node, err error)
+	// Filter removes the nodes with an open circuit, it can be used as a selector.Filter
+	Filter(services []*registry.Service) []*registry.Service
+	// Status returns the circuits of a service, or of all services if empty
+	Status(service string) []*Status
+	// Reset closes all the circuits of a service
+	Reset(service string)
+	// String returns the name of the implementation
+	String() string
+}
+
+// State of a circuit
+type State int
+
+const (
+	// Closed lets all requests through
+	Closed State = iota
+	// Open rejects all requests until the cool down has passed
+	Open
+	// HalfOpen lets a single trial request through to test the node
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Status of the circuit of a single node
+type Status struct {
+	Service string
+	Node    string
+	Address string
+	State   State
+	// Requests and Failures recorded in the current window
+	Requests int
+	Failures int
+	// Updated is the time the circuit last changed state
+	Updated time.Time
+}
+
+// Tripped reports whether any circuit of the service is not closed
+func Tripped(b Breaker, service string) bool {
+	for _, s := range b.Status(service) {
+		if s.State != Closed {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrOpen returns the service unavailable error of a request not sent because of an open circuit
+func ErrOpen(format string, a ...interface{}) error {
+	return errors.ServiceUnavailable(OpenId, format, a...)
+}
+
+// IsOpen reports whether the error is of a request not sent because of an open circuit
+func IsOpen(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.Parse(err.Error())
+	return e.Code == 503 && e.Id == OpenId
+}
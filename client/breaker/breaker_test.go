@@ -0,0 +1,151 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+func TestBreaker(t *testing.T) {
+	b := NewBreaker(
+		FailureRatio(0.5),
+		MinRequests(4),
+		Window(time.Minute),
+		Cooldown(50*time.Millisecond),
+	)
+
+	service := "foo"
+	node := &registry.Node{Id: "foo-1", Address: "localhost:9999"}
+	other := &registry.Node{Id: "foo-2", Address: "localhost:9998"}
+	fail := errors.InternalServerError("foo", "failed")
+
+	// client errors don't count as failures
+	for i := 0; i < 4; i++ {
+		b.Mark(service, node, errors.BadRequest("foo", "bad"))
+	}
+	if !b.Allow(service, node) {
+		t.Fatal("Expected circuit to be closed after client errors")
+	}
+
+	b.Reset(service)
+
+	b.Mark(service, node, nil)
+	b.Mark(service, node, fail)
+	b.Mark(service, node, nil)
+	if !b.Allow(service, node) {
+		t.Fatal("Expected circuit to be closed below min requests")
+	}
+
+	b.Mark(service, node, fail)
+	if b.Allow(service, node) {
+		t.Fatal("Expected circuit to be open")
+	}
+	if !b.Allow(service, other) {
+		t.Fatal("Expected other node to be allowed")
+	}
+	if !Tripped(b, service) {
+		t.Fatal("Expected service to be tripped")
+	}
+
+	services := b.Filter([]*registry.Service{{Name: service, Nodes: []*registry.Node{node, other}}})
+	if len(services) != 1 || len(services[0].Nodes) != 1 || services[0].Nodes[0].Id != other.Id {
+		t.Fatalf("Expected open node to be filtered, got %+v", services)
+	}
+
+	// after the cool down a single trial is allowed
+	time.Sleep(60 * time.Millisecond)
+	if !b.Allow(service, node) {
+		t.Fatal("Expected half open circuit to allow a trial request")
+	}
+	if b.Allow(service, node) {
+		t.Fatal("Expected half open circuit to allow only one trial request")
+	}
+
+	// a failed trial opens the circuit again
+	b.Mark(service, node, fail)
+	if st := b.Status(service)[0].State; st != Open {
+		t.Fatalf("Expected circuit to be open, got %s", st)
+	}
+
+	// a successful trial closes it
+	time.Sleep(60 * time.Millisecond)
+	if !b.Allow(service, node) {
+		t.Fatal("Expected half open circuit to allow a trial request")
+	}
+	b.Mark(service, node, nil)
+	if st := b.Status(service)[0].State; st != Closed {
+		t.Fatalf("Expected circuit to be closed, got %s", st)
+	}
+	if Tripped(b, service) {
+		t.Fatal("Expected service not to be tripped")
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	testData := []struct {
+		err     error
+		failure bool
+	}{
+		{nil, false},
+		{errors.BadRequest("foo", "bad"), false},
+		{errors.InternalServerError("foo", "failed"), true},
+		{errors.Timeout("foo", "deadline exceeded"), true},
+		{context.Canceled, false},
+		{errors.Timeout("go.micro.client", "%v", context.Canceled), false},
+		{errors.Timeout("go.micro.client", "call timeout: %v", context.Canceled), false},
+	}
+
+	for _, d := range testData {
+		if f := IsFailure(d.err); f != d.failure {
+			t.Fatalf("Expected failure %v for %v, got %v", d.failure, d.err, f)
+		}
+	}
+}
+
+func TestIsOpen(t *testing.T) {
+	if !IsOpen(ErrOpen("service %s: circuit open", "foo")) {
+		t.Fatal("Expected the error of an open circuit")
+	}
+	if IsOpen(errors.ServiceUnavailable("foo", "unavailable")) {
+		t.Fatal("Expected a service unavailable error not to be of an open circuit")
+	}
+}
+
+func TestBreakerCancelledTrial(t *testing.T) {
+	b := NewBreaker(
+		FailureRatio(0.5),
+		MinRequests(1),
+		Window(time.Minute),
+		Cooldown(10*time.Millisecond),
+	)
+
+	service := "foo"
+	node := &registry.Node{Id: "foo-1", Address: "localhost:9999"}
+	services := []*registry.Service{{Name: service, Nodes: []*registry.Node{node}}}
+
+	b.Mark(service, node, errors.InternalServerError("foo", "failed"))
+	time.Sleep(20 * time.Millisecond)
+
+	// the trial of the half open circuit is cancelled e.g as a hedge which lost
+	if !b.Allow(service, node) {
+		t.Fatal("Expected the trial request to be allowed")
+	}
+	if len(b.Filter(services)) != 0 {
+		t.Fatal("Expected the node to be filtered during the trial")
+	}
+	b.Mark(service, node, errors.Timeout("go.micro.client", "%v", context.Canceled))
+
+	// the node can be selected again for another trial
+	if len(b.Filter(services)) != 1 {
+		t.Fatal("Expected the node to be selected again after a cancelled trial")
+	}
+	if !b.Allow(service, node) {
+		t.Fatal("Expected another trial request to be allowed")
+	}
+	if s := b.Status(service); len(s) != 1 || s[0].State != HalfOpen {
+		t.Fatalf("Expected the circuit to stay half open, got %+v", s)
+	}
+}
@@ -0,0 +1,308 @@
+package breaker
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+var (
+	// DefaultFailureRatio at which a circuit opens
+	DefaultFailureRatio = 0.5
+	// DefaultMinRequests before a circuit can open
+	DefaultMinRequests = 10
+	// DefaultWindow over which requests are counted
+	DefaultWindow = 10 * time.Second
+	// DefaultCooldown before an open circuit is half opened
+	DefaultCooldown = 5 * time.Second
+)
+
+type circuit struct {
+	service string
+	node    string
+	address string
+	state   State
+	// counts for the current window
+	requests int
+	failures int
+	window   time.Time
+	// time of the last state change
+	updated time.Time
+	// whether the half open trial request is in flight
+	trial bool
+}
+
+type breaker struct {
+	sync.Mutex
+	opts Options
+	// circuits keyed by service and node
+	circuits map[string]map[string]*circuit
+}
+
+// NewBreaker returns a breaker which keeps a circuit per service node in memory
+func NewBreaker(opts ...Option) Breaker {
+	options := Options{
+		FailureRatio: DefaultFailureRatio,
+		MinRequests:  DefaultMinRequests,
+		Window:       DefaultWindow,
+		Cooldown:     DefaultCooldown,
+		Context:      context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &breaker{
+		opts:     options,
+		circuits: make(map[string]map[string]*circuit),
+	}
+}
+
+// IsFailure reports whether an error indicates the node itself failed, as opposed
+// to the request being rejected e.g bad request or not found, or being cancelled
+func IsFailure(err error) bool {
+	if err == nil || isCancelled(err) {
+		return false
+	}
+	e := errors.Parse(err.Error())
+	return e.Code == 0 || e.Code == 408 || e.Code >= 500
+}
+
+// isCancelled reports whether the error is of a cancelled request
+func isCancelled(err error) bool {
+	if err == context.Canceled {
+		return true
+	}
+	// the clients return the cancellation of a request as a timeout
+	e := errors.Parse(err.Error())
+	return strings.HasSuffix(e.Detail, context.Canceled.Error())
+}
+
+func nodeID(node *registry.Node) string {
+	if len(node.Id) > 0 {
+		return node.Id
+	}
+	return node.Address
+}
+
+// lookup returns the circuit of a node, creating it if asked to
+func (b *breaker) lookup(service string, node *registry.Node, create bool) *circuit {
+	nodes, ok := b.circuits[service]
+	if !ok {
+		if !create {
+			return nil
+		}
+		nodes = make(map[string]*circuit)
+		b.circuits[service] = nodes
+	}
+
+	id := nodeID(node)
+	c, ok := nodes[id]
+	if !ok && create {
+		now := time.Now()
+		c = &circuit{
+			service: service,
+			node:    id,
+			address: node.Address,
+			window:  now,
+			updated: now,
+		}
+		nodes[id] = c
+	}
+	return c
+}
+
+// state returns the state of the circuit, half opening it once the
+// cool down has passed. Must be called with the lock held.
+func (b *breaker) state(c *circuit, now time.Time) State {
+	if c.state == Open && now.Sub(c.updated) >= b.opts.Cooldown {
+		b.transition(c, HalfOpen, now)
+	}
+	return c.state
+}
+
+func (b *breaker) transition(c *circuit, s State, now time.Time) {
+	c.state = s
+	c.updated = now
+	c.trial = false
+	c.requests = 0
+	c.failures = 0
+	c.window = now
+}
+
+func (b *breaker) Init(opts ...Option) error {
+	b.Lock()
+	defer b.Unlock()
+
+	for _, o := range opts {
+		o(&b.opts)
+	}
+	return nil
+}
+
+func (b *breaker) Options() Options {
+	b.Lock()
+	defer b.Unlock()
+	return b.opts
+}
+
+func (b *breaker) Allow(service string, node *registry.Node) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	c := b.lookup(service, node, false)
+	if c == nil {
+		return true
+	}
+
+	switch b.state(c, time.Now()) {
+	case Open:
+		return false
+	case HalfOpen:
+		if c.trial {
+			return false
+		}
+		c.trial = true
+	}
+
+	return true
+}
+
+func (b *breaker) Mark(service string, node *registry.Node, err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	failed := IsFailure(err)
+
+	// a cancelled request says nothing of the node, it only
+	// frees the trial of a half open circuit for another request
+	if err != nil && isCancelled(err) {
+		if c := b.lookup(service, node, false); c != nil && b.state(c, now) == HalfOpen {
+			c.trial = false
+		}
+		return
+	}
+
+	c := b.lookup(service, node, true)
+
+	switch b.state(c, now) {
+	case Open:
+		// a request sent before the circuit opened
+		return
+	case HalfOpen:
+		if failed {
+			b.transition(c, Open, now)
+		} else {
+			b.transition(c, Closed, now)
+		}
+		return
+	}
+
+	// start a new window
+	if now.Sub(c.window) > b.opts.Window {
+		c.requests = 0
+		c.failures = 0
+		c.window = now
+	}
+
+	c.requests++
+	if failed {
+		c.failures++
+	}
+
+	if c.requests < b.opts.MinRequests {
+		return
+	}
+
+	if float64(c.failures)/float64(c.requests) >= b.opts.FailureRatio {
+		b.transition(c, Open, now)
+	}
+}
+
+func (b *breaker) Filter(old []*registry.Service) []*registry.Service {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	var services []*registry.Service
+
+	for _, service := range old {
+		var nodes []*registry.Node
+
+		for _, node := range service.Nodes {
+			if c := b.lookup(service.Name, node, false); c != nil {
+				switch b.state(c, now) {
+				case Open:
+					continue
+				case HalfOpen:
+					if c.trial {
+						continue
+					}
+				}
+			}
+			nodes = append(nodes, node)
+		}
+
+		// only add service if there's some nodes
+		if len(nodes) > 0 {
+			// copy
+			serv := new(registry.Service)
+			*serv = *service
+			serv.Nodes = nodes
+			services = append(services, serv)
+		}
+	}
+
+	return services
+}
+
+func (b *breaker) Status(service string) []*Status {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	var status []*Status
+
+	for name, nodes := range b.circuits {
+		if len(service) > 0 && name != service {
+			continue
+		}
+		for _, c := range nodes {
+			status = append(status, &Status{
+				Service:  c.service,
+				Node:     c.node,
+				Address:  c.address,
+				State:    b.state(c, now),
+				Requests: c.requests,
+				Failures: c.failures,
+				Updated:  c.updated,
+			})
+		}
+	}
+
+	sort.Slice(status, func(i, j int) bool {
+		if status[i].Service == status[j].Service {
+			return status[i].Node < status[j].Node
+		}
+		return status[i].Service < status[j].Service
+	})
+
+	return status
+}
+
+func (b *breaker) Reset(service string) {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.circuits, service)
+}
+
+func (b *breaker) String() string {
+	return "memory"
+}
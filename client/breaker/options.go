@@ -0,0 +1,52 @@
+package breaker
+
+import (
+	"context"
+	"time"
+)
+
+type Options struct {
+	// FailureRatio of requests within the window at which the circuit opens
+	FailureRatio float64
+	// MinRequests is the number of requests within the window
+	// required before the failure ratio is considered
+	MinRequests int
+	// Window is the period over which requests are counted
+	Window time.Duration
+	// Cooldown is how long a circuit stays open before a trial request is let through
+	Cooldown time.Duration
+
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
+}
+
+type Option func(o *Options)
+
+// FailureRatio at which the circuit of a node opens, between 0 and 1
+func FailureRatio(r float64) Option {
+	return func(o *Options) {
+		o.FailureRatio = r
+	}
+}
+
+// MinRequests within the window before a circuit can open
+func MinRequests(n int) Option {
+	return func(o *Options) {
+		o.MinRequests = n
+	}
+}
+
+// Window over which requests and failures are counted
+func Window(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+// Cooldown is how long a circuit stays open before it is half opened
+func Cooldown(d time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = d
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/micro/go-micro/v2/broker"
 	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/client/breaker"
 	"github.com/micro/go-micro/v2/client/selector"
 	raw "github.com/micro/go-micro/v2/codec/bytes"
 	"github.com/micro/go-micro/v2/errors"
@@ -30,6 +31,9 @@ type grpcClient struct {
 	opts client.Options
 	pool *pool
 	once atomic.Value
+
+	// hedges idempotent requests
+	hedger *client.Hedger
 }
 
 func init() {
@@ -84,9 +88,19 @@ func (g *grpcClient) next(request client.Request, opts client.CallOptions) (sele
 		}, nil
 	}
 
+	sopts := opts.SelectOptions
+
+	// skip the nodes with an open circuit
+	if g.opts.Breaker != nil {
+		sopts = append(sopts[:len(sopts):len(sopts)], selector.WithFilter(g.opts.Breaker.Filter))
+	}
+
 	// get next nodes from the selector
-	next, err := g.opts.Selector.Select(service, opts.SelectOptions...)
+	next, err := g.opts.Selector.Select(service, sopts...)
 	if err != nil {
+		if err == selector.ErrNoneAvailable && g.opts.Breaker != nil && breaker.Tripped(g.opts.Breaker, service) {
+			return nil, breaker.ErrOpen("service %s: circuit open", service)
+		}
 		if err == selector.ErrNotFound {
 			return nil, errors.InternalServerError("go.micro.client", "service %s: %s", service, err.Error())
 		}
@@ -390,6 +404,14 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 		opt(&callOpts)
 	}
 
+	// route on the hash key if there is one
+	if callOpts.HashKey != nil {
+		if key := callOpts.HashKey(ctx, req); len(key) > 0 {
+			sopts := callOpts.SelectOptions
+			callOpts.SelectOptions = append(sopts[:len(sopts):len(sopts)], selector.WithHashKey(key))
+		}
+	}
+
 	next, err := g.next(req, callOpts)
 	if err != nil {
 		return err
@@ -424,10 +446,60 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 		gcall = callOpts.CallWrappers[i-1](gcall)
 	}
 
+	// send makes the call to a single node
+	send := func(ctx context.Context, node *registry.Node, rsp interface{}) error {
+		service := req.Service()
+
+		// fail fast if the circuit opened since selection
+		if g.opts.Breaker != nil && !g.opts.Breaker.Allow(service, node) {
+			return breaker.ErrOpen("service %s node %s: circuit open", service, node.Id)
+		}
+
+		// make the call, recording the node load for the strategies
+		selector.DefaultStats.Start(node)
+		start := time.Now()
+		err := gcall(ctx, node, req, rsp, callOpts)
+		took := time.Since(start)
+		selector.DefaultStats.Done(node, took)
+
+		// cancelled e.g because a hedged request answered first, which
+		// only frees the trial of a half open circuit
+		if err != nil && ctx.Err() == context.Canceled {
+			if g.opts.Breaker != nil {
+				g.opts.Breaker.Mark(service, node, context.Canceled)
+			}
+			return err
+		}
+
+		g.opts.Selector.Mark(service, node, err)
+		if g.opts.Breaker != nil {
+			g.opts.Breaker.Mark(service, node, err)
+		}
+		if err == nil {
+			g.hedger.Record(req, took)
+		}
+		if verr, ok := err.(*errors.Error); ok {
+			return verr
+		}
+
+		return err
+	}
+
+	// only hedge requests which are safe to send more than once
+	hedge := client.Hedged(rsp, callOpts)
+	// hedging goes through the proxy like retries
+	if _, _, ok := pnet.Proxy(req.Service(), callOpts.Address); ok {
+		hedge = false
+	}
+
 	// return errors.New("go.micro.client", "request timeout", 408)
-	call := func(i int) error {
+	call := func(i int, rerr error) error {
 		// call backoff first. Someone may want an initial start delay
-		t, err := callOpts.Backoff(ctx, req, i)
+		bctx := ctx
+		if rerr != nil {
+			bctx = client.NewRetryContext(ctx, rerr)
+		}
+		t, err := callOpts.Backoff(bctx, req, i)
 		if err != nil {
 			return errors.InternalServerError("go.micro.client", err.Error())
 		}
@@ -437,6 +509,10 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 			time.Sleep(t)
 		}
 
+		if hedge {
+			return g.hedger.Hedge(ctx, next, send, req, rsp, callOpts)
+		}
+
 		// select next node
 		node, err := next()
 		service := req.Service()
@@ -447,23 +523,16 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 			return errors.InternalServerError("go.micro.client", "error selecting %s node: %s", service, err.Error())
 		}
 
-		// make the call
-		err = gcall(ctx, node, req, rsp, callOpts)
-		g.opts.Selector.Mark(service, node, err)
-		if verr, ok := err.(*errors.Error); ok {
-			return verr
-		}
-
-		return err
+		return send(ctx, node, rsp)
 	}
 
 	ch := make(chan error, callOpts.Retries+1)
 	var gerr error
 
 	for i := 0; i <= callOpts.Retries; i++ {
-		go func(i int) {
-			ch <- call(i)
-		}(i)
+		go func(i int, err error) {
+			ch <- call(i, err)
+		}(i, gerr)
 
 		select {
 		case <-ctx.Done():
@@ -497,6 +566,14 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 		opt(&callOpts)
 	}
 
+	// route on the hash key if there is one
+	if callOpts.HashKey != nil {
+		if key := callOpts.HashKey(ctx, req); len(key) > 0 {
+			sopts := callOpts.SelectOptions
+			callOpts.SelectOptions = append(sopts[:len(sopts):len(sopts)], selector.WithHashKey(key))
+		}
+	}
+
 	next, err := g.next(req, callOpts)
 	if err != nil {
 		return nil, err
@@ -519,9 +596,13 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 		gstream = callOpts.CallWrappers[i-1](gstream)
 	}
 
-	call := func(i int) (client.Stream, error) {
+	call := func(i int, rerr error) (client.Stream, error) {
 		// call backoff first. Someone may want an initial start delay
-		t, err := callOpts.Backoff(ctx, req, i)
+		bctx := ctx
+		if rerr != nil {
+			bctx = client.NewRetryContext(ctx, rerr)
+		}
+		t, err := callOpts.Backoff(bctx, req, i)
 		if err != nil {
 			return nil, errors.InternalServerError("go.micro.client", err.Error())
 		}
@@ -540,11 +621,22 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 			return nil, errors.InternalServerError("go.micro.client", "error selecting %s node: %s", service, err.Error())
 		}
 
-		// make the call
+		// fail fast if the circuit opened since selection
+		if g.opts.Breaker != nil && !g.opts.Breaker.Allow(service, node) {
+			return nil, breaker.ErrOpen("service %s node %s: circuit open", service, node.Id)
+		}
+
+		// only the time to establish the stream is recorded
 		stream := &grpcStream{}
+		selector.DefaultStats.Start(node)
+		start := time.Now()
 		err = g.stream(ctx, node, req, stream, callOpts)
+		selector.DefaultStats.Done(node, time.Since(start))
 
 		g.opts.Selector.Mark(service, node, err)
+		if g.opts.Breaker != nil {
+			g.opts.Breaker.Mark(service, node, err)
+		}
 		return stream, err
 	}
 
@@ -557,10 +649,10 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 	var grr error
 
 	for i := 0; i <= callOpts.Retries; i++ {
-		go func(i int) {
-			s, err := call(i)
+		go func(i int, err error) {
+			s, err := call(i, err)
 			ch <- response{s, err}
-		}(i)
+		}(i, grr)
 
 		select {
 		case <-ctx.Done():
@@ -693,7 +785,8 @@ func newClient(opts ...client.Option) client.Client {
 	}
 
 	rc := &grpcClient{
-		opts: options,
+		opts:   options,
+		hedger: client.NewHedger(),
 	}
 	rc.once.Store(false)
 
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/client/selector"
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/memory"
+)
+
+type hedgeResponse struct {
+	Node string
+}
+
+func TestGRPCClientHedge(t *testing.T) {
+	var mtx sync.Mutex
+	var nodes []string
+
+	// the first node called is slow, the rest answer immediately
+	wrap := func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			mtx.Lock()
+			nodes = append(nodes, node.Id)
+			first := len(nodes) == 1
+			mtx.Unlock()
+
+			if first {
+				select {
+				case <-time.After(time.Second):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			rsp.(*hedgeResponse).Node = node.Id
+			return nil
+		}
+	}
+
+	r := memory.NewRegistry()
+	r.Register(&registry.Service{
+		Name:    "foo",
+		Version: "1",
+		Nodes: []*registry.Node{
+			{Id: "foo-1", Address: "10.0.0.1:8080"},
+			{Id: "foo-2", Address: "10.0.0.2:8080"},
+		},
+	})
+
+	c := NewClient(
+		client.Registry(r),
+		client.Selector(selector.NewSelector(selector.Registry(r))),
+		client.WrapCall(wrap),
+	)
+
+	req := c.NewRequest("foo", "Foo.Bar", nil)
+	rsp := new(hedgeResponse)
+
+	start := time.Now()
+	if err := c.Call(context.Background(), req, rsp, client.WithIdempotent(), client.WithHedging(1, 10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(start); took > 500*time.Millisecond {
+		t.Fatalf("expected hedged response, took %v", took)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(nodes) != 2 || nodes[0] == nodes[1] {
+		t.Fatalf("expected a hedge to a different node, got %v", nodes)
+	}
+	if rsp.Node != nodes[1] {
+		t.Fatalf("expected response from %s, got %s", nodes[1], rsp.Node)
+	}
+}
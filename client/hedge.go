@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/client/selector"
+	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+var (
+	// DefaultLatencySamples is the number of recent latencies
+	// kept per endpoint to compute the hedging percentile
+	DefaultLatencySamples = 100
+	// minLatencySamples before the percentile is trusted
+	minLatencySamples = 10
+	// hedgeAttempts is how many nodes are picked to find one not yet used,
+	// as strategies such as random may pick the same node several times
+	hedgeAttempts = 10
+)
+
+// latencies records the recent latencies of each endpoint
+type latencies struct {
+	sync.Mutex
+	samples map[string]*latencyWindow
+}
+
+type latencyWindow struct {
+	values []time.Duration
+	next   int
+}
+
+func newLatencies() *latencies {
+	return &latencies{
+		samples: make(map[string]*latencyWindow),
+	}
+}
+
+func latencyKey(req Request) string {
+	return req.Service() + "." + req.Endpoint()
+}
+
+func (l *latencies) Record(req Request, d time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+
+	w, ok := l.samples[latencyKey(req)]
+	if !ok {
+		w = &latencyWindow{}
+		l.samples[latencyKey(req)] = w
+	}
+
+	if len(w.values) < DefaultLatencySamples {
+		w.values = append(w.values, d)
+		return
+	}
+
+	w.values[w.next] = d
+	w.next = (w.next + 1) % len(w.values)
+}
+
+// Percentile returns the p-th percentile, between 0 and 100, of the
+// recent latencies of the endpoint or false if there are too few samples
+func (l *latencies) Percentile(req Request, p float64) (time.Duration, bool) {
+	l.Lock()
+	w, ok := l.samples[latencyKey(req)]
+	if !ok || len(w.values) < minLatencySamples {
+		l.Unlock()
+		return 0, false
+	}
+	values := make([]time.Duration, len(w.values))
+	copy(values, w.values)
+	l.Unlock()
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	i := int(math.Ceil(p/100*float64(len(values)))) - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(values) {
+		i = len(values) - 1
+	}
+
+	return values[i], true
+}
+
+// Hedger sends hedged requests for the client implementations, keeping the
+// recent latencies of each endpoint to hedge after a percentile of them
+type Hedger struct {
+	latencies *latencies
+}
+
+// NewHedger returns a hedger without any latencies recorded
+func NewHedger() *Hedger {
+	return &Hedger{latencies: newLatencies()}
+}
+
+// Hedged returns true if the call is hedged, which requires the request to be
+// idempotent and the response a pointer each hedged request can be decoded into
+func Hedged(rsp interface{}, opts CallOptions) bool {
+	if opts.Hedges <= 0 || !opts.Idempotent {
+		return false
+	}
+	v := reflect.ValueOf(rsp)
+	return v.Kind() == reflect.Ptr && !v.IsNil()
+}
+
+// Record the latency of a successful request
+func (h *Hedger) Record(req Request, d time.Duration) {
+	h.latencies.Record(req, d)
+}
+
+// delay returns how long to wait for a response before sending another request
+func (h *Hedger) delay(req Request, opts CallOptions) time.Duration {
+	if opts.HedgePercentile > 0 {
+		if d, ok := h.latencies.Percentile(req, opts.HedgePercentile); ok {
+			return d
+		}
+	}
+	return opts.HedgeDelay
+}
+
+// Hedge sends the request to a node and, if no response arrives within the
+// hedge delay, to up to opts.Hedges other nodes. The first successful
+// response is written to rsp and the outstanding requests are cancelled.
+func (h *Hedger) Hedge(
+	ctx context.Context,
+	next selector.Next,
+	send func(context.Context, *registry.Node, interface{}) error,
+	req Request,
+	rsp interface{},
+	opts CallOptions,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		rsp interface{}
+		err error
+	}
+
+	ch := make(chan result, opts.Hedges+1)
+	typ := reflect.TypeOf(rsp).Elem()
+	used := make(map[string]bool)
+
+	// launch sends the request to a node not yet used
+	launch := func() error {
+		var node *registry.Node
+		for i := 0; i < hedgeAttempts; i++ {
+			n, err := next()
+			if err != nil {
+				return err
+			}
+			if !used[n.Id+n.Address] {
+				node = n
+				break
+			}
+		}
+		if node == nil {
+			return selector.ErrNoneAvailable
+		}
+		used[node.Id+node.Address] = true
+
+		// each request decodes into its own response
+		out := reflect.New(typ).Interface()
+		go func() {
+			err := send(ctx, node, out)
+			ch <- result{out, err}
+		}()
+		return nil
+	}
+
+	if err := launch(); err != nil {
+		service := req.Service()
+		if err == selector.ErrNotFound {
+			return errors.InternalServerError("go.micro.client", "service %s: %s", service, err.Error())
+		}
+		return errors.InternalServerError("go.micro.client", "error getting next %s node: %s", service, err.Error())
+	}
+
+	pending := 1
+	hedges := opts.Hedges
+	delay := h.delay(req, opts)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var gerr error
+
+	for pending > 0 {
+		select {
+		case res := <-ch:
+			pending--
+			if res.err == nil {
+				reflect.ValueOf(rsp).Elem().Set(reflect.ValueOf(res.rsp).Elem())
+				return nil
+			}
+			gerr = res.err
+		case <-timer.C:
+			if hedges == 0 {
+				continue
+			}
+			// no other node to hedge to
+			if err := launch(); err != nil {
+				hedges = 0
+				continue
+			}
+			hedges--
+			pending++
+			timer.Reset(delay)
+		case <-ctx.Done():
+			return errors.Timeout("go.micro.client", fmt.Sprintf("%v", ctx.Err()))
+		}
+	}
+
+	return gerr
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/client/selector"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+type hedgeResponse struct {
+	Node string
+}
+
+func TestCallHedge(t *testing.T) {
+	var mtx sync.Mutex
+	var nodes []string
+
+	// the first node called is slow, the rest answer immediately
+	wrap := func(cf CallFunc) CallFunc {
+		return func(ctx context.Context, node *registry.Node, req Request, rsp interface{}, opts CallOptions) error {
+			mtx.Lock()
+			nodes = append(nodes, node.Id)
+			first := len(nodes) == 1
+			mtx.Unlock()
+
+			if first {
+				select {
+				case <-time.After(time.Second):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			rsp.(*hedgeResponse).Node = node.Id
+			return nil
+		}
+	}
+
+	r := newTestRegistry()
+	c := NewClient(
+		Registry(r),
+		WrapCall(wrap),
+	)
+	c.Options().Selector.Init(selector.Registry(r))
+
+	req := c.NewRequest("foo", "Foo.Bar", nil)
+	rsp := new(hedgeResponse)
+
+	start := time.Now()
+	err := c.Call(context.Background(), req, rsp, WithIdempotent(), WithHedging(1, 10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(start); took > 500*time.Millisecond {
+		t.Fatalf("expected hedged response, took %v", took)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(nodes))
+	}
+	if nodes[0] == nodes[1] {
+		t.Fatalf("expected hedge to a different node, both sent to %s", nodes[0])
+	}
+	if rsp.Node != nodes[1] {
+		t.Fatalf("expected response from %s, got %s", nodes[1], rsp.Node)
+	}
+}
+
+func TestCallNotIdempotent(t *testing.T) {
+	var mtx sync.Mutex
+	var called int
+
+	wrap := func(cf CallFunc) CallFunc {
+		return func(ctx context.Context, node *registry.Node, req Request, rsp interface{}, opts CallOptions) error {
+			mtx.Lock()
+			called++
+			mtx.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}
+	}
+
+	r := newTestRegistry()
+	c := NewClient(
+		Registry(r),
+		WrapCall(wrap),
+	)
+	c.Options().Selector.Init(selector.Registry(r))
+
+	req := c.NewRequest("foo", "Foo.Bar", nil)
+
+	if err := c.Call(context.Background(), req, new(hedgeResponse), WithHedging(2, time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if called != 1 {
+		t.Fatalf("expected a single request, got %d", called)
+	}
+}
+
+func TestLatenciesPercentile(t *testing.T) {
+	l := newLatencies()
+	req := newRequest("foo", "Foo.Bar", nil, "")
+
+	if _, ok := l.Percentile(req, 90); ok {
+		t.Fatal("expected no percentile without samples")
+	}
+
+	for i := 1; i <= 100; i++ {
+		l.Record(req, time.Duration(i)*time.Millisecond)
+	}
+
+	d, ok := l.Percentile(req, 90)
+	if !ok {
+		t.Fatal("expected percentile")
+	}
+	if d != 90*time.Millisecond {
+		t.Fatalf("expected 90ms got %v", d)
+	}
+}
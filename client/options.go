@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/micro/go-micro/v2/broker"
+	"github.com/micro/go-micro/v2/client/breaker"
 	"github.com/micro/go-micro/v2/client/selector"
 	"github.com/micro/go-micro/v2/codec"
+	"github.com/micro/go-micro/v2/metadata"
 	"github.com/micro/go-micro/v2/registry"
 	"github.com/micro/go-micro/v2/transport"
 )
@@ -32,6 +34,9 @@ type Options struct {
 	// Response cache
 	Cache *Cache
 
+	// Circuit breaker, nil disables it
+	Breaker breaker.Breaker
+
 	// Middleware for client
 	Wrappers []Wrapper
 
@@ -64,6 +69,19 @@ type CallOptions struct {
 	ServiceToken bool
 	// Duration to cache the response for
 	CacheExpiry time.Duration
+	// HashKey returns the key requests are routed on with consistent
+	// hashing, so that requests with the same key go to the same node
+	HashKey func(ctx context.Context, req Request) string
+	// Idempotent marks the request as safe to send more than once
+	Idempotent bool
+	// Hedges is the number of extra requests sent to other nodes
+	// when a response is slow, only idempotent requests are hedged
+	Hedges int
+	// HedgeDelay is how long to wait for a response before hedging
+	HedgeDelay time.Duration
+	// HedgePercentile of recent latencies, between 0 and 100, after
+	// which to hedge. HedgeDelay is used until there are enough samples.
+	HedgePercentile float64
 
 	// Middleware for low level call func
 	CallWrappers []CallWrapper
@@ -129,6 +147,13 @@ func Broker(b broker.Broker) Option {
 	}
 }
 
+// Breaker sets the circuit breaker used to stop calling failing nodes
+func Breaker(b breaker.Breaker) Option {
+	return func(o *Options) {
+		o.Breaker = b
+	}
+}
+
 // Codec to be used to encode/decode requests for a given content type
 func Codec(contentType string, c codec.NewCodec) Option {
 	return func(o *Options) {
@@ -268,6 +293,54 @@ func WithSelectOption(so ...selector.SelectOption) CallOption {
 	}
 }
 
+// WithHashKey is a CallOption which routes the request with consistent
+// hashing on the key, sending requests with the same key to the same node
+func WithHashKey(key string) CallOption {
+	return func(o *CallOptions) {
+		o.HashKey = func(context.Context, Request) string {
+			return key
+		}
+	}
+}
+
+// WithHashMetadata is a CallOption which routes the request with consistent
+// hashing on the value of the given key in the context metadata
+func WithHashMetadata(key string) CallOption {
+	return func(o *CallOptions) {
+		o.HashKey = func(ctx context.Context, _ Request) string {
+			v, _ := metadata.Get(ctx, key)
+			return v
+		}
+	}
+}
+
+// WithIdempotent is a CallOption which marks the request as safe to be
+// sent more than once, allowing it to be hedged
+func WithIdempotent() CallOption {
+	return func(o *CallOptions) {
+		o.Idempotent = true
+	}
+}
+
+// WithHedging is a CallOption which sends the request to up to n other
+// nodes if no response arrives within the delay. The first successful
+// response is used and the rest are cancelled. Only requests marked with
+// WithIdempotent are hedged.
+func WithHedging(n int, delay time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.Hedges = n
+		o.HedgeDelay = delay
+	}
+}
+
+// WithHedgePercentile is a CallOption which hedges once the request takes
+// longer than the p-th percentile of the endpoint's recent latencies
+func WithHedgePercentile(p float64) CallOption {
+	return func(o *CallOptions) {
+		o.HedgePercentile = p
+	}
+}
+
 // WithCallWrapper is a CallOption which adds to the existing CallFunc wrappers
 func WithCallWrapper(cw ...CallWrapper) CallOption {
 	return func(o *CallOptions) {
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 
+	"github.com/micro/go-micro/v2/client/breaker"
 	"github.com/micro/go-micro/v2/errors"
 )
 
@@ -14,7 +15,9 @@ func RetryAlways(ctx context.Context, req Request, retryCount int, err error) (b
 	return true, nil
 }
 
-// RetryOnError retries a request on a 500 or timeout error
+// RetryOnError retries a request on a 408, 429 or 500 error, or when it wasn't sent
+// because of an open circuit. When the server is shedding load with a 429 error
+// the default backoff spreads the retries.
 func RetryOnError(ctx context.Context, req Request, retryCount int, err error) (bool, error) {
 	if err == nil {
 		return false, nil
@@ -26,9 +29,12 @@ func RetryOnError(ctx context.Context, req Request, retryCount int, err error) (
 	}
 
 	switch e.Code {
-	// retry on timeout or internal server error
-	case 408, 500:
+	// retry on timeout, too many requests or internal server error
+	case 408, 429, 500:
 		return true, nil
+	// retry on another node when the circuit of the node is open
+	case 503:
+		return breaker.IsOpen(err), nil
 	default:
 		return false, nil
 	}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v2/client/breaker"
+	"github.com/micro/go-micro/v2/errors"
+)
+
+func TestRetryOnError(t *testing.T) {
+	testData := []struct {
+		err   error
+		retry bool
+	}{
+		{nil, false},
+		{errors.BadRequest("test", "bad request"), false},
+		{errors.Timeout("test", "timeout"), true},
+		{errors.InternalServerError("test", "error"), true},
+		{errors.ServiceUnavailable("test", "unavailable"), false},
+		{breaker.ErrOpen("circuit open"), true},
+		{errors.TooManyRequests("test", "shed"), true},
+	}
+
+	for _, d := range testData {
+		retry, err := RetryOnError(context.Background(), nil, 0, d.err)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retry != d.retry {
+			t.Fatalf("expected retry %v for %v got %v", d.retry, d.err, retry)
+		}
+	}
+}
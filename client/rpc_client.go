@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/micro/go-micro/v2/broker"
+	"github.com/micro/go-micro/v2/client/breaker"
 	"github.com/micro/go-micro/v2/client/selector"
 	"github.com/micro/go-micro/v2/codec"
 	raw "github.com/micro/go-micro/v2/codec/bytes"
@@ -25,6 +26,9 @@ type rpcClient struct {
 	opts Options
 	pool pool.Pool
 	seq  uint64
+
+	// hedges idempotent requests
+	hedger *Hedger
 }
 
 func newRpcClient(opt ...Option) Client {
@@ -37,9 +41,10 @@ func newRpcClient(opt ...Option) Client {
 	)
 
 	rc := &rpcClient{
-		opts: opts,
-		pool: p,
-		seq:  0,
+		opts:   opts,
+		pool:   p,
+		seq:    0,
+		hedger: NewHedger(),
 	}
 	rc.once.Store(false)
 
@@ -345,9 +350,19 @@ func (r *rpcClient) next(request Request, opts CallOptions) (selector.Next, erro
 		}, nil
 	}
 
+	sopts := opts.SelectOptions
+
+	// skip the nodes with an open circuit
+	if r.opts.Breaker != nil {
+		sopts = append(sopts[:len(sopts):len(sopts)], selector.WithFilter(r.opts.Breaker.Filter))
+	}
+
 	// get next nodes from the selector
-	next, err := r.opts.Selector.Select(service, opts.SelectOptions...)
+	next, err := r.opts.Selector.Select(service, sopts...)
 	if err != nil {
+		if err == selector.ErrNoneAvailable && r.opts.Breaker != nil && breaker.Tripped(r.opts.Breaker, service) {
+			return nil, breaker.ErrOpen("service %s: circuit open", service)
+		}
 		if err == selector.ErrNotFound {
 			return nil, errors.InternalServerError("go.micro.client", "service %s: %s", service, err.Error())
 		}
@@ -364,6 +379,14 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 		opt(&callOpts)
 	}
 
+	// route on the hash key if there is one
+	if callOpts.HashKey != nil {
+		if key := callOpts.HashKey(ctx, request); len(key) > 0 {
+			sopts := callOpts.SelectOptions
+			callOpts.SelectOptions = append(sopts[:len(sopts):len(sopts)], selector.WithHashKey(key))
+		}
+	}
+
 	next, err := r.next(request, callOpts)
 	if err != nil {
 		return err
@@ -398,10 +421,56 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 		rcall = callOpts.CallWrappers[i-1](rcall)
 	}
 
+	// send makes the call to a single node
+	send := func(ctx context.Context, node *registry.Node, rsp interface{}) error {
+		service := request.Service()
+
+		// fail fast if the circuit opened since selection
+		if r.opts.Breaker != nil && !r.opts.Breaker.Allow(service, node) {
+			return breaker.ErrOpen("service %s node %s: circuit open", service, node.Id)
+		}
+
+		// make the call, recording the node load for the strategies
+		selector.DefaultStats.Start(node)
+		start := time.Now()
+		err := rcall(ctx, node, request, rsp, callOpts)
+		took := time.Since(start)
+		selector.DefaultStats.Done(node, took)
+
+		// cancelled e.g because a hedged request answered first, which
+		// only frees the trial of a half open circuit
+		if err != nil && ctx.Err() == context.Canceled {
+			if r.opts.Breaker != nil {
+				r.opts.Breaker.Mark(service, node, context.Canceled)
+			}
+			return err
+		}
+
+		r.opts.Selector.Mark(service, node, err)
+		if r.opts.Breaker != nil {
+			r.opts.Breaker.Mark(service, node, err)
+		}
+		if err == nil {
+			r.hedger.Record(request, took)
+		}
+		return err
+	}
+
+	// only hedge requests which are safe to send more than once
+	hedge := Hedged(response, callOpts)
+	// hedging goes through the proxy like retries
+	if _, _, ok := net.Proxy(request.Service(), callOpts.Address); ok {
+		hedge = false
+	}
+
 	// return errors.New("go.micro.client", "request timeout", 408)
-	call := func(i int) error {
+	call := func(i int, rerr error) error {
 		// call backoff first. Someone may want an initial start delay
-		t, err := callOpts.Backoff(ctx, request, i)
+		bctx := ctx
+		if rerr != nil {
+			bctx = NewRetryContext(ctx, rerr)
+		}
+		t, err := callOpts.Backoff(bctx, request, i)
 		if err != nil {
 			return errors.InternalServerError("go.micro.client", "backoff error: %v", err.Error())
 		}
@@ -411,6 +480,10 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 			time.Sleep(t)
 		}
 
+		if hedge {
+			return r.hedger.Hedge(ctx, next, send, request, response, callOpts)
+		}
+
 		// select next node
 		node, err := next()
 		service := request.Service()
@@ -421,10 +494,7 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 			return errors.InternalServerError("go.micro.client", "error getting next %s node: %s", service, err.Error())
 		}
 
-		// make the call
-		err = rcall(ctx, node, request, response, callOpts)
-		r.opts.Selector.Mark(service, node, err)
-		return err
+		return send(ctx, node, response)
 	}
 
 	// get the retries
@@ -439,9 +509,9 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 	var gerr error
 
 	for i := 0; i <= retries; i++ {
-		go func(i int) {
-			ch <- call(i)
-		}(i)
+		go func(i int, err error) {
+			ch <- call(i, err)
+		}(i, gerr)
 
 		select {
 		case <-ctx.Done():
@@ -475,6 +545,14 @@ func (r *rpcClient) Stream(ctx context.Context, request Request, opts ...CallOpt
 		opt(&callOpts)
 	}
 
+	// route on the hash key if there is one
+	if callOpts.HashKey != nil {
+		if key := callOpts.HashKey(ctx, request); len(key) > 0 {
+			sopts := callOpts.SelectOptions
+			callOpts.SelectOptions = append(sopts[:len(sopts):len(sopts)], selector.WithHashKey(key))
+		}
+	}
+
 	next, err := r.next(request, callOpts)
 	if err != nil {
 		return nil, err
@@ -487,9 +565,13 @@ func (r *rpcClient) Stream(ctx context.Context, request Request, opts ...CallOpt
 	default:
 	}
 
-	call := func(i int) (Stream, error) {
+	call := func(i int, rerr error) (Stream, error) {
 		// call backoff first. Someone may want an initial start delay
-		t, err := callOpts.Backoff(ctx, request, i)
+		bctx := ctx
+		if rerr != nil {
+			bctx = NewRetryContext(ctx, rerr)
+		}
+		t, err := callOpts.Backoff(bctx, request, i)
 		if err != nil {
 			return nil, errors.InternalServerError("go.micro.client", "backoff error: %v", err.Error())
 		}
@@ -508,8 +590,20 @@ func (r *rpcClient) Stream(ctx context.Context, request Request, opts ...CallOpt
 			return nil, errors.InternalServerError("go.micro.client", "error getting next %s node: %s", service, err.Error())
 		}
 
+		// fail fast if the circuit opened since selection
+		if r.opts.Breaker != nil && !r.opts.Breaker.Allow(service, node) {
+			return nil, breaker.ErrOpen("service %s node %s: circuit open", service, node.Id)
+		}
+
+		// only the time to establish the stream is recorded
+		selector.DefaultStats.Start(node)
+		start := time.Now()
 		stream, err := r.stream(ctx, node, request, callOpts)
+		selector.DefaultStats.Done(node, time.Since(start))
 		r.opts.Selector.Mark(service, node, err)
+		if r.opts.Breaker != nil {
+			r.opts.Breaker.Mark(service, node, err)
+		}
 		return stream, err
 	}
 
@@ -530,10 +624,10 @@ func (r *rpcClient) Stream(ctx context.Context, request Request, opts ...CallOpt
 	var grr error
 
 	for i := 0; i <= retries; i++ {
-		go func(i int) {
-			s, err := call(i)
+		go func(i int, err error) {
+			s, err := call(i, err)
 			ch <- response{s, err}
-		}(i)
+		}(i, grr)
 
 		select {
 		case <-ctx.Done():
@@ -3,6 +3,7 @@ package selector
 import (
 	"time"
 
+	"github.com/micro/go-micro/v2/health"
 	"github.com/micro/go-micro/v2/registry"
 	"github.com/micro/go-micro/v2/registry/cache"
 )
@@ -18,6 +19,15 @@ func (c *registrySelector) newCache() cache.Cache {
 		if t, ok := c.so.Context.Value("selector_ttl").(time.Duration); ok {
 			ropts = append(ropts, cache.WithTTL(t))
 		}
+		if hc, ok := c.so.Context.Value("selector_health_check").(health.Check); ok {
+			ropts = append(ropts, cache.WithHealthCheck(hc))
+		}
+		if t, ok := c.so.Context.Value("selector_health_interval").(time.Duration); ok {
+			ropts = append(ropts, cache.WithHealthInterval(t))
+		}
+		if s, ok := c.so.Context.Value("selector_snapshot").(cache.Snapshot); ok {
+			ropts = append(ropts, cache.WithSnapshot(s))
+		}
 	}
 	return cache.New(c.so.Registry, ropts...)
 }
@@ -71,6 +81,7 @@ func (c *registrySelector) Select(service string, opts ...SelectOption) (Next, e
 }
 
 func (c *registrySelector) Mark(service string, node *registry.Node, err error) {
+	DefaultStats.Mark(node, err)
 }
 
 func (c *registrySelector) Reset(service string) {
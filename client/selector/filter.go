@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"github.com/micro/go-micro/v2/health"
 	"github.com/micro/go-micro/v2/registry"
 )
 
@@ -23,6 +24,18 @@ func FilterEndpoint(name string) Filter {
 	}
 }
 
+// FilterHealthy is a health based Select Filter which will
+// only return the nodes which haven't published a failing status.
+// Nodes which crashed without publishing one are only left out by the
+// registry selector when it's given a health check to run on the nodes.
+func FilterHealthy() Filter {
+	return func(old []*registry.Service) []*registry.Service {
+		return health.Filter(old, func(service string, node *registry.Node) bool {
+			return health.Healthy(node)
+		})
+	}
+}
+
 // FilterLabel is a label based Select Filter which will
 // only return services with the label specified.
 func FilterLabel(key, val string) Filter {
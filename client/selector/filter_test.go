@@ -237,3 +237,31 @@ func TestFilterVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterHealthy(t *testing.T) {
+	services := []*registry.Service{
+		{
+			Name:    "test",
+			Version: "1.0.0",
+			Nodes: []*registry.Node{
+				{Id: "test-1"},
+				{Id: "test-2", Metadata: map[string]string{"health": "failing"}},
+			},
+		},
+		{
+			Name:    "test",
+			Version: "1.1.0",
+			Nodes: []*registry.Node{
+				{Id: "test-3", Metadata: map[string]string{"health": "failing"}},
+			},
+		},
+	}
+
+	filtered := FilterHealthy()(services)
+	if len(filtered) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(filtered))
+	}
+	if len(filtered[0].Nodes) != 1 || filtered[0].Nodes[0].Id != "test-1" {
+		t.Fatalf("Expected node test-1, got %+v", filtered[0].Nodes)
+	}
+}
@@ -0,0 +1,164 @@
+package selector
+
+import (
+	"hash/crc32"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+var (
+	// DefaultReplicas is the number of virtual nodes per node on the hash ring
+	DefaultReplicas = 100
+	// DefaultLoadFactor bounds the outstanding requests of a node to this
+	// multiple of the average, beyond which keys spill over to the next node
+	DefaultLoadFactor = 1.25
+
+	// rings caches the hash ring of each node set
+	rings = &hashRings{rings: make(map[string]*hashRing)}
+)
+
+// maxRings bounds the number of node sets a ring is cached for
+const maxRings = 64
+
+type hashRings struct {
+	sync.Mutex
+	rings map[string]*hashRing
+}
+
+// hashRing places virtual nodes for every node on a ring of hashes
+type hashRing struct {
+	hashes []uint32
+	owners map[uint32]*registry.Node
+	nodes  []*registry.Node
+}
+
+// ConsistentHash is a strategy algorithm which routes requests with
+// the same key to the same node. As nodes come and go only the keys
+// of those nodes move. Nodes with far more outstanding requests than
+// the average are skipped to bound their load.
+func ConsistentHash(key string) Strategy {
+	return func(services []*registry.Service) Next {
+		ring := rings.get(services)
+		h := crc32.ChecksumIEEE([]byte(key))
+
+		// each call returns the next distinct node
+		// on the ring so retries go elsewhere
+		var mtx sync.Mutex
+		var attempt int
+
+		return func() (*registry.Node, error) {
+			mtx.Lock()
+			skip := attempt
+			attempt++
+			mtx.Unlock()
+
+			return ring.lookup(h, skip, DefaultStats)
+		}
+	}
+}
+
+// get returns the ring for the services, reusing the ring built
+// before for the same set of nodes. Filtered subsets of a service
+// e.g by version or circuit state are distinct sets with their own ring.
+func (r *hashRings) get(services []*registry.Service) *hashRing {
+	var keys []string
+	var nodes []*registry.Node
+
+	for _, service := range services {
+		for _, node := range service.Nodes {
+			keys = append(keys, statsKey(node)+"@"+node.Address)
+			nodes = append(nodes, node)
+		}
+	}
+
+	sort.Strings(keys)
+	signature := strings.Join(keys, ",")
+
+	r.Lock()
+	defer r.Unlock()
+
+	if ring, ok := r.rings[signature]; ok {
+		return ring
+	}
+
+	// drop the rings of old node sets rather than growing without bound
+	if len(r.rings) >= maxRings {
+		r.rings = make(map[string]*hashRing)
+	}
+
+	ring := newHashRing(nodes, DefaultReplicas)
+	r.rings[signature] = ring
+	return ring
+}
+
+func newHashRing(nodes []*registry.Node, replicas int) *hashRing {
+	ring := &hashRing{
+		owners: make(map[uint32]*registry.Node, len(nodes)*replicas),
+		nodes:  nodes,
+	}
+
+	for _, node := range nodes {
+		for i := 0; i < replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(statsKey(node) + "#" + strconv.Itoa(i)))
+			// the first node wins in the rare event of a collision
+			if _, ok := ring.owners[h]; ok {
+				continue
+			}
+			ring.owners[h] = node
+			ring.hashes = append(ring.hashes, h)
+		}
+	}
+
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+	return ring
+}
+
+// lookup walks the ring clockwise from the hash, skipping the first
+// skip distinct nodes and any node which is over its load bound
+func (r *hashRing) lookup(h uint32, skip int, stats *Stats) (*registry.Node, error) {
+	if len(r.hashes) == 0 {
+		return nil, ErrNoneAvailable
+	}
+
+	// the bound on the outstanding requests of a node
+	var total int64
+	for _, node := range r.nodes {
+		total += stats.Inflight(node)
+	}
+	bound := int64(math.Ceil(float64(total+1) / float64(len(r.nodes)) * DefaultLoadFactor))
+
+	start := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	seen := make(map[string]bool, len(r.nodes))
+	var overloaded []*registry.Node
+
+	for i := 0; i < len(r.hashes) && len(seen) < len(r.nodes); i++ {
+		node := r.owners[r.hashes[(start+i)%len(r.hashes)]]
+		id := statsKey(node)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if stats.Inflight(node) >= bound {
+			overloaded = append(overloaded, node)
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		return node, nil
+	}
+
+	// every node is busy, fall back to ring order
+	if len(overloaded) > 0 {
+		return overloaded[skip%len(overloaded)], nil
+	}
+
+	return r.owners[r.hashes[start%len(r.hashes)]], nil
+}
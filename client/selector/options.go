@@ -58,3 +58,9 @@ func WithStrategy(fn Strategy) SelectOption {
 		o.Strategy = fn
 	}
 }
+
+// WithHashKey routes the request with consistent hashing on the key,
+// so that requests with the same key are sent to the same node
+func WithHashKey(key string) SelectOption {
+	return WithStrategy(ConsistentHash(key))
+}
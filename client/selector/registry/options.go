@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/micro/go-micro/v2/client/selector"
+	"github.com/micro/go-micro/v2/health"
+	"github.com/micro/go-micro/v2/registry/cache"
 )
 
 // Set the registry cache ttl
@@ -16,3 +18,35 @@ func TTL(t time.Duration) selector.Option {
 		o.Context = context.WithValue(o.Context, "selector_ttl", t)
 	}
 }
+
+// Set the health check of the nodes in the registry cache, e.g health/rpc.NewCheck.
+// The nodes are only checked actively with one, there's no check by default.
+func HealthCheck(c health.Check) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, "selector_health_check", c)
+	}
+}
+
+// Set the interval on which the nodes in the registry cache are checked
+func HealthInterval(t time.Duration) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, "selector_health_interval", t)
+	}
+}
+
+// Set the snapshot the registry cache saves the services in, to resolve
+// them when the registry can't be reached
+func Snapshot(s cache.Snapshot) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, "selector_snapshot", s)
+	}
+}
@@ -0,0 +1,177 @@
+package selector
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+var (
+	// DefaultStats is used by the load aware strategies. It's fed
+	// call timings by the client and failures by Selector.Mark.
+	DefaultStats = NewStats()
+
+	// DefaultDecay is the time over which latency samples decay
+	DefaultDecay = 10 * time.Second
+	// DefaultPenalty is the latency recorded for a failed call
+	DefaultPenalty = time.Second
+)
+
+// Stats tracks the outstanding requests and latency of nodes
+type Stats struct {
+	decay   time.Duration
+	penalty time.Duration
+
+	sync.Mutex
+	nodes map[string]*nodeStats
+	calls int
+}
+
+type nodeStats struct {
+	// number of outstanding requests
+	inflight int64
+	// exponentially weighted moving average of latency in nanoseconds
+	ewma float64
+	// time of the last sample
+	last time.Time
+}
+
+// NewStats returns an empty node stats tracker
+func NewStats() *Stats {
+	return &Stats{
+		decay:   DefaultDecay,
+		penalty: DefaultPenalty,
+		nodes:   make(map[string]*nodeStats),
+	}
+}
+
+func statsKey(node *registry.Node) string {
+	if len(node.Id) > 0 {
+		return node.Id
+	}
+	return node.Address
+}
+
+// get returns the stats of a node. Must be called with the lock held.
+func (s *Stats) get(node *registry.Node) *nodeStats {
+	k := statsKey(node)
+	n, ok := s.nodes[k]
+	if !ok {
+		n = &nodeStats{last: time.Now()}
+		s.nodes[k] = n
+	}
+	return n
+}
+
+// observe records a latency sample, moving straight to a peak and
+// decaying towards lower values. Must be called with the lock held.
+func (s *Stats) observe(n *nodeStats, rtt time.Duration) {
+	now := time.Now()
+	sample := float64(rtt)
+
+	if sample > n.ewma {
+		n.ewma = sample
+	} else {
+		w := math.Exp(-float64(now.Sub(n.last)) / float64(s.decay))
+		n.ewma = n.ewma*w + sample*(1-w)
+	}
+
+	n.last = now
+}
+
+// prune drops idle nodes which have likely left the registry.
+// Must be called with the lock held.
+func (s *Stats) prune() {
+	s.calls++
+	if s.calls%1000 != 0 {
+		return
+	}
+
+	for k, n := range s.nodes {
+		if n.inflight == 0 && time.Since(n.last) > 10*s.decay {
+			delete(s.nodes, k)
+		}
+	}
+}
+
+// Start records the start of a request to the node
+func (s *Stats) Start(node *registry.Node) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.prune()
+	s.get(node).inflight++
+}
+
+// Done records the completion of a request started with Start
+func (s *Stats) Done(node *registry.Node, d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	n := s.get(node)
+	if n.inflight > 0 {
+		n.inflight--
+	}
+	s.observe(n, d)
+}
+
+// Mark records the result of a request, a failure is
+// counted as a slow response to steer traffic away
+func (s *Stats) Mark(node *registry.Node, err error) {
+	if err == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.observe(s.get(node), s.penalty)
+}
+
+// Inflight returns the number of outstanding requests to the node
+func (s *Stats) Inflight(node *registry.Node) int64 {
+	s.Lock()
+	defer s.Unlock()
+
+	if n, ok := s.nodes[statsKey(node)]; ok {
+		return n.inflight
+	}
+	return 0
+}
+
+// Latency returns the peak exponentially weighted moving average of the node's latency
+func (s *Stats) Latency(node *registry.Node) time.Duration {
+	s.Lock()
+	defer s.Unlock()
+
+	if n, ok := s.nodes[statsKey(node)]; ok {
+		return time.Duration(n.ewma)
+	}
+	return 0
+}
+
+// Cost returns the expected cost of sending a request to the node,
+// its latency weighted by the number of outstanding requests
+func (s *Stats) Cost(node *registry.Node) float64 {
+	s.Lock()
+	defer s.Unlock()
+
+	n, ok := s.nodes[statsKey(node)]
+	if !ok {
+		return 0
+	}
+
+	// decay the average when there are no recent samples so that a
+	// once slow node gets retried eventually
+	w := math.Exp(-float64(time.Since(n.last)) / float64(s.decay))
+	ewma := n.ewma * w
+
+	// nodes without samples are penalised if busy
+	if ewma == 0 && n.inflight > 0 {
+		ewma = float64(s.penalty)
+	}
+
+	return ewma * float64(n.inflight+1)
+}
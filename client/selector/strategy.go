@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"math"
 	"math/rand"
 	"sync"
 	"time"
@@ -54,3 +55,99 @@ func RoundRobin(services []*registry.Service) Next {
 		return node, nil
 	}
 }
+
+// LeastRequests is a strategy algorithm which picks the node with the
+// fewest outstanding requests, breaking ties at random
+func LeastRequests(services []*registry.Service) Next {
+	return leastRequests(DefaultStats, services)
+}
+
+// PeakEWMA is a strategy algorithm which picks the node with the lowest
+// peak exponentially weighted moving average latency, weighted by the
+// number of outstanding requests
+func PeakEWMA(services []*registry.Service) Next {
+	return peakEWMA(DefaultStats, services)
+}
+
+// PowerOfTwoChoices is a strategy algorithm which picks two nodes at
+// random and uses the one with the lowest peak EWMA cost
+func PowerOfTwoChoices(services []*registry.Service) Next {
+	return powerOfTwoChoices(DefaultStats, services)
+}
+
+func leastRequests(stats *Stats, services []*registry.Service) Next {
+	nodes := make([]*registry.Node, 0, len(services))
+
+	for _, service := range services {
+		nodes = append(nodes, service.Nodes...)
+	}
+
+	return func() (*registry.Node, error) {
+		return least(nodes, func(n *registry.Node) float64 {
+			return float64(stats.Inflight(n))
+		})
+	}
+}
+
+func peakEWMA(stats *Stats, services []*registry.Service) Next {
+	nodes := make([]*registry.Node, 0, len(services))
+
+	for _, service := range services {
+		nodes = append(nodes, service.Nodes...)
+	}
+
+	return func() (*registry.Node, error) {
+		return least(nodes, stats.Cost)
+	}
+}
+
+func powerOfTwoChoices(stats *Stats, services []*registry.Service) Next {
+	nodes := make([]*registry.Node, 0, len(services))
+
+	for _, service := range services {
+		nodes = append(nodes, service.Nodes...)
+	}
+
+	return func() (*registry.Node, error) {
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+		if len(nodes) == 1 {
+			return nodes[0], nil
+		}
+
+		i := rand.Intn(len(nodes))
+		j := rand.Intn(len(nodes) - 1)
+		if j >= i {
+			j++
+		}
+
+		if stats.Cost(nodes[j]) < stats.Cost(nodes[i]) {
+			return nodes[j], nil
+		}
+		return nodes[i], nil
+	}
+}
+
+// least returns the node with the lowest cost, ties are broken at random
+func least(nodes []*registry.Node, cost func(*registry.Node) float64) (*registry.Node, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoneAvailable
+	}
+
+	var best []*registry.Node
+	min := math.MaxFloat64
+
+	for _, node := range nodes {
+		c := cost(node)
+		switch {
+		case c < min:
+			min = c
+			best = append(best[:0], node)
+		case c == min:
+			best = append(best, node)
+		}
+	}
+
+	return best[rand.Intn(len(best))], nil
+}
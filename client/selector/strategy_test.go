@@ -1,8 +1,11 @@
 package selector
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/micro/go-micro/v2/registry"
 )
@@ -56,3 +59,180 @@ func TestStrategies(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadAwareStrategies(t *testing.T) {
+	fast := &registry.Node{Id: "fast", Address: "10.0.0.1:1001"}
+	slow := &registry.Node{Id: "slow", Address: "10.0.0.2:1002"}
+	testData := []*registry.Service{
+		{
+			Name:  "test1",
+			Nodes: []*registry.Node{fast, slow},
+		},
+	}
+
+	stats := NewStats()
+
+	// slow has an outstanding request
+	stats.Start(slow)
+
+	node, err := leastRequests(stats, testData)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Id != fast.Id {
+		t.Fatalf("Expected least requests to pick %s, got %s", fast.Id, node.Id)
+	}
+
+	stats.Done(slow, 100*time.Millisecond)
+	stats.Start(fast)
+	stats.Done(fast, time.Millisecond)
+
+	for name, strategy := range map[string]func(*Stats, []*registry.Service) Next{
+		"peakewma":   peakEWMA,
+		"poweroftwo": powerOfTwoChoices,
+	} {
+		next := strategy(stats, testData)
+		for i := 0; i < 10; i++ {
+			node, err := next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if node.Id != fast.Id {
+				t.Fatalf("Expected %s to pick %s, got %s", name, fast.Id, node.Id)
+			}
+		}
+	}
+
+	// failures make a node look slow
+	stats.Mark(fast, errors.New("failed"))
+	if stats.Latency(fast) != DefaultPenalty {
+		t.Fatalf("Expected failure to be recorded as %v, got %v", DefaultPenalty, stats.Latency(fast))
+	}
+
+	if _, err := peakEWMA(stats, nil)(); err != ErrNoneAvailable {
+		t.Fatalf("Expected %v, got %v", ErrNoneAvailable, err)
+	}
+}
+
+func TestConsistentHash(t *testing.T) {
+	var nodes []*registry.Node
+	for i := 0; i < 5; i++ {
+		nodes = append(nodes, &registry.Node{
+			Id:      fmt.Sprintf("hash-%d", i),
+			Address: fmt.Sprintf("10.0.0.%d:1001", i),
+		})
+	}
+
+	services := func(nodes []*registry.Node) []*registry.Service {
+		return []*registry.Service{{Name: "hash", Nodes: nodes}}
+	}
+
+	pick := func(key string, nodes []*registry.Node) string {
+		node, err := ConsistentHash(key)(services(nodes))()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return node.Id
+	}
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		before[key] = pick(key, nodes)
+
+		if id := pick(key, nodes); id != before[key] {
+			t.Fatalf("Expected key %s to stick to %s, got %s", key, before[key], id)
+		}
+	}
+
+	// removing a node only moves the keys it owned
+	removed := nodes[2].Id
+	var moved int
+	for key, id := range before {
+		after := pick(key, append(nodes[:2:2], nodes[3:]...))
+		if id != removed && after != id {
+			t.Fatalf("Expected key %s to stay on %s, moved to %s", key, id, after)
+		}
+		if id == removed {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Fatal("Expected some keys to be owned by the removed node")
+	}
+
+	// retries go to a different node
+	next := ConsistentHash("user-1")(services(nodes))
+	first, _ := next()
+	second, _ := next()
+	if first.Id == second.Id {
+		t.Fatalf("Expected retry to pick a different node than %s", first.Id)
+	}
+
+	// a busy node sheds its keys to the next node on the ring
+	owner := nodes[0]
+	for _, n := range nodes {
+		if n.Id == before["user-1"] {
+			owner = n
+		}
+	}
+	for i := 0; i < 10; i++ {
+		DefaultStats.Start(owner)
+	}
+	defer func() {
+		for i := 0; i < 10; i++ {
+			DefaultStats.Done(owner, 0)
+		}
+	}()
+	if id := pick("user-1", nodes); id == owner.Id {
+		t.Fatalf("Expected overloaded node %s to be skipped", owner.Id)
+	}
+}
+
+func TestConsistentHashNodeSets(t *testing.T) {
+	var nodes []*registry.Node
+	for i := 0; i < 4; i++ {
+		// nodes without ids are told apart by address
+		nodes = append(nodes, &registry.Node{Address: fmt.Sprintf("10.0.1.%d:1001", i)})
+	}
+
+	services := func(nodes []*registry.Node) []*registry.Service {
+		return []*registry.Service{{Name: "sets", Nodes: nodes}}
+	}
+
+	// subsets of the same service each get their own ring
+	subset := nodes[:2]
+	all := rings.get(services(nodes))
+	part := rings.get(services(subset))
+	if all == part {
+		t.Fatal("Expected a ring per node set")
+	}
+	if rings.get(services(nodes)) != all || rings.get(services(subset)) != part {
+		t.Fatal("Expected the ring of a node set to be reused")
+	}
+
+	// every node is reachable even without an id
+	next := ConsistentHash("key")(services(nodes))
+	seen := make(map[string]bool)
+	for i := 0; i < len(nodes); i++ {
+		node, err := next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[node.Address] = true
+	}
+	if len(seen) != len(nodes) {
+		t.Fatalf("Expected retries to reach %d nodes, got %d", len(nodes), len(seen))
+	}
+
+	// a subset only ever picks its own nodes
+	for i := 0; i < 100; i++ {
+		node, err := ConsistentHash(fmt.Sprintf("user-%d", i))(services(subset))()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node != subset[0] && node != subset[1] {
+			t.Fatalf("Expected a node of the subset, got %s", node.Address)
+		}
+	}
+}
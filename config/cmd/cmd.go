@@ -45,9 +45,12 @@ import (
 	"github.com/micro/go-micro/v2/broker/memory"
 	"github.com/micro/go-micro/v2/broker/nats"
 	brokerSrv "github.com/micro/go-micro/v2/broker/service"
+	brokerStore "github.com/micro/go-micro/v2/broker/store"
 
 	// registries
 	"github.com/micro/go-micro/v2/registry/etcd"
+	fReg "github.com/micro/go-micro/v2/registry/file"
+	kReg "github.com/micro/go-micro/v2/registry/kubernetes"
 	"github.com/micro/go-micro/v2/registry/mdns"
 	rmem "github.com/micro/go-micro/v2/registry/memory"
 	regSrv "github.com/micro/go-micro/v2/registry/service"
@@ -73,6 +76,7 @@ import (
 	// tracers
 	// jTracer "github.com/micro/go-micro/v2/debug/trace/jaeger"
 	memTracer "github.com/micro/go-micro/v2/debug/trace/memory"
+	otlpTracer "github.com/micro/go-micro/v2/debug/trace/otlp"
 
 	// auth
 	jwtAuth "github.com/micro/go-micro/v2/auth/jwt"
@@ -181,7 +185,7 @@ var (
 		&cli.StringFlag{
 			Name:    "broker",
 			EnvVars: []string{"MICRO_BROKER"},
-			Usage:   "Broker for pub/sub. http, nats, rabbitmq",
+			Usage:   "Broker for pub/sub. http, nats, rabbitmq, store",
 		},
 		&cli.StringFlag{
 			Name:    "broker_address",
@@ -196,7 +200,7 @@ var (
 		&cli.StringFlag{
 			Name:    "registry",
 			EnvVars: []string{"MICRO_REGISTRY"},
-			Usage:   "Registry for discovery. etcd, mdns",
+			Usage:   "Registry for discovery. etcd, mdns, kubernetes, file",
 		},
 		&cli.StringFlag{
 			Name:    "registry_address",
@@ -253,7 +257,7 @@ var (
 		&cli.StringFlag{
 			Name:    "tracer",
 			EnvVars: []string{"MICRO_TRACER"},
-			Usage:   "Tracer for distributed tracing, e.g. memory, jaeger",
+			Usage:   "Tracer for distributed tracing, e.g. memory, otlp, jaeger",
 		},
 		&cli.StringFlag{
 			Name:    "tracer_address",
@@ -333,6 +337,7 @@ var (
 		"memory":  memory.NewBroker,
 		"nats":    nats.NewBroker,
 		"http":    brokerHttp.NewBroker,
+		"store":   brokerStore.NewBroker,
 	}
 
 	DefaultClients = map[string]func(...client.Option) client.Client{
@@ -341,10 +346,12 @@ var (
 	}
 
 	DefaultRegistries = map[string]func(...registry.Option) registry.Registry{
-		"service": regSrv.NewRegistry,
-		"etcd":    etcd.NewRegistry,
-		"mdns":    mdns.NewRegistry,
-		"memory":  rmem.NewRegistry,
+		"service":    regSrv.NewRegistry,
+		"etcd":       etcd.NewRegistry,
+		"file":       fReg.NewRegistry,
+		"mdns":       mdns.NewRegistry,
+		"memory":     rmem.NewRegistry,
+		"kubernetes": kReg.NewRegistry,
 	}
 
 	DefaultSelectors = map[string]func(...selector.Option) selector.Selector{
@@ -376,6 +383,7 @@ var (
 
 	DefaultTracers = map[string]func(...trace.Option) trace.Tracer{
 		"memory": memTracer.NewTracer,
+		"otlp":   otlpTracer.NewTracer,
 		// "jaeger": jTracer.NewTracer,
 	}
 
@@ -507,7 +515,13 @@ func (c *cmd) Before(ctx *cli.Context) error {
 			return fmt.Errorf("Unsupported tracer: %s", name)
 		}
 
-		*c.opts.Tracer = r()
+		var tracerOpts []trace.Option
+		// the tracers send the spans to a single address
+		if addrs := ctx.String("tracer_address"); len(addrs) > 0 {
+			tracerOpts = append(tracerOpts, trace.WithAddress(strings.Split(addrs, ",")[0]))
+		}
+
+		*c.opts.Tracer = r(tracerOpts...)
 	}
 
 	// Set the client
@@ -625,7 +639,13 @@ func (c *cmd) Before(ctx *cli.Context) error {
 			return fmt.Errorf("Broker %s not found", name)
 		}
 
-		*c.opts.Broker = b()
+		// the store broker persists messages to the store of the service
+		var bopts []broker.Option
+		if name == "store" {
+			bopts = append(bopts, brokerStore.Store(*c.opts.Store))
+		}
+
+		*c.opts.Broker = b(bopts...)
 		serverOpts = append(serverOpts, server.Broker(*c.opts.Broker))
 		clientOpts = append(clientOpts, client.Broker(*c.opts.Broker))
 	}
@@ -0,0 +1,236 @@
+// Package metrics provides counters and histograms exposed in the Prometheus text format
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// DefaultRegistry is used by the client, server and broker wrappers
+	DefaultRegistry = NewRegistry()
+
+	// DefaultBuckets of latency histograms in seconds
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// Registry holds a set of metrics, it serves them over http in the Prometheus text format
+type Registry struct {
+	sync.Mutex
+	metrics map[string]metric
+}
+
+type metric interface {
+	// write the metric in the text format
+	write(b *strings.Builder)
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]metric),
+	}
+}
+
+// Counter returns the counter of the name, creating it if it doesn't exist.
+// It panics if the name is registered as another type of metric.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	r.Lock()
+	defer r.Unlock()
+
+	if m, ok := r.metrics[name]; ok {
+		c, ok := m.(*Counter)
+		if !ok {
+			panic(fmt.Sprintf("metrics: %s is not a counter", name))
+		}
+		return c
+	}
+
+	c := &Counter{
+		desc:   newDesc(name, help, labels),
+		values: make(map[string]*counterValue),
+	}
+	r.metrics[name] = c
+	return c
+}
+
+// Histogram returns the histogram of the name, creating it if it doesn't
+// exist. It panics if the name is registered as another type of metric.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	r.Lock()
+	defer r.Unlock()
+
+	if m, ok := r.metrics[name]; ok {
+		h, ok := m.(*Histogram)
+		if !ok {
+			panic(fmt.Sprintf("metrics: %s is not a histogram", name))
+		}
+		return h
+	}
+
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+
+	h := &Histogram{
+		desc:    newDesc(name, help, labels),
+		buckets: b,
+		values:  make(map[string]*histogramValue),
+	}
+	r.metrics[name] = h
+	return h
+}
+
+// String returns the metrics in the Prometheus text format
+func (r *Registry) String() string {
+	r.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.Unlock()
+
+	var b strings.Builder
+	for _, m := range metrics {
+		m.write(&b)
+	}
+	return b.String()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(r.String()))
+}
+
+// desc describes a metric and its labels
+type desc struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func newDesc(name, help string, labels []string) desc {
+	return desc{
+		name:   name,
+		help:   help,
+		labels: labels,
+	}
+}
+
+// key joins the label values into a series key
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// Counter is a monotonically increasing value per set of label values
+type Counter struct {
+	desc
+
+	sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labels []string
+	value  float64
+}
+
+// Inc increments the counter of the label values
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds to the counter of the label values, v must not be negative
+func (c *Counter) Add(v float64, values ...string) {
+	k := c.key(values)
+
+	c.Lock()
+	defer c.Unlock()
+
+	cv, ok := c.values[k]
+	if !ok {
+		cv = &counterValue{labels: append([]string(nil), values...)}
+		c.values[k] = cv
+	}
+	cv.value += v
+}
+
+// Value returns the counter of the label values
+func (c *Counter) Value(values ...string) float64 {
+	k := c.key(values)
+
+	c.Lock()
+	defer c.Unlock()
+
+	if cv, ok := c.values[k]; ok {
+		return cv.value
+	}
+	return 0
+}
+
+// Histogram counts observations in buckets per set of label values
+type Histogram struct {
+	desc
+	// upper bounds of the buckets
+	buckets []float64
+
+	sync.Mutex
+	values map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labels []string
+	// counts per bucket, not cumulative
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Observe records a value for the label values
+func (h *Histogram) Observe(v float64, values ...string) {
+	k := h.key(values)
+
+	h.Lock()
+	defer h.Unlock()
+
+	hv, ok := h.values[k]
+	if !ok {
+		hv = &histogramValue{
+			labels: append([]string(nil), values...),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.values[k] = hv
+	}
+
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		hv.counts[i]++
+	}
+	hv.count++
+	hv.sum += v
+}
+
+// Count returns the number of observations of the label values
+func (h *Histogram) Count(values ...string) uint64 {
+	k := h.key(values)
+
+	h.Lock()
+	defer h.Unlock()
+
+	if hv, ok := h.values[k]; ok {
+		return hv.count
+	}
+	return 0
+}
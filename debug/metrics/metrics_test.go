@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("requests_total", "Total number of requests", "service", "code")
+	c.Inc("foo", "200")
+	c.Add(2, "foo", "200")
+	c.Inc("bar", "500")
+
+	if v := c.Value("foo", "200"); v != 3 {
+		t.Fatalf("expected 3 got %v", v)
+	}
+
+	// the same counter is returned
+	if r.Counter("requests_total", "") != c {
+		t.Fatal("expected the registered counter")
+	}
+
+	expect := `# HELP requests_total Total number of requests
+# TYPE requests_total counter
+requests_total{service="bar",code="500"} 1
+requests_total{service="foo",code="200"} 3
+`
+	if v := r.String(); v != expect {
+		t.Fatalf("expected\n%s\ngot\n%s", expect, v)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.Histogram("duration_seconds", "Latency", []float64{1, 0.1}, "endpoint")
+	h.Observe(0.05, "Foo.Bar")
+	h.Observe(0.5, "Foo.Bar")
+	h.Observe(5, "Foo.Bar")
+
+	if v := h.Count("Foo.Bar"); v != 3 {
+		t.Fatalf("expected 3 got %d", v)
+	}
+
+	expect := `# HELP duration_seconds Latency
+# TYPE duration_seconds histogram
+duration_seconds_bucket{endpoint="Foo.Bar",le="0.1"} 1
+duration_seconds_bucket{endpoint="Foo.Bar",le="1"} 2
+duration_seconds_bucket{endpoint="Foo.Bar",le="+Inf"} 3
+duration_seconds_sum{endpoint="Foo.Bar"} 5.55
+duration_seconds_count{endpoint="Foo.Bar"} 3
+`
+	if v := r.String(); v != expect {
+		t.Fatalf("expected\n%s\ngot\n%s", expect, v)
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("total", "a \\ help\ntext", "label").Inc("a \"quoted\"\nvalue")
+
+	v := r.String()
+	if !strings.Contains(v, `# HELP total a \\ help\ntext`) {
+		t.Fatalf("help not escaped %s", v)
+	}
+	if !strings.Contains(v, `total{label="a \"quoted\"\nvalue"} 1`) {
+		t.Fatalf("label not escaped %s", v)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("total", "", "label").Inc("value")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %s", ct)
+	}
+	if body := w.Body.String(); body != "# TYPE total counter\ntotal{label=\"value\"} 1\n" {
+		t.Fatalf("unexpected body %s", body)
+	}
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The Prometheus text exposition format, see
+// https://prometheus.io/docs/instrumenting/exposition_formats/
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+func (d desc) writeHeader(b *strings.Builder, typ string) {
+	if len(d.help) > 0 {
+		b.WriteString("# HELP " + d.name + " " + helpEscaper.Replace(d.help) + "\n")
+	}
+	b.WriteString("# TYPE " + d.name + " " + typ + "\n")
+}
+
+// writeSample writes a sample with the label values and any extra label
+func (d desc) writeSample(b *strings.Builder, suffix string, values []string, extra, extraValue string, v string) {
+	b.WriteString(d.name + suffix)
+
+	if len(values) > 0 || len(extra) > 0 {
+		b.WriteString("{")
+		for i, l := range d.labels {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(l + `="` + labelEscaper.Replace(values[i]) + `"`)
+		}
+		if len(extra) > 0 {
+			if len(values) > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(extra + `="` + extraValue + `"`)
+		}
+		b.WriteString("}")
+	}
+
+	b.WriteString(" " + v + "\n")
+}
+
+func (c *Counter) write(b *strings.Builder) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.writeHeader(b, "counter")
+
+	// sort the series so the output is stable
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		cv := c.values[k]
+		c.writeSample(b, "", cv.labels, "", "", formatFloat(cv.value))
+	}
+}
+
+func (h *Histogram) write(b *strings.Builder) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.writeHeader(b, "histogram")
+
+	// sort the series so the output is stable
+	keys := make([]string, 0, len(h.values))
+	for k := range h.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		hv := h.values[k]
+
+		// buckets are cumulative
+		var count uint64
+		for i, le := range h.buckets {
+			count += hv.counts[i]
+			h.writeSample(b, "_bucket", hv.labels, "le", formatFloat(le), strconv.FormatUint(count, 10))
+		}
+		h.writeSample(b, "_bucket", hv.labels, "le", "+Inf", strconv.FormatUint(hv.count, 10))
+		h.writeSample(b, "_sum", hv.labels, "", "", formatFloat(hv.sum))
+		h.writeSample(b, "_count", hv.labels, "", "", strconv.FormatUint(hv.count, 10))
+	}
+}
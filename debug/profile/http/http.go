@@ -7,6 +7,7 @@ import (
 	"net/http/pprof"
 	"sync"
 
+	"github.com/micro/go-micro/v2/debug/metrics"
 	"github.com/micro/go-micro/v2/debug/profile"
 )
 
@@ -69,6 +70,9 @@ func NewProfile(opts ...profile.Option) profile.Profile {
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
+	// metrics in the prometheus text format
+	mux.Handle("/metrics", metrics.DefaultRegistry)
+
 	return &httpProfile{
 		server: &http.Server{
 			Addr:    DefaultAddress,
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/client/breaker"
 	"github.com/micro/go-micro/v2/debug/log"
 	proto "github.com/micro/go-micro/v2/debug/service/proto"
 	"github.com/micro/go-micro/v2/debug/stats"
@@ -16,10 +17,11 @@ import (
 // NewHandler returns an instance of the Debug Handler
 func NewHandler(c client.Client) *Debug {
 	return &Debug{
-		log:   log.DefaultLog,
-		stats: stats.DefaultStats,
-		trace: trace.DefaultTracer,
-		cache: c.Options().Cache,
+		log:     log.DefaultLog,
+		stats:   stats.DefaultStats,
+		trace:   trace.DefaultTracer,
+		cache:   c.Options().Cache,
+		breaker: c.Options().Breaker,
 	}
 }
 
@@ -34,6 +36,8 @@ type Debug struct {
 	trace trace.Tracer
 	// the cache
 	cache *client.Cache
+	// the client circuit breaker
+	breaker breaker.Breaker
 }
 
 func (d *Debug) Health(ctx context.Context, req *proto.HealthRequest, rsp *proto.HealthResponse) error {
@@ -174,3 +178,24 @@ func (d *Debug) Cache(ctx context.Context, req *proto.CacheRequest, rsp *proto.C
 	rsp.Values = d.cache.List()
 	return nil
 }
+
+// Breaker returns the state of the client circuit breakers
+func (d *Debug) Breaker(ctx context.Context, req *proto.BreakerRequest, rsp *proto.BreakerResponse) error {
+	if d.breaker == nil {
+		return nil
+	}
+
+	for _, s := range d.breaker.Status(req.Service) {
+		rsp.Circuits = append(rsp.Circuits, &proto.Circuit{
+			Service:  s.Service,
+			Node:     s.Node,
+			Address:  s.Address,
+			State:    s.State.String(),
+			Requests: uint64(s.Requests),
+			Failures: uint64(s.Failures),
+			Updated:  s.Updated.Unix(),
+		})
+	}
+
+	return nil
+}
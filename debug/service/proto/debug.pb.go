@@ -652,6 +652,176 @@ func (m *CacheResponse) GetValues() map[string]string {
 	return nil
 }
 
+type BreakerRequest struct {
+	// optional service name
+	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BreakerRequest) Reset()         { *m = BreakerRequest{} }
+func (m *BreakerRequest) String() string { return proto.CompactTextString(m) }
+func (*BreakerRequest) ProtoMessage()    {}
+func (*BreakerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_df91f41a5db378e6, []int{11}
+}
+
+func (m *BreakerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakerRequest.Unmarshal(m, b)
+}
+func (m *BreakerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakerRequest.Marshal(b, m, deterministic)
+}
+func (m *BreakerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakerRequest.Merge(m, src)
+}
+func (m *BreakerRequest) XXX_Size() int {
+	return xxx_messageInfo_BreakerRequest.Size(m)
+}
+func (m *BreakerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BreakerRequest proto.InternalMessageInfo
+
+func (m *BreakerRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+type BreakerResponse struct {
+	Circuits             []*Circuit `protobuf:"bytes,1,rep,name=circuits,proto3" json:"circuits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *BreakerResponse) Reset()         { *m = BreakerResponse{} }
+func (m *BreakerResponse) String() string { return proto.CompactTextString(m) }
+func (*BreakerResponse) ProtoMessage()    {}
+func (*BreakerResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_df91f41a5db378e6, []int{12}
+}
+
+func (m *BreakerResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakerResponse.Unmarshal(m, b)
+}
+func (m *BreakerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakerResponse.Marshal(b, m, deterministic)
+}
+func (m *BreakerResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakerResponse.Merge(m, src)
+}
+func (m *BreakerResponse) XXX_Size() int {
+	return xxx_messageInfo_BreakerResponse.Size(m)
+}
+func (m *BreakerResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakerResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BreakerResponse proto.InternalMessageInfo
+
+func (m *BreakerResponse) GetCircuits() []*Circuit {
+	if m != nil {
+		return m.Circuits
+	}
+	return nil
+}
+
+// Circuit is the client circuit breaker state of a node
+type Circuit struct {
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Node    string `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	Address string `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	// closed, open or half-open
+	State string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	// requests and failures in the current window
+	Requests uint64 `protobuf:"varint,5,opt,name=requests,proto3" json:"requests,omitempty"`
+	Failures uint64 `protobuf:"varint,6,opt,name=failures,proto3" json:"failures,omitempty"`
+	// unix timestamp of the last state change
+	Updated              int64    `protobuf:"varint,7,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Circuit) Reset()         { *m = Circuit{} }
+func (m *Circuit) String() string { return proto.CompactTextString(m) }
+func (*Circuit) ProtoMessage()    {}
+func (*Circuit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_df91f41a5db378e6, []int{13}
+}
+
+func (m *Circuit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Circuit.Unmarshal(m, b)
+}
+func (m *Circuit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Circuit.Marshal(b, m, deterministic)
+}
+func (m *Circuit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Circuit.Merge(m, src)
+}
+func (m *Circuit) XXX_Size() int {
+	return xxx_messageInfo_Circuit.Size(m)
+}
+func (m *Circuit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Circuit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Circuit proto.InternalMessageInfo
+
+func (m *Circuit) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *Circuit) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *Circuit) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Circuit) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Circuit) GetRequests() uint64 {
+	if m != nil {
+		return m.Requests
+	}
+	return 0
+}
+
+func (m *Circuit) GetFailures() uint64 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *Circuit) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("SpanType", SpanType_name, SpanType_value)
 	proto.RegisterType((*HealthRequest)(nil), "HealthRequest")
@@ -668,53 +838,63 @@ func init() {
 	proto.RegisterType((*CacheRequest)(nil), "CacheRequest")
 	proto.RegisterType((*CacheResponse)(nil), "CacheResponse")
 	proto.RegisterMapType((map[string]string)(nil), "CacheResponse.ValuesEntry")
+	proto.RegisterType((*BreakerRequest)(nil), "BreakerRequest")
+	proto.RegisterType((*BreakerResponse)(nil), "BreakerResponse")
+	proto.RegisterType((*Circuit)(nil), "Circuit")
 }
 
 func init() { proto.RegisterFile("debug/service/proto/debug.proto", fileDescriptor_df91f41a5db378e6) }
 
 var fileDescriptor_df91f41a5db378e6 = []byte{
-	// 646 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdb, 0x6e, 0xd3, 0x4a,
-	0x14, 0x8d, 0xed, 0x38, 0xb1, 0x77, 0x62, 0x9f, 0x6a, 0xce, 0x45, 0x96, 0x0f, 0xd0, 0xca, 0x12,
-	0x52, 0xb8, 0x68, 0x02, 0xe1, 0x85, 0xcb, 0x1b, 0x14, 0x09, 0xa4, 0xd2, 0x4a, 0xd3, 0x96, 0xf7,
-	0xa9, 0x3d, 0x4a, 0x03, 0xf5, 0x85, 0x99, 0x71, 0xa5, 0xbc, 0xf0, 0x23, 0xfc, 0x04, 0xff, 0x82,
-	0xf8, 0x1f, 0x34, 0x17, 0xb7, 0xb6, 0x10, 0xaa, 0x10, 0x6f, 0x5e, 0x6b, 0xaf, 0xd9, 0xd9, 0x7b,
-	0x69, 0x65, 0xc3, 0x6e, 0xc1, 0xce, 0xda, 0xf5, 0x52, 0x30, 0x7e, 0xb9, 0xc9, 0xd9, 0xb2, 0xe1,
-	0xb5, 0xac, 0x97, 0x9a, 0xc3, 0xfa, 0x3b, 0xbb, 0x07, 0xd1, 0x1b, 0x46, 0x2f, 0xe4, 0x39, 0x61,
-	0x9f, 0x5a, 0x26, 0x24, 0x4a, 0x60, 0x6a, 0xd5, 0x89, 0xb3, 0xe7, 0x2c, 0x42, 0xd2, 0xc1, 0x6c,
-	0x01, 0x71, 0x27, 0x15, 0x4d, 0x5d, 0x09, 0x86, 0xfe, 0x83, 0x89, 0x90, 0x54, 0xb6, 0xc2, 0x4a,
-	0x2d, 0xca, 0x16, 0x30, 0x3f, 0x96, 0x54, 0x8a, 0x9b, 0x7b, 0x7e, 0x77, 0x20, 0xb2, 0x52, 0xdb,
-	0xf3, 0x16, 0x84, 0x72, 0x53, 0x32, 0x21, 0x69, 0xd9, 0x68, 0xf5, 0x98, 0x5c, 0x13, 0xba, 0x93,
-	0xa4, 0x5c, 0xb2, 0x22, 0x71, 0x75, 0xad, 0x83, 0x6a, 0x96, 0xb6, 0x51, 0xc2, 0xc4, 0xd3, 0x05,
-	0x8b, 0x14, 0x5f, 0xb2, 0xb2, 0xe6, 0xdb, 0x64, 0x6c, 0x78, 0x83, 0x54, 0x27, 0x79, 0xce, 0x19,
-	0x2d, 0x44, 0xe2, 0x9b, 0x4e, 0x16, 0xa2, 0x18, 0xdc, 0x75, 0x9e, 0x4c, 0x34, 0xe9, 0xae, 0x73,
-	0x94, 0x42, 0xc0, 0xcd, 0x22, 0x22, 0x99, 0x6a, 0xf6, 0x0a, 0xab, 0xee, 0x8c, 0xf3, 0x9a, 0x8b,
-	0x24, 0x30, 0xdd, 0x0d, 0xca, 0x3e, 0x00, 0x1c, 0xd4, 0xeb, 0x1b, 0xf7, 0x37, 0x0e, 0x72, 0x46,
-	0x4b, 0xbd, 0x4e, 0x40, 0x2c, 0x42, 0xff, 0x80, 0x9f, 0xd7, 0x6d, 0x25, 0xf5, 0x32, 0x1e, 0x31,
-	0x40, 0xb1, 0x62, 0x53, 0xe5, 0x4c, 0xaf, 0xe2, 0x11, 0x03, 0xb2, 0xaf, 0x0e, 0x4c, 0x08, 0xcb,
-	0x6b, 0x5e, 0xfc, 0x6c, 0x9e, 0xd7, 0x37, 0xef, 0x31, 0x04, 0x25, 0x93, 0xb4, 0xa0, 0x92, 0x26,
-	0xee, 0x9e, 0xb7, 0x98, 0xad, 0xfe, 0xc5, 0xe6, 0x21, 0x7e, 0x67, 0xf9, 0xd7, 0x95, 0xe4, 0x5b,
-	0x72, 0x25, 0x53, 0x93, 0x97, 0x4c, 0x08, 0xba, 0x36, 0xb6, 0x86, 0xa4, 0x83, 0xe9, 0x0b, 0x88,
-	0x06, 0x8f, 0xd0, 0x0e, 0x78, 0x1f, 0xd9, 0xd6, 0x2e, 0xa8, 0x3e, 0xd5, 0xb8, 0x97, 0xf4, 0xa2,
-	0x65, 0x7a, 0xb7, 0x90, 0x18, 0xf0, 0xdc, 0x7d, 0xea, 0x64, 0x77, 0x60, 0x7e, 0xc2, 0x69, 0xce,
-	0x3a, 0x83, 0x62, 0x70, 0x37, 0x85, 0x7d, 0xea, 0x6e, 0x8a, 0xec, 0x21, 0x44, 0xb6, 0x6e, 0x53,
-	0xf1, 0x3f, 0xf8, 0xa2, 0xa1, 0x95, 0x0a, 0x9a, 0x9a, 0xdb, 0xc7, 0xc7, 0x0d, 0xad, 0x88, 0xe1,
-	0xb2, 0x2f, 0x2e, 0x8c, 0x15, 0x56, 0x3f, 0x28, 0xd5, 0x33, 0xdb, 0xc9, 0x00, 0xdb, 0xdc, 0xed,
-	0x9a, 0x2b, 0xcf, 0x1b, 0xca, 0x99, 0x35, 0x37, 0x24, 0x16, 0x21, 0x04, 0xe3, 0x8a, 0x96, 0xc6,
-	0xdc, 0x90, 0xe8, 0xef, 0x7e, 0xde, 0xfc, 0x61, 0xde, 0x52, 0x08, 0x8a, 0x96, 0x53, 0xb9, 0xa9,
-	0x2b, 0x9b, 0x95, 0x2b, 0x8c, 0x96, 0x3d, 0xa3, 0xa7, 0x7a, 0xe0, 0xbf, 0xf5, 0xc0, 0xbf, 0xb4,
-	0xf9, 0x36, 0x8c, 0xe5, 0xb6, 0x61, 0x3a, 0x44, 0xf1, 0x2a, 0xd4, 0xe2, 0x93, 0x6d, 0xc3, 0x88,
-	0xa6, 0xff, 0xcc, 0xeb, 0x18, 0xe6, 0xaf, 0x68, 0x7e, 0xde, 0x79, 0x9d, 0x7d, 0x86, 0xc8, 0x62,
-	0xeb, 0xed, 0x0a, 0x26, 0x5a, 0xdd, 0x99, 0x9b, 0xe2, 0x41, 0x1d, 0xbf, 0xd7, 0x45, 0x33, 0xb2,
-	0x55, 0xa6, 0xcf, 0x60, 0xd6, 0xa3, 0x7f, 0x67, 0x9e, 0xfb, 0x77, 0x21, 0xe8, 0xd6, 0x43, 0x33,
-	0x98, 0xbe, 0x3d, 0x7c, 0x79, 0x74, 0x7a, 0xb8, 0xbf, 0x33, 0x42, 0x73, 0x08, 0x8e, 0x4e, 0x4f,
-	0x0c, 0x72, 0x56, 0xdf, 0x1c, 0xf0, 0xf7, 0xd5, 0xa1, 0x42, 0xbb, 0xe0, 0x1d, 0xd4, 0x6b, 0x34,
-	0xc3, 0xd7, 0xff, 0xa8, 0x74, 0x6a, 0x83, 0x9b, 0x8d, 0x1e, 0x39, 0xe8, 0x01, 0x4c, 0xcc, 0x61,
-	0x42, 0x31, 0x1e, 0x1c, 0xb3, 0xf4, 0x2f, 0x3c, 0xbc, 0x58, 0xd9, 0x08, 0x2d, 0xc0, 0xd7, 0x07,
-	0x07, 0x45, 0xb8, 0x7f, 0xa3, 0xd2, 0x18, 0x0f, 0xee, 0x90, 0x51, 0xea, 0x10, 0xa2, 0x08, 0xf7,
-	0xc3, 0x9a, 0xc6, 0x78, 0x90, 0x4d, 0xa3, 0xd4, 0x96, 0xa1, 0x08, 0xf7, 0xad, 0x4e, 0xe3, 0xa1,
-	0x93, 0xd9, 0xe8, 0x6c, 0xa2, 0xaf, 0xee, 0x93, 0x1f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x22, 0x65,
-	0x99, 0x10, 0x98, 0x05, 0x00, 0x00,
+	// 762 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xcb, 0x6e, 0xdb, 0x46,
+	0x14, 0x15, 0x29, 0x51, 0xa4, 0xae, 0x44, 0xda, 0x98, 0x3e, 0x40, 0xb0, 0x0f, 0x1b, 0x44, 0x0b,
+	0xa8, 0x6e, 0x31, 0x6a, 0xd5, 0x45, 0x5f, 0x3b, 0xdb, 0x05, 0x5a, 0xc0, 0xb5, 0x01, 0xda, 0xee,
+	0x7e, 0x4c, 0x4e, 0x64, 0xc6, 0xe6, 0x23, 0x33, 0x43, 0x03, 0xda, 0x64, 0x93, 0xcf, 0xc8, 0x4f,
+	0x64, 0x9b, 0x0f, 0xc9, 0xff, 0x04, 0xf3, 0x92, 0x45, 0x07, 0x89, 0x11, 0x64, 0x37, 0xe7, 0xce,
+	0x99, 0xab, 0x7b, 0x0f, 0xcf, 0xbd, 0x82, 0xbd, 0x82, 0x5e, 0x75, 0xab, 0x05, 0xa7, 0xec, 0xae,
+	0xcc, 0xe9, 0xa2, 0x65, 0x8d, 0x68, 0x16, 0x2a, 0x86, 0xd5, 0x39, 0xfd, 0x01, 0xc2, 0x7f, 0x28,
+	0xb9, 0x15, 0xd7, 0x19, 0x7d, 0xd6, 0x51, 0x2e, 0x50, 0x0c, 0xbe, 0x61, 0xc7, 0xce, 0xbe, 0x33,
+	0x9f, 0x64, 0x16, 0xa6, 0x73, 0x88, 0x2c, 0x95, 0xb7, 0x4d, 0xcd, 0x29, 0xfa, 0x12, 0xc6, 0x5c,
+	0x10, 0xd1, 0x71, 0x43, 0x35, 0x28, 0x9d, 0xc3, 0xec, 0x5c, 0x10, 0xc1, 0x1f, 0xcf, 0xf9, 0xc6,
+	0x81, 0xd0, 0x50, 0x4d, 0xce, 0xaf, 0x61, 0x22, 0xca, 0x8a, 0x72, 0x41, 0xaa, 0x56, 0xb1, 0x47,
+	0xd9, 0x7d, 0x40, 0x65, 0x12, 0x84, 0x09, 0x5a, 0xc4, 0xae, 0xba, 0xb3, 0x50, 0xd6, 0xd2, 0xb5,
+	0x92, 0x18, 0x0f, 0xd5, 0x85, 0x41, 0x32, 0x5e, 0xd1, 0xaa, 0x61, 0xeb, 0x78, 0xa4, 0xe3, 0x1a,
+	0xc9, 0x4c, 0xe2, 0x9a, 0x51, 0x52, 0xf0, 0xd8, 0xd3, 0x99, 0x0c, 0x44, 0x11, 0xb8, 0xab, 0x3c,
+	0x1e, 0xab, 0xa0, 0xbb, 0xca, 0x51, 0x02, 0x01, 0xd3, 0x8d, 0xf0, 0xd8, 0x57, 0xd1, 0x0d, 0x96,
+	0xd9, 0x29, 0x63, 0x0d, 0xe3, 0x71, 0xa0, 0xb3, 0x6b, 0x94, 0x3e, 0x05, 0x38, 0x69, 0x56, 0x8f,
+	0xf6, 0xaf, 0x15, 0x64, 0x94, 0x54, 0xaa, 0x9d, 0x20, 0x33, 0x08, 0x7d, 0x0e, 0x5e, 0xde, 0x74,
+	0xb5, 0x50, 0xcd, 0x0c, 0x33, 0x0d, 0x64, 0x94, 0x97, 0x75, 0x4e, 0x55, 0x2b, 0xc3, 0x4c, 0x83,
+	0xf4, 0x95, 0x03, 0xe3, 0x8c, 0xe6, 0x0d, 0x2b, 0xde, 0x15, 0x6f, 0xb8, 0x2d, 0xde, 0x2f, 0x10,
+	0x54, 0x54, 0x90, 0x82, 0x08, 0x12, 0xbb, 0xfb, 0xc3, 0xf9, 0x74, 0xf9, 0x05, 0xd6, 0x0f, 0xf1,
+	0x7f, 0x26, 0xfe, 0x77, 0x2d, 0xd8, 0x3a, 0xdb, 0xd0, 0x64, 0xe5, 0x15, 0xe5, 0x9c, 0xac, 0xb4,
+	0xac, 0x93, 0xcc, 0xc2, 0xe4, 0x2f, 0x08, 0x7b, 0x8f, 0xd0, 0x2e, 0x0c, 0x6f, 0xe8, 0xda, 0x34,
+	0x28, 0x8f, 0xb2, 0xdc, 0x3b, 0x72, 0xdb, 0x51, 0xd5, 0xdb, 0x24, 0xd3, 0xe0, 0x4f, 0xf7, 0x77,
+	0x27, 0xfd, 0x16, 0x66, 0x17, 0x8c, 0xe4, 0xd4, 0x0a, 0x14, 0x81, 0x5b, 0x16, 0xe6, 0xa9, 0x5b,
+	0x16, 0xe9, 0x4f, 0x10, 0x9a, 0x7b, 0xe3, 0x8a, 0xaf, 0xc0, 0xe3, 0x2d, 0xa9, 0xa5, 0xd1, 0x64,
+	0xdd, 0x1e, 0x3e, 0x6f, 0x49, 0x9d, 0xe9, 0x58, 0xfa, 0xd2, 0x85, 0x91, 0xc4, 0xf2, 0x07, 0x85,
+	0x7c, 0x66, 0x32, 0x69, 0x60, 0x92, 0xbb, 0x36, 0xb9, 0xd4, 0xbc, 0x25, 0x8c, 0x1a, 0x71, 0x27,
+	0x99, 0x41, 0x08, 0xc1, 0xa8, 0x26, 0x95, 0x16, 0x77, 0x92, 0xa9, 0xf3, 0xb6, 0xdf, 0xbc, 0xbe,
+	0xdf, 0x12, 0x08, 0x8a, 0x8e, 0x11, 0x51, 0x36, 0xb5, 0xf1, 0xca, 0x06, 0xa3, 0xc5, 0x96, 0xd0,
+	0xbe, 0x2a, 0xf8, 0x33, 0x55, 0xf0, 0x7b, 0x65, 0xfe, 0x06, 0x46, 0x62, 0xdd, 0x52, 0x65, 0xa2,
+	0x68, 0x39, 0x51, 0xe4, 0x8b, 0x75, 0x4b, 0x33, 0x15, 0xfe, 0x34, 0xad, 0x23, 0x98, 0x1d, 0x91,
+	0xfc, 0xda, 0x6a, 0x9d, 0x3e, 0x87, 0xd0, 0x60, 0xa3, 0xed, 0x12, 0xc6, 0x8a, 0x6d, 0xc5, 0x4d,
+	0x70, 0xef, 0x1e, 0xff, 0xaf, 0x2e, 0x75, 0xc9, 0x86, 0x99, 0xfc, 0x01, 0xd3, 0xad, 0xf0, 0x47,
+	0xd5, 0x73, 0x00, 0xd1, 0x21, 0xa3, 0xe4, 0x86, 0xb2, 0xc7, 0xd7, 0xc3, 0x6f, 0xb0, 0xb3, 0xe1,
+	0x9a, 0x6a, 0xbf, 0x83, 0x20, 0x2f, 0x59, 0xde, 0x95, 0xc2, 0xd6, 0x1b, 0xe0, 0x23, 0x1d, 0xc8,
+	0x36, 0x37, 0xe9, 0x6b, 0x07, 0x7c, 0x13, 0xfd, 0xc0, 0xf4, 0xc9, 0x2f, 0xde, 0x14, 0xb6, 0x46,
+	0x75, 0x96, 0x6c, 0x52, 0x14, 0x8c, 0x72, 0x6e, 0x1d, 0x6f, 0xa0, 0x9a, 0x3e, 0x41, 0x84, 0x35,
+	0x88, 0x06, 0xbd, 0xed, 0xe0, 0x3d, 0xd8, 0x0e, 0x09, 0x04, 0x4f, 0x48, 0x79, 0xdb, 0x31, 0xca,
+	0xad, 0x47, 0x2c, 0x96, 0xbf, 0xd3, 0xb5, 0x05, 0x91, 0xce, 0xf2, 0xd5, 0xa0, 0x5a, 0x78, 0xf0,
+	0x3d, 0x04, 0xf6, 0xfb, 0xa3, 0x29, 0xf8, 0xff, 0x9e, 0x1e, 0x9e, 0x5d, 0x9e, 0x1e, 0xef, 0x0e,
+	0xd0, 0x0c, 0x82, 0xb3, 0xcb, 0x0b, 0x8d, 0x9c, 0xe5, 0x0b, 0x17, 0xbc, 0x63, 0xb9, 0xc9, 0xd1,
+	0x1e, 0x0c, 0x4f, 0x9a, 0x15, 0x9a, 0xe2, 0xfb, 0x95, 0x93, 0xf8, 0x66, 0xb2, 0xd3, 0xc1, 0xcf,
+	0x0e, 0xfa, 0x11, 0xc6, 0x7a, 0x73, 0xa3, 0x08, 0xf7, 0xb6, 0x7d, 0xb2, 0x83, 0xfb, 0x2b, 0x3d,
+	0x1d, 0xa0, 0x39, 0x78, 0x6a, 0x23, 0xa3, 0x10, 0x6f, 0x2f, 0xf1, 0x24, 0xc2, 0xbd, 0x45, 0xad,
+	0x99, 0x6a, 0x4a, 0x51, 0x88, 0xb7, 0xa7, 0x39, 0x89, 0x70, 0x6f, 0x78, 0x35, 0x53, 0x79, 0x0a,
+	0x85, 0x78, 0xdb, 0x8b, 0x49, 0xd4, 0xb7, 0x5a, 0x3a, 0x40, 0x18, 0x7c, 0xf3, 0xc5, 0xd1, 0x0e,
+	0xee, 0xfb, 0x24, 0xd9, 0xc5, 0x0f, 0xcc, 0x90, 0x0e, 0xae, 0xc6, 0xea, 0x6f, 0xec, 0xd7, 0xb7,
+	0x03, 0x00, 0x23, 0x8b, 0x43, 0xba, 0xe9, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -734,6 +914,7 @@ type DebugClient interface {
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	Trace(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error)
 	Cache(ctx context.Context, in *CacheRequest, opts ...grpc.CallOption) (*CacheResponse, error)
+	Breaker(ctx context.Context, in *BreakerRequest, opts ...grpc.CallOption) (*BreakerResponse, error)
 }
 
 type debugClient struct {
@@ -812,6 +993,15 @@ func (c *debugClient) Cache(ctx context.Context, in *CacheRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *debugClient) Breaker(ctx context.Context, in *BreakerRequest, opts ...grpc.CallOption) (*BreakerResponse, error) {
+	out := new(BreakerResponse)
+	err := c.cc.Invoke(ctx, "/Debug/Breaker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DebugServer is the server API for Debug service.
 type DebugServer interface {
 	Log(*LogRequest, Debug_LogServer) error
@@ -819,6 +1009,7 @@ type DebugServer interface {
 	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 	Trace(context.Context, *TraceRequest) (*TraceResponse, error)
 	Cache(context.Context, *CacheRequest) (*CacheResponse, error)
+	Breaker(context.Context, *BreakerRequest) (*BreakerResponse, error)
 }
 
 // UnimplementedDebugServer can be embedded to have forward compatible implementations.
@@ -840,6 +1031,9 @@ func (*UnimplementedDebugServer) Trace(ctx context.Context, req *TraceRequest) (
 func (*UnimplementedDebugServer) Cache(ctx context.Context, req *CacheRequest) (*CacheResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Cache not implemented")
 }
+func (*UnimplementedDebugServer) Breaker(ctx context.Context, req *BreakerRequest) (*BreakerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Breaker not implemented")
+}
 
 func RegisterDebugServer(s *grpc.Server, srv DebugServer) {
 	s.RegisterService(&_Debug_serviceDesc, srv)
@@ -938,6 +1132,24 @@ func _Debug_Cache_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Debug_Breaker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BreakerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServer).Breaker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Debug/Breaker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServer).Breaker(ctx, req.(*BreakerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Debug_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "Debug",
 	HandlerType: (*DebugServer)(nil),
@@ -958,6 +1170,10 @@ var _Debug_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Cache",
 			Handler:    _Debug_Cache_Handler,
 		},
+		{
+			MethodName: "Breaker",
+			Handler:    _Debug_Breaker_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
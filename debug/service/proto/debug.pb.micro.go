@@ -47,6 +47,7 @@ type DebugService interface {
 	Stats(ctx context.Context, in *StatsRequest, opts ...client.CallOption) (*StatsResponse, error)
 	Trace(ctx context.Context, in *TraceRequest, opts ...client.CallOption) (*TraceResponse, error)
 	Cache(ctx context.Context, in *CacheRequest, opts ...client.CallOption) (*CacheResponse, error)
+	Breaker(ctx context.Context, in *BreakerRequest, opts ...client.CallOption) (*BreakerResponse, error)
 }
 
 type debugService struct {
@@ -150,6 +151,16 @@ func (c *debugService) Cache(ctx context.Context, in *CacheRequest, opts ...clie
 	return out, nil
 }
 
+func (c *debugService) Breaker(ctx context.Context, in *BreakerRequest, opts ...client.CallOption) (*BreakerResponse, error) {
+	req := c.c.NewRequest(c.name, "Debug.Breaker", in)
+	out := new(BreakerResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Debug service
 
 type DebugHandler interface {
@@ -158,6 +169,7 @@ type DebugHandler interface {
 	Stats(context.Context, *StatsRequest, *StatsResponse) error
 	Trace(context.Context, *TraceRequest, *TraceResponse) error
 	Cache(context.Context, *CacheRequest, *CacheResponse) error
+	Breaker(context.Context, *BreakerRequest, *BreakerResponse) error
 }
 
 func RegisterDebugHandler(s server.Server, hdlr DebugHandler, opts ...server.HandlerOption) error {
@@ -167,6 +179,7 @@ func RegisterDebugHandler(s server.Server, hdlr DebugHandler, opts ...server.Han
 		Stats(ctx context.Context, in *StatsRequest, out *StatsResponse) error
 		Trace(ctx context.Context, in *TraceRequest, out *TraceResponse) error
 		Cache(ctx context.Context, in *CacheRequest, out *CacheResponse) error
+		Breaker(ctx context.Context, in *BreakerRequest, out *BreakerResponse) error
 	}
 	type Debug struct {
 		debug
@@ -234,3 +247,7 @@ func (h *debugHandler) Trace(ctx context.Context, in *TraceRequest, out *TraceRe
 func (h *debugHandler) Cache(ctx context.Context, in *CacheRequest, out *CacheResponse) error {
 	return h.DebugHandler.Cache(ctx, in, out)
 }
+
+func (h *debugHandler) Breaker(ctx context.Context, in *BreakerRequest, out *BreakerResponse) error {
+	return h.DebugHandler.Breaker(ctx, in, out)
+}
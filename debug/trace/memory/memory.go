@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/micro/go-micro/v2/debug/trace"
 	"github.com/micro/go-micro/v2/util/ring"
 )
@@ -41,8 +40,8 @@ func (t *Tracer) Read(opts ...trace.ReadOption) ([]*trace.Span, error) {
 func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *trace.Span) {
 	span := &trace.Span{
 		Name:     name,
-		Trace:    uuid.New().String(),
-		Id:       uuid.New().String(),
+		Trace:    trace.NewTraceID(),
+		Id:       trace.NewSpanID(),
 		Started:  time.Now(),
 		Metadata: make(map[string]string),
 	}
@@ -62,6 +61,8 @@ func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *trac
 	span.Trace = traceID
 	// set parent
 	span.Parent = parentSpanID
+	// carry the vendor trace state
+	span.State, _ = trace.StateFromContext(ctx)
 
 	// return the span
 	return trace.ToContext(ctx, span.Trace, span.Id), span
@@ -73,6 +74,11 @@ func (t *Tracer) Finish(s *trace.Span) error {
 	// save the span
 	t.buffer.Put(s)
 
+	// ship the span
+	if t.opts.Exporter != nil {
+		return t.opts.Exporter.Export([]*trace.Span{s})
+	}
+
 	return nil
 }
 
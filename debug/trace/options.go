@@ -3,10 +3,28 @@ package trace
 type Options struct {
 	// Size is the size of ring buffer
 	Size int
+	// Exporter finished spans are shipped to
+	Exporter Exporter
+	// Address of the backend the spans are sent to, for the tracers which send them
+	Address string
 }
 
 type Option func(o *Options)
 
+// WithExporter ships finished spans to the exporter
+func WithExporter(e Exporter) Option {
+	return func(o *Options) {
+		o.Exporter = e
+	}
+}
+
+// WithAddress sets the address of the backend the spans are sent to
+func WithAddress(a string) Option {
+	return func(o *Options) {
+		o.Address = a
+	}
+}
+
 type ReadOptions struct {
 	// Trace id
 	Trace string
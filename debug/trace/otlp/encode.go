@@ -0,0 +1,202 @@
+package otlp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v2/debug/trace"
+)
+
+// The OTLP JSON encoding of a trace export request, see
+// https://github.com/open-telemetry/opentelemetry-proto
+
+type exportRequest struct {
+	ResourceSpans []*resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   *resource     `json:"resource"`
+	ScopeSpans []*scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []*keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope *scope  `json:"scope"`
+	Spans []*span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	TraceState        string      `json:"traceState,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []*keyValue `json:"attributes,omitempty"`
+	Events            []*event    `json:"events,omitempty"`
+	Status            *status     `json:"status,omitempty"`
+}
+
+type event struct {
+	TimeUnixNano string      `json:"timeUnixNano"`
+	Name         string      `json:"name"`
+	Attributes   []*keyValue `json:"attributes,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string    `json:"key"`
+	Value *anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+const (
+	// span kinds
+	kindServer = 2
+	kindClient = 3
+)
+
+func value(v interface{}) *anyValue {
+	switch t := v.(type) {
+	case string:
+		return &anyValue{StringValue: &t}
+	case bool:
+		return &anyValue{BoolValue: &t}
+	case int:
+		s := strconv.FormatInt(int64(t), 10)
+		return &anyValue{IntValue: &s}
+	case int32:
+		s := strconv.FormatInt(int64(t), 10)
+		return &anyValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(t, 10)
+		return &anyValue{IntValue: &s}
+	case float32:
+		f := float64(t)
+		return &anyValue{DoubleValue: &f}
+	case float64:
+		return &anyValue{DoubleValue: &t}
+	default:
+		s := fmt.Sprintf("%v", t)
+		return &anyValue{StringValue: &s}
+	}
+}
+
+// attributes returns the key values sorted by key
+func attributes(attrs map[string]interface{}) []*keyValue {
+	kvs := make([]*keyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, &keyValue{Key: k, Value: value(v)})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+// traceID returns the id as 16 hex encoded bytes, uuids lose their dashes
+func traceID(id string) string {
+	return strings.ToLower(strings.Replace(id, "-", "", -1))
+}
+
+// spanID returns the id as 8 hex encoded bytes, longer ids are truncated
+func spanID(id string) string {
+	id = traceID(id)
+	if len(id) > 16 {
+		return id[:16]
+	}
+	return id
+}
+
+func nanos(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func encodeSpan(s *trace.Span) *span {
+	kind := kindServer
+	if s.Type == trace.SpanTypeRequestOutbound {
+		kind = kindClient
+	}
+
+	attrs := make(map[string]interface{}, len(s.Metadata)+len(s.Attributes))
+	for k, v := range s.Metadata {
+		attrs[k] = v
+	}
+	for k, v := range s.Attributes {
+		attrs[k] = v
+	}
+
+	sp := &span{
+		TraceID:           traceID(s.Trace),
+		SpanID:            spanID(s.Id),
+		TraceState:        s.State,
+		Name:              s.Name,
+		Kind:              kind,
+		StartTimeUnixNano: nanos(s.Started.UnixNano()),
+		EndTimeUnixNano:   nanos(s.Started.Add(s.Duration).UnixNano()),
+		Attributes:        attributes(attrs),
+	}
+
+	if len(s.Parent) > 0 {
+		sp.ParentSpanID = spanID(s.Parent)
+	}
+
+	for _, e := range s.Events {
+		sp.Events = append(sp.Events, &event{
+			TimeUnixNano: nanos(e.Time.UnixNano()),
+			Name:         e.Name,
+			Attributes:   attributes(e.Attributes),
+		})
+	}
+
+	// the trace status codes match the OTLP ones
+	if s.Status.Code != trace.StatusUnset {
+		sp.Status = &status{
+			Code:    int(s.Status.Code),
+			Message: s.Status.Message,
+		}
+	}
+
+	return sp
+}
+
+func encode(name string, spans []*trace.Span) *exportRequest {
+	ss := &scopeSpans{
+		Scope: &scope{Name: "github.com/micro/go-micro/v2/debug/trace"},
+		Spans: make([]*span, 0, len(spans)),
+	}
+
+	for _, s := range spans {
+		ss.Spans = append(ss.Spans, encodeSpan(s))
+	}
+
+	return &exportRequest{
+		ResourceSpans: []*resourceSpans{{
+			Resource: &resource{
+				Attributes: attributes(map[string]interface{}{
+					"service.name": name,
+				}),
+			},
+			ScopeSpans: []*scopeSpans{ss},
+		}},
+	}
+}
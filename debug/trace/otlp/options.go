@@ -0,0 +1,114 @@
+package otlp
+
+import (
+	"net/http"
+	"os"
+	"time"
+)
+
+var (
+	// DefaultEndpoint of the OTLP HTTP receiver, the standard
+	// OTEL_EXPORTER_OTLP_ENDPOINT environment variable overrides it
+	DefaultEndpoint = "http://localhost:4318"
+	// DefaultName of the service the spans belong to
+	DefaultName = "go.micro"
+	// DefaultBatchSize is the max number of spans sent in a request
+	DefaultBatchSize = 512
+	// DefaultInterval at which spans are sent if the batch isn't full
+	DefaultInterval = 5 * time.Second
+	// DefaultQueueSize is the max number of spans waiting to be sent,
+	// spans are dropped when the queue is full
+	DefaultQueueSize = 2048
+	// DefaultTimeout of a request to the receiver
+	DefaultTimeout = 10 * time.Second
+)
+
+type Options struct {
+	// Endpoint of the receiver, spans are posted to /v1/traces
+	Endpoint string
+	// Headers sent with each request e.g for authentication
+	Headers map[string]string
+	// Name of the service, set as the service.name resource attribute
+	Name string
+	// BatchSize is the max number of spans sent in a request
+	BatchSize int
+	// Interval at which spans are sent if the batch isn't full
+	Interval time.Duration
+	// QueueSize is the max number of spans waiting to be sent
+	QueueSize int
+	// Client used to make requests
+	Client *http.Client
+}
+
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Endpoint:  DefaultEndpoint,
+		Headers:   make(map[string]string),
+		Name:      DefaultName,
+		BatchSize: DefaultBatchSize,
+		Interval:  DefaultInterval,
+		QueueSize: DefaultQueueSize,
+		Client:    &http.Client{Timeout: DefaultTimeout},
+	}
+
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); len(v) > 0 {
+		options.Endpoint = v
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
+}
+
+// Endpoint of the OTLP HTTP receiver e.g http://collector:4318
+func Endpoint(e string) Option {
+	return func(o *Options) {
+		o.Endpoint = e
+	}
+}
+
+// Header to send with each request
+func Header(k, v string) Option {
+	return func(o *Options) {
+		o.Headers[k] = v
+	}
+}
+
+// Name of the service the spans belong to
+func Name(n string) Option {
+	return func(o *Options) {
+		o.Name = n
+	}
+}
+
+// BatchSize is the max number of spans sent in a request
+func BatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// Interval at which spans are sent if the batch isn't full
+func Interval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
+
+// QueueSize is the max number of spans waiting to be sent
+func QueueSize(n int) Option {
+	return func(o *Options) {
+		o.QueueSize = n
+	}
+}
+
+// HTTPClient used to send spans
+func HTTPClient(c *http.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
@@ -0,0 +1,202 @@
+// Package otlp exports spans to an OpenTelemetry collector in the OTLP format over HTTP
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/debug/trace"
+	"github.com/micro/go-micro/v2/debug/trace/memory"
+	"github.com/micro/go-micro/v2/logger"
+)
+
+var (
+	// ErrClosed is returned when exporting spans after the exporter is closed
+	ErrClosed = errors.New("exporter closed")
+)
+
+type exporter struct {
+	opts Options
+
+	sync.RWMutex
+	queue  chan *trace.Span
+	exit   chan bool
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewExporter returns an exporter which sends spans in batches
+// to an OTLP HTTP receiver such as the OpenTelemetry collector
+func NewExporter(opts ...Option) trace.Exporter {
+	options := newOptions(opts...)
+
+	e := &exporter{
+		opts:  options,
+		queue: make(chan *trace.Span, options.QueueSize),
+		exit:  make(chan bool),
+	}
+
+	e.wg.Add(1)
+	go e.run()
+
+	return e
+}
+
+// NewTracer returns a memory tracer which exports its spans with an OTLP exporter,
+// sending them to the address set with trace.WithAddress if any
+func NewTracer(opts ...trace.Option) trace.Tracer {
+	var options trace.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// an exporter set in the options is used instead
+	if options.Exporter != nil {
+		return memory.NewTracer(opts...)
+	}
+
+	var eopts []Option
+	if len(options.Address) > 0 {
+		eopts = append(eopts, Endpoint(endpoint(options.Address)))
+	}
+
+	return memory.NewTracer(append([]trace.Option{trace.WithExporter(NewExporter(eopts...))}, opts...)...)
+}
+
+// endpoint returns the url of the receiver at the address, which is http if not set
+func endpoint(address string) string {
+	if strings.Contains(address, "://") {
+		return address
+	}
+	return "http://" + address
+}
+
+func (e *exporter) run() {
+	defer e.wg.Done()
+
+	t := time.NewTicker(e.opts.Interval)
+	defer t.Stop()
+
+	batch := make([]*trace.Span, 0, e.opts.BatchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("otlp: error exporting %d spans: %v", len(batch), err)
+			}
+		}
+		batch = make([]*trace.Span, 0, e.opts.BatchSize)
+	}
+
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+			if len(batch) >= e.opts.BatchSize {
+				flush()
+			}
+		case <-t.C:
+			flush()
+		case <-e.exit:
+			// send what's left in the queue
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+					if len(batch) >= e.opts.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *exporter) send(spans []*trace.Span) error {
+	b, err := json.Marshal(encode(e.opts.Name, spans))
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(e.opts.Endpoint, "/") + "/v1/traces"
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, 1024))
+		return fmt.Errorf("%s: %s", rsp.Status, string(body))
+	}
+
+	// drain the body so the connection is reused
+	io.Copy(ioutil.Discard, rsp.Body)
+
+	return nil
+}
+
+func (e *exporter) Export(spans []*trace.Span) error {
+	e.RLock()
+	defer e.RUnlock()
+
+	if e.closed {
+		return ErrClosed
+	}
+
+	for _, s := range spans {
+		select {
+		case e.queue <- s:
+		default:
+			// never block the request being traced
+			if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+				logger.Warnf("otlp: queue full, dropping span %s", s.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) Close() error {
+	e.Lock()
+	if e.closed {
+		e.Unlock()
+		return nil
+	}
+	e.closed = true
+	close(e.exit)
+	e.Unlock()
+
+	// wait for the remaining spans to be sent
+	e.wg.Wait()
+	return nil
+}
+
+func (e *exporter) String() string {
+	return "otlp"
+}
@@ -0,0 +1,148 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/debug/trace"
+	"github.com/micro/go-micro/v2/debug/trace/memory"
+)
+
+func TestExporter(t *testing.T) {
+	var mtx sync.Mutex
+	var requests []*exportRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		req := new(exportRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mtx.Lock()
+		requests = append(requests, req)
+		mtx.Unlock()
+	}))
+	defer srv.Close()
+
+	e := NewExporter(
+		Endpoint(srv.URL),
+		Header("Api-Key", "secret"),
+		Name("go.micro.srv.foo"),
+		BatchSize(2),
+		Interval(time.Hour),
+	)
+
+	tr := memory.NewTracer(trace.WithExporter(e))
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	_, child := tr.Start(ctx, "child")
+	child.Type = trace.SpanTypeRequestOutbound
+	child.SetAttribute("attempt", 2)
+	child.AddEvent("sent", nil)
+	child.SetStatus(trace.StatusError, "failed")
+	tr.Finish(child)
+	tr.Finish(parent)
+
+	// the third span is sent on close
+	_, last := tr.Start(context.Background(), "last")
+	tr.Finish(last)
+
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.Export([]*trace.Span{last}); err != ErrClosed {
+		t.Fatalf("expected %v got %v", ErrClosed, err)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 batches got %d", len(requests))
+	}
+
+	rs := requests[0].ResourceSpans[0]
+	if v := rs.Resource.Attributes[0]; v.Key != "service.name" || *v.Value.StringValue != "go.micro.srv.foo" {
+		t.Fatalf("unexpected resource %+v", v)
+	}
+
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans got %d", len(spans))
+	}
+
+	c, p := spans[0], spans[1]
+	if c.TraceID != p.TraceID || len(c.TraceID) != 32 {
+		t.Fatalf("unexpected trace ids %s %s", c.TraceID, p.TraceID)
+	}
+	if c.ParentSpanID != p.SpanID || len(p.SpanID) != 16 {
+		t.Fatalf("expected parent %s got %s", p.SpanID, c.ParentSpanID)
+	}
+	if c.Kind != kindClient || p.Kind != kindServer {
+		t.Fatalf("unexpected kinds %d %d", c.Kind, p.Kind)
+	}
+	if c.Status == nil || c.Status.Code != 2 || c.Status.Message != "failed" {
+		t.Fatalf("unexpected status %+v", c.Status)
+	}
+	if len(c.Events) != 1 || c.Events[0].Name != "sent" {
+		t.Fatalf("unexpected events %+v", c.Events)
+	}
+	if len(c.Attributes) != 1 || *c.Attributes[0].Value.IntValue != "2" {
+		t.Fatalf("unexpected attributes %+v", c.Attributes)
+	}
+
+	if spans := requests[1].ResourceSpans[0].ScopeSpans[0].Spans; len(spans) != 1 || spans[0].Name != "last" {
+		t.Fatalf("unexpected final batch %+v", spans)
+	}
+}
+
+func TestTracerAddress(t *testing.T) {
+	received := make(chan bool, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/traces" {
+			select {
+			case received <- true:
+			default:
+			}
+		}
+	}))
+	defer srv.Close()
+
+	// the address takes precedence over the environment
+	os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:1")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+
+	// send each span as it finishes
+	size := DefaultBatchSize
+	DefaultBatchSize = 1
+	defer func() { DefaultBatchSize = size }()
+
+	tr := NewTracer(trace.WithAddress(strings.TrimPrefix(srv.URL, "http://")))
+
+	_, span := tr.Start(context.Background(), "span")
+	tr.Finish(span)
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the span to be sent to the address")
+	}
+}
@@ -3,6 +3,10 @@ package trace
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/micro/go-micro/v2/metadata"
@@ -18,6 +22,16 @@ type Tracer interface {
 	Read(...ReadOption) ([]*Span, error)
 }
 
+// Exporter ships finished spans to a tracing backend
+type Exporter interface {
+	// Export the spans, it should not block on the backend
+	Export([]*Span) error
+	// Close flushes the spans yet to be exported
+	Close() error
+	// String returns the name of the exporter
+	String() string
+}
+
 // SpanType describe the nature of the trace span
 type SpanType int
 
@@ -46,15 +60,151 @@ type Span struct {
 	Metadata map[string]string
 	// Type
 	Type SpanType
+	// Attributes describing the span e.g the status code of a request.
+	// Values are strings, bools, integers or floats.
+	Attributes map[string]interface{}
+	// Events which occurred during the span
+	Events []*Event
+	// Status of the operation
+	Status Status
+	// State is the vendor specific W3C tracestate of the trace
+	State string
+}
+
+// Event is a named point in time during a span
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]interface{}
+}
+
+// StatusCode of a span
+type StatusCode int
+
+const (
+	// StatusUnset is the default status of a span
+	StatusUnset StatusCode = iota
+	// StatusOK means the operation completed successfully
+	StatusOK
+	// StatusError means the operation failed
+	StatusError
+)
+
+// Status of the operation of a span
+type Status struct {
+	Code    StatusCode
+	Message string
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]interface{})
+	}
+	s.Attributes[key] = value
+}
+
+// AddEvent records an event which occurred now
+func (s *Span) AddEvent(name string, attrs map[string]interface{}) {
+	s.Events = append(s.Events, &Event{
+		Name:       name,
+		Time:       time.Now(),
+		Attributes: attrs,
+	})
+}
+
+// SetStatus sets the status of the span
+func (s *Span) SetStatus(code StatusCode, message string) {
+	s.Status = Status{
+		Code:    code,
+		Message: message,
+	}
 }
 
 const (
 	traceIDKey = "Micro-Trace-Id"
 	spanIDKey  = "Micro-Span-Id"
+
+	// W3C trace context headers
+	traceParentKey = "Traceparent"
+	traceStateKey  = "Tracestate"
 )
 
-// FromContext returns a span from context
+// NewTraceID returns a random W3C trace id, 16 bytes hex encoded
+func NewTraceID() string {
+	return randomID(16)
+}
+
+// NewSpanID returns a random W3C span id, 8 bytes hex encoded
+func NewSpanID() string {
+	return randomID(8)
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validID reports whether the id is a non zero hex id of the length
+func validID(id string, n int) bool {
+	if len(id) != n || id == strings.Repeat("0", n) {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// parseTraceParent parses a W3C traceparent header
+// of the form version-traceid-parentid-flags
+func parseTraceParent(v string) (traceID string, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+	// version 00 has exactly four fields
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+	traceID, spanID = strings.ToLower(parts[1]), strings.ToLower(parts[2])
+	if !validID(traceID, 32) || !validID(spanID, 16) {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// normalizeTraceID returns the trace id in the W3C format if it's a uuid, which
+// is a valid trace id once the dashes are removed, so the id doesn't change
+// format along a trace. Other ids are returned as they are.
+func normalizeTraceID(traceID string) string {
+	id := strings.ToLower(strings.Replace(traceID, "-", "", -1))
+	if !validID(id, 32) {
+		return traceID
+	}
+	return id
+}
+
+// formatTraceParent returns the W3C traceparent header for the ids or
+// false if they can't be represented e.g a trace id which isn't a uuid
+func formatTraceParent(traceID, spanID string) (string, bool) {
+	traceID = normalizeTraceID(traceID)
+	spanID = strings.ToLower(spanID)
+	if !validID(traceID, 32) || !validID(spanID, 16) {
+		return "", false
+	}
+	// traces are always sampled
+	return fmt.Sprintf("00-%s-%s-01", traceID, spanID), true
+}
+
+// FromContext returns a span from context. The W3C traceparent header
+// takes precedence over the Micro-Trace-Id and Micro-Span-Id headers.
 func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFound bool) {
+	if v, ok := metadata.Get(ctx, traceParentKey); ok {
+		if traceID, parentSpanID, ok := parseTraceParent(v); ok {
+			return traceID, parentSpanID, true
+		}
+	}
+
 	traceID, traceOk := metadata.Get(ctx, traceIDKey)
 	microID, microOk := metadata.Get(ctx, "Micro-Id")
 	if !traceOk && !microOk {
@@ -65,15 +215,34 @@ func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFo
 		traceID = microID
 	}
 	parentSpanID, ok := metadata.Get(ctx, spanIDKey)
-	return traceID, parentSpanID, ok
+	return normalizeTraceID(traceID), parentSpanID, ok
+}
+
+// StateFromContext returns the W3C tracestate from the context
+func StateFromContext(ctx context.Context) (string, bool) {
+	return metadata.Get(ctx, traceStateKey)
 }
 
-// ToContext saves the trace and span ids in the context
+// ToContext saves the trace and span ids in the context, as a W3C
+// traceparent header too if the ids are in the W3C format
 func ToContext(ctx context.Context, traceID, parentSpanID string) context.Context {
-	return metadata.MergeContext(ctx, map[string]string{
+	// the same id is sent in both headers
+	traceID = normalizeTraceID(traceID)
+
+	md := map[string]string{
 		traceIDKey: traceID,
 		spanIDKey:  parentSpanID,
-	}, true)
+	}
+
+	// drop the traceparent as received, which may be in
+	// lower case, so that it isn't sent twice or left stale
+	ctx = metadata.Delete(ctx, traceParentKey)
+
+	if v, ok := formatTraceParent(traceID, parentSpanID); ok {
+		md[traceParentKey] = v
+	}
+
+	return metadata.MergeContext(ctx, md, true)
 }
 
 var (
@@ -0,0 +1,119 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/go-micro/v2/metadata"
+)
+
+func TestTraceParent(t *testing.T) {
+	testData := []struct {
+		header string
+		trace  string
+		span   string
+		ok     bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-00", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		// future versions may add fields
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", "", "", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "", "", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", "", "", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", "", "", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01", "", "", false},
+		{"garbage", "", "", false},
+	}
+
+	for _, d := range testData {
+		traceID, spanID, ok := parseTraceParent(d.header)
+		if ok != d.ok || traceID != d.trace || spanID != d.span {
+			t.Fatalf("%s: expected %s %s %v got %s %s %v", d.header, d.trace, d.span, d.ok, traceID, spanID, ok)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	// a traceparent received in lower case from another stack
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"tracestate":  "vendor=value",
+	})
+
+	traceID, parentID, ok := FromContext(ctx)
+	if !ok || traceID != "4bf92f3577b34da6a3ce929d0e0e4736" || parentID != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected trace %s parent %s", traceID, parentID)
+	}
+
+	if state, _ := StateFromContext(ctx); state != "vendor=value" {
+		t.Fatalf("expected trace state got %s", state)
+	}
+
+	spanID := NewSpanID()
+	ctx = ToContext(ctx, traceID, spanID)
+
+	md, _ := metadata.FromContext(ctx)
+	if v := md["Traceparent"]; v != "00-"+traceID+"-"+spanID+"-01" {
+		t.Fatalf("unexpected traceparent %s", v)
+	}
+	if v := md["Micro-Span-Id"]; v != spanID {
+		t.Fatalf("unexpected span id %s", v)
+	}
+
+	// the parent is the new span
+	if _, parentID, _ := FromContext(ctx); parentID != spanID {
+		t.Fatalf("expected parent %s got %s", spanID, parentID)
+	}
+
+	// ids which aren't in the W3C format fall back to the micro headers
+	ctx = ToContext(ctx, "trace", "span")
+	if _, ok := metadata.Get(ctx, "Traceparent"); ok {
+		t.Fatal("unexpected traceparent")
+	}
+	if traceID, parentID, _ := FromContext(ctx); traceID != "trace" || parentID != "span" {
+		t.Fatalf("unexpected trace %s parent %s", traceID, parentID)
+	}
+}
+
+func TestContextUUID(t *testing.T) {
+	// a uuid trace id received from a service which doesn't send a traceparent
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		"Micro-Trace-Id": "4BF92F35-77B3-4DA6-A3CE-929D0E0E4736",
+		"Micro-Span-Id":  "00f067aa0ba902b7",
+	})
+
+	traceID, _, ok := FromContext(ctx)
+	if !ok || traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("unexpected trace %s", traceID)
+	}
+
+	// the same id is sent in both headers
+	spanID := NewSpanID()
+	ctx = ToContext(ctx, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", spanID)
+
+	md, _ := metadata.FromContext(ctx)
+	if v := md["Micro-Trace-Id"]; v != traceID {
+		t.Fatalf("unexpected trace id %s", v)
+	}
+	if v := md["Traceparent"]; v != "00-"+traceID+"-"+spanID+"-01" {
+		t.Fatalf("unexpected traceparent %s", v)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	s := new(Span)
+	s.SetAttribute("rpc.method", "Foo.Bar")
+	s.AddEvent("retry", map[string]interface{}{"attempt": 1})
+	s.SetStatus(StatusError, "failed")
+
+	if s.Attributes["rpc.method"] != "Foo.Bar" {
+		t.Fatalf("unexpected attributes %v", s.Attributes)
+	}
+	if len(s.Events) != 1 || s.Events[0].Name != "retry" || s.Events[0].Time.IsZero() {
+		t.Fatalf("unexpected events %v", s.Events)
+	}
+	if s.Status.Code != StatusError || s.Status.Message != "failed" {
+		t.Fatalf("unexpected status %v", s.Status)
+	}
+}
@@ -106,6 +106,16 @@ func Conflict(id, format string, a ...interface{}) error {
 	}
 }
 
+// TooManyRequests generates a 429 error.
+func TooManyRequests(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   429,
+		Detail: fmt.Sprintf(format, a...),
+		Status: http.StatusText(429),
+	}
+}
+
 // InternalServerError generates a 500 error.
 func InternalServerError(id, format string, a ...interface{}) error {
 	return &Error{
@@ -116,6 +126,16 @@ func InternalServerError(id, format string, a ...interface{}) error {
 	}
 }
 
+// ServiceUnavailable generates a 503 error.
+func ServiceUnavailable(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   503,
+		Detail: fmt.Sprintf(format, a...),
+		Status: http.StatusText(503),
+	}
+}
+
 // Equal tries to compare errors
 func Equal(err1 error, err2 error) bool {
 	verr1, ok1 := err1.(*Error)
@@ -0,0 +1,106 @@
+// Package health provides health checks for the nodes of services. Nodes run their
+// checks and publish their status in their metadata, and clients can check nodes
+// actively to stop using those which have crashed before their registration expires.
+// Clients only check nodes actively if they're given a check, such as the one of
+// the health/rpc package, otherwise they only leave out the nodes which published
+// a failing status.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+const (
+	// StatusKey is the node metadata field holding the health status of the node
+	StatusKey = "health"
+	// Passing is the status of a node whose checks pass
+	Passing = "passing"
+	// Failing is the status of a node whose checks fail
+	Failing = "failing"
+)
+
+// Check checks the health of a node of a service, returning an error if it's unhealthy
+type Check func(ctx context.Context, service string, node *registry.Node) error
+
+// Func returns a check which calls the function, for checks a process runs on itself
+func Func(fn func(ctx context.Context) error) Check {
+	return func(ctx context.Context, service string, node *registry.Node) error {
+		return fn(ctx)
+	}
+}
+
+// HTTP returns a check which requests the url, passing on a 2xx response. A url
+// starting with a / is requested on the address of the node being checked.
+func HTTP(url string) Check {
+	return func(ctx context.Context, service string, node *registry.Node) error {
+		u := url
+		if strings.HasPrefix(u, "/") {
+			u = "http://" + node.Address + u
+		}
+
+		req, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			return err
+		}
+
+		rsp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		rsp.Body.Close()
+
+		if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+			return fmt.Errorf("health check %s: %s", u, rsp.Status)
+		}
+		return nil
+	}
+}
+
+// Run the checks of the node, returning the first error
+func Run(ctx context.Context, service string, node *registry.Node, checks ...Check) error {
+	for _, check := range checks {
+		if err := check(ctx, service, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Healthy returns false if the node has published a failing status.
+// Nodes which don't publish a status are healthy.
+func Healthy(node *registry.Node) bool {
+	return node.Metadata[StatusKey] != Failing
+}
+
+// Filter returns the services with only the nodes for which healthy returns true,
+// leaving out the services without any. The services passed aren't modified.
+func Filter(services []*registry.Service, healthy func(service string, node *registry.Node) bool) []*registry.Service {
+	var filtered []*registry.Service
+
+	for _, service := range services {
+		var nodes []*registry.Node
+		for _, node := range service.Nodes {
+			if healthy(service.Name, node) {
+				nodes = append(nodes, node)
+			}
+		}
+
+		// only add the service if there's some nodes
+		if len(nodes) == 0 {
+			continue
+		}
+
+		// copy
+		serv := new(registry.Service)
+		*serv = *service
+		serv.Nodes = nodes
+		filtered = append(filtered, serv)
+	}
+
+	return filtered
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+func TestHTTP(t *testing.T) {
+	var healthy bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	node := &registry.Node{Id: "foo-1", Address: strings.TrimPrefix(srv.URL, "http://")}
+
+	for _, check := range []Check{HTTP("/health"), HTTP(srv.URL + "/health")} {
+		healthy = true
+		if err := check(context.Background(), "foo", node); err != nil {
+			t.Fatalf("Expected the check to pass, got %v", err)
+		}
+		healthy = false
+		if err := check(context.Background(), "foo", node); err == nil {
+			t.Fatal("Expected the check to fail")
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	pass := Func(func(context.Context) error { return nil })
+	fail := Func(func(context.Context) error { return errFailed })
+
+	if err := Run(context.Background(), "foo", &registry.Node{}, pass, pass); err != nil {
+		t.Fatalf("Expected the checks to pass, got %v", err)
+	}
+	if err := Run(context.Background(), "foo", &registry.Node{}, pass, fail); err != errFailed {
+		t.Fatalf("Expected %v, got %v", errFailed, err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	services := []*registry.Service{
+		{
+			Name:    "foo",
+			Version: "1",
+			Nodes: []*registry.Node{
+				{Id: "foo-1"},
+				{Id: "foo-2", Metadata: map[string]string{StatusKey: Passing}},
+				{Id: "foo-3", Metadata: map[string]string{StatusKey: Failing}},
+			},
+		},
+		{
+			Name:    "foo",
+			Version: "2",
+			Nodes: []*registry.Node{
+				{Id: "foo-4", Metadata: map[string]string{StatusKey: Failing}},
+			},
+		},
+	}
+
+	filtered := Filter(services, func(service string, node *registry.Node) bool {
+		return Healthy(node)
+	})
+	if len(filtered) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(filtered))
+	}
+	if len(filtered[0].Nodes) != 2 || filtered[0].Nodes[0].Id != "foo-1" || filtered[0].Nodes[1].Id != "foo-2" {
+		t.Fatalf("Expected the healthy nodes, got %+v", filtered[0].Nodes)
+	}
+
+	// the services aren't modified
+	if len(services[0].Nodes) != 3 {
+		t.Fatalf("Expected the services not to be modified, got %+v", services[0].Nodes)
+	}
+}
@@ -0,0 +1,39 @@
+// Package rpc provides a health check calling the Debug.Health endpoint of the node
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/micro/go-micro/v2/client"
+	proto "github.com/micro/go-micro/v2/debug/service/proto"
+	"github.com/micro/go-micro/v2/health"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+// NewCheck returns a check which calls Debug.Health on the node with the client,
+// which every go-micro service serves. It passes if the status is ok.
+func NewCheck(c client.Client) health.Check {
+	return func(ctx context.Context, service string, node *registry.Node) error {
+		// call the node directly, without retrying
+		opts := []client.CallOption{
+			client.WithAddress(node.Address),
+			client.WithRetries(0),
+		}
+		if d, ok := ctx.Deadline(); ok {
+			opts = append(opts, client.WithRequestTimeout(time.Until(d)))
+		}
+
+		req := c.NewRequest(service, "Debug.Health", &proto.HealthRequest{})
+		rsp := &proto.HealthResponse{}
+		if err := c.Call(ctx, req, rsp, opts...); err != nil {
+			return err
+		}
+
+		if rsp.Status != "ok" {
+			return fmt.Errorf("health check %s: status %s", node.Id, rsp.Status)
+		}
+		return nil
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/micro/go-micro/v2/config/cmd"
 	"github.com/micro/go-micro/v2/debug/profile"
 	"github.com/micro/go-micro/v2/debug/trace"
+	"github.com/micro/go-micro/v2/health"
 	"github.com/micro/go-micro/v2/registry"
 	"github.com/micro/go-micro/v2/runtime"
 	"github.com/micro/go-micro/v2/server"
@@ -249,6 +250,21 @@ func RegisterInterval(t time.Duration) Option {
 	}
 }
 
+// HealthCheck adds checks run by the service on itself, which publishes its
+// health status in the registry so clients stop using it when it's failing
+func HealthCheck(checks ...health.Check) Option {
+	return func(o *Options) {
+		o.Server.Init(server.HealthCheck(checks...))
+	}
+}
+
+// HealthCheckInterval specifies the interval on which to run the health checks
+func HealthCheckInterval(t time.Duration) Option {
+	return func(o *Options) {
+		o.Server.Init(server.HealthCheckInterval(t))
+	}
+}
+
 // WrapClient is a convenience method for wrapping a Client with
 // some middleware component. A list of wrappers can be provided.
 // Wrappers are applied in reverse order so the last is executed first.
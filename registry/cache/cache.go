@@ -2,11 +2,14 @@
 package cache
 
 import (
+	"context"
 	"math"
 	"math/rand"
+	"reflect"
 	"sync"
 	"time"
 
+	"github.com/micro/go-micro/v2/health"
 	"github.com/micro/go-micro/v2/logger"
 	"github.com/micro/go-micro/v2/registry"
 	util "github.com/micro/go-micro/v2/util/registry"
@@ -18,11 +21,32 @@ type Cache interface {
 	registry.Registry
 	// stop the cache watcher
 	Stop()
+	// Stale returns true while the registry can't be reached, when the
+	// services returned are the last known ones from the cache or snapshot
+	Stale() bool
+}
+
+// Snapshot keeps the services which were last cached, for when the
+// registry can't be reached
+type Snapshot interface {
+	// Save the services by name
+	Save(service string, services []*registry.Service) error
+	// Load the services by name, returning registry.ErrNotFound if there's none
+	Load(service string) ([]*registry.Service, error)
 }
 
 type Options struct {
 	// TTL is the cache TTL
 	TTL time.Duration
+	// HealthCheck checks the nodes of the cached services, none by default
+	HealthCheck health.Check
+	// HealthInterval is the interval on which the nodes are checked
+	HealthInterval time.Duration
+	// Snapshot is where the cached services are saved, to be served
+	// when the registry can't be reached, even after a restart
+	Snapshot Snapshot
+	// SnapshotInterval is how often the cached services are saved
+	SnapshotInterval time.Duration
 }
 
 type Option func(o *Options)
@@ -36,6 +60,12 @@ type cache struct {
 	cache   map[string][]*registry.Service
 	ttls    map[string]time.Time
 	watched map[string]bool
+	// nodes which failed the health check
+	unhealthy map[nodeKey]bool
+	// nodes which have been checked, or are being checked
+	checked map[nodeKey]bool
+	// the services last saved in the snapshot
+	saved map[string][]*registry.Service
 
 	// used to stop the cache
 	exit chan bool
@@ -50,6 +80,10 @@ type cache struct {
 
 var (
 	DefaultTTL = time.Minute
+	// DefaultHealthInterval is how often the nodes are checked if there's a health check
+	DefaultHealthInterval = time.Second * 10
+	// DefaultSnapshotInterval is how often the services are saved if there's a snapshot
+	DefaultSnapshotInterval = time.Second * 30
 )
 
 func backoff(attempts int) time.Duration {
@@ -111,7 +145,36 @@ func (c *cache) del(service string) {
 	delete(c.ttls, service)
 }
 
-func (c *cache) get(service string) ([]*registry.Service, error) {
+// key returns the key of the service in the cache, which is its
+// name in the default domain and is prefixed by its domain otherwise
+func key(domain, service string) string {
+	if len(domain) == 0 || domain == registry.DefaultDomain {
+		return service
+	}
+	return domain + "/" + service
+}
+
+// withoutDomain returns the service without the domain the watcher added to its metadata
+func withoutDomain(s *registry.Service) (*registry.Service, string) {
+	domain, ok := s.Metadata[registry.DomainKey]
+	if !ok {
+		return s, registry.DefaultDomain
+	}
+
+	srv := new(registry.Service)
+	*srv = *s
+	srv.Metadata = make(map[string]string, len(s.Metadata))
+	for k, v := range s.Metadata {
+		if k != registry.DomainKey {
+			srv.Metadata[k] = v
+		}
+	}
+	return srv, domain
+}
+
+func (c *cache) get(domain, name string) ([]*registry.Service, error) {
+	service := key(domain, name)
+
 	// read lock
 	c.RLock()
 
@@ -132,7 +195,7 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 	// get does the actual request for a service and cache it
 	get := func(service string, cached []*registry.Service) ([]*registry.Service, error) {
 		// ask the registry
-		services, err := c.Registry.GetService(service)
+		services, err := c.Registry.GetService(name, registry.GetDomain(domain))
 		if err != nil {
 			// check the cache
 			if len(cached) > 0 {
@@ -142,6 +205,24 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 				// return the stale cache
 				return cached, nil
 			}
+			// check the snapshot, which is all there is on a cold start
+			if err != registry.ErrNotFound {
+				if snapshot := c.load(service); len(snapshot) > 0 {
+					if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+						logger.Warnf("rcache: serving %s from the snapshot: %v", service, err)
+					}
+
+					// set the error status
+					c.setStatus(err)
+
+					// cache without a ttl so the registry is asked on every lookup until it's back
+					c.Lock()
+					c.cache[service] = util.Copy(snapshot)
+					c.Unlock()
+
+					return snapshot, nil
+				}
+			}
 			// otherwise return error
 			return nil, err
 		}
@@ -174,6 +255,7 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 
 		// only kick it off if not running
 		if !c.running {
+			c.running = true
 			go c.run()
 		}
 
@@ -187,6 +269,25 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 func (c *cache) set(service string, services []*registry.Service) {
 	c.cache[service] = services
 	c.ttls[service] = time.Now().Add(c.opts.TTL)
+
+	if !c.checking() {
+		return
+	}
+
+	// check the nodes which haven't been checked yet right away
+	var targets []target
+	for _, s := range services {
+		for _, node := range s.Nodes {
+			t := target{s.Name, node}
+			if !c.checked[t.key()] {
+				c.checked[t.key()] = true
+				targets = append(targets, t)
+			}
+		}
+	}
+	if len(targets) > 0 {
+		go c.checkTargets(targets)
+	}
 }
 
 func (c *cache) update(res *registry.Result) {
@@ -194,15 +295,20 @@ func (c *cache) update(res *registry.Result) {
 		return
 	}
 
+	// the services of all the domains are watched
+	srv, domain := withoutDomain(res.Service)
+	res = &registry.Result{Action: res.Action, Service: srv}
+	name := key(domain, srv.Name)
+
 	c.Lock()
 	defer c.Unlock()
 
 	// only save watched services
-	if _, ok := c.watched[res.Service.Name]; !ok {
+	if _, ok := c.watched[name]; !ok {
 		return
 	}
 
-	services, ok := c.cache[res.Service.Name]
+	services, ok := c.cache[name]
 	if !ok {
 		// we're not going to cache anything
 		// unless there was already a lookup
@@ -212,7 +318,7 @@ func (c *cache) update(res *registry.Result) {
 	if len(res.Service.Nodes) == 0 {
 		switch res.Action {
 		case "delete":
-			c.del(res.Service.Name)
+			c.del(name)
 		}
 		return
 	}
@@ -230,7 +336,7 @@ func (c *cache) update(res *registry.Result) {
 	switch res.Action {
 	case "create", "update":
 		if service == nil {
-			c.set(res.Service.Name, append(services, res.Service))
+			c.set(name, append(services, res.Service))
 			return
 		}
 
@@ -249,7 +355,7 @@ func (c *cache) update(res *registry.Result) {
 		}
 
 		services[index] = res.Service
-		c.set(res.Service.Name, services)
+		c.set(name, services)
 	case "delete":
 		if service == nil {
 			return
@@ -275,7 +381,7 @@ func (c *cache) update(res *registry.Result) {
 		if len(nodes) > 0 {
 			service.Nodes = nodes
 			services[index] = service
-			c.set(service.Name, services)
+			c.set(name, services)
 			return
 		}
 
@@ -284,7 +390,7 @@ func (c *cache) update(res *registry.Result) {
 		// only have one thing to delete
 		// nuke the thing
 		if len(services) == 1 {
-			c.del(service.Name)
+			c.del(name)
 			return
 		}
 
@@ -298,16 +404,22 @@ func (c *cache) update(res *registry.Result) {
 		}
 
 		// save
-		c.set(service.Name, srvs)
+		c.set(name, srvs)
 	}
 }
 
 // run starts the cache watcher loop
 // it creates a new watcher if there's a problem
 func (c *cache) run() {
-	c.Lock()
-	c.running = true
-	c.Unlock()
+	// check the nodes until the cache is stopped
+	if c.checking() {
+		go c.check()
+	}
+
+	// save the services until the cache is stopped
+	if c.opts.Snapshot != nil && c.opts.SnapshotInterval > time.Duration(0) {
+		go c.snapshot()
+	}
 
 	// reset watcher on exit
 	defer func() {
@@ -329,8 +441,8 @@ func (c *cache) run() {
 		j := rand.Int63n(100)
 		time.Sleep(time.Duration(j) * time.Millisecond)
 
-		// create new watcher
-		w, err := c.Registry.Watch()
+		// create new watcher of all the domains
+		w, err := c.Registry.Watch(registry.WatchDomain(registry.WildcardDomain))
 		if err != nil {
 			if c.quit() {
 				return
@@ -419,13 +531,240 @@ func (c *cache) watch(w registry.Watcher) error {
 	}
 }
 
+// check the health of the cached nodes on interval
+func (c *cache) check() {
+	t := time.NewTicker(c.opts.HealthInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.exit:
+			return
+		case <-t.C:
+			c.checkNodes()
+		}
+	}
+}
+
+// nodeKey identifies a node of a service in the health check results
+type nodeKey struct {
+	service string
+	node    string
+}
+
+// target is a node of a service to check
+type target struct {
+	service string
+	node    *registry.Node
+}
+
+func (t target) key() nodeKey {
+	return keyOf(t.service, t.node)
+}
+
+func keyOf(service string, node *registry.Node) nodeKey {
+	if len(node.Id) > 0 {
+		return nodeKey{service, node.Id}
+	}
+	return nodeKey{service, node.Address}
+}
+
+// checking returns true if the cached nodes are health checked
+func (c *cache) checking() bool {
+	return c.opts.HealthCheck != nil && c.opts.HealthInterval > time.Duration(0)
+}
+
+// checkNodes runs the health check on all the cached nodes at once
+// and forgets the results of the nodes which aren't cached anymore
+func (c *cache) checkNodes() {
+	var targets []target
+
+	c.RLock()
+	for _, services := range c.cache {
+		for _, service := range services {
+			for _, node := range service.Nodes {
+				targets = append(targets, target{service.Name, node})
+			}
+		}
+	}
+	c.RUnlock()
+
+	c.checkTargets(targets)
+
+	cached := make(map[nodeKey]bool, len(targets))
+	for _, t := range targets {
+		cached[t.key()] = true
+	}
+
+	c.Lock()
+	for k := range c.checked {
+		if !cached[k] {
+			delete(c.checked, k)
+			delete(c.unhealthy, k)
+		}
+	}
+	c.Unlock()
+}
+
+// checkTargets runs the health check on the nodes at once
+// and updates the unhealthy nodes with the results
+func (c *cache) checkTargets(targets []target) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.HealthInterval)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	failed := make([]bool, len(targets))
+
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t target) {
+			defer wg.Done()
+			if err := c.opts.HealthCheck(ctx, t.service, t.node); err != nil {
+				if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+					logger.Debugf("rcache: node %s of %s failed the health check: %v", t.node.Id, t.service, err)
+				}
+				failed[i] = true
+			}
+		}(i, t)
+	}
+
+	wg.Wait()
+
+	c.Lock()
+	for i, t := range targets {
+		c.checked[t.key()] = true
+		if failed[i] {
+			c.unhealthy[t.key()] = true
+		} else {
+			delete(c.unhealthy, t.key())
+		}
+	}
+	c.Unlock()
+}
+
+// snapshot saves the cached services on interval, and once more when the cache is stopped
+func (c *cache) snapshot() {
+	t := time.NewTicker(c.opts.SnapshotInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.exit:
+			c.save()
+			return
+		case <-t.C:
+			c.save()
+		}
+	}
+}
+
+// save the cached services which changed since they were last saved
+func (c *cache) save() {
+	// only save services the registry is known to have
+	if c.getStatus() != nil {
+		return
+	}
+
+	c.RLock()
+	snapshots := make(map[string][]*registry.Service, len(c.cache))
+	for name, services := range c.cache {
+		snapshots[name] = util.Copy(services)
+	}
+	c.RUnlock()
+
+	for name, services := range snapshots {
+		if reflect.DeepEqual(c.saved[name], services) {
+			continue
+		}
+		if err := c.opts.Snapshot.Save(name, services); err != nil {
+			if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+				logger.Warnf("rcache: failed to save the snapshot of %s: %v", name, err)
+			}
+			continue
+		}
+		c.saved[name] = services
+	}
+}
+
+// load the services from the snapshot, returning nil if they aren't there
+func (c *cache) load(service string) []*registry.Service {
+	if c.opts.Snapshot == nil {
+		return nil
+	}
+
+	services, err := c.opts.Snapshot.Load(service)
+	if err != nil {
+		if err != registry.ErrNotFound && logger.V(logger.WarnLevel, logger.DefaultLogger) {
+			logger.Warnf("rcache: failed to load the snapshot of %s: %v", service, err)
+		}
+		return nil
+	}
+
+	return services
+}
+
+// healthy returns false for nodes with a failing status or which failed the health check.
+// Must be called with the lock held.
+func (c *cache) healthy(service string, node *registry.Node) bool {
+	return health.Healthy(node) && !c.unhealthy[keyOf(service, node)]
+}
+
+// Register the service, dropping it from the cache so it's read again
+func (c *cache) Register(s *registry.Service, opts ...registry.RegisterOption) error {
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	err := c.Registry.Register(s, opts...)
+
+	c.Lock()
+	c.del(key(options.Domain, s.Name))
+	c.Unlock()
+
+	return err
+}
+
+// Deregister the service, dropping it from the cache so it's read again
+func (c *cache) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	err := c.Registry.Deregister(s, opts...)
+
+	c.Lock()
+	c.del(key(options.Domain, s.Name))
+	c.Unlock()
+
+	return err
+}
+
 func (c *cache) GetService(service string, opts ...registry.GetOption) ([]*registry.Service, error) {
-	// get the service
-	services, err := c.get(service)
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	var services []*registry.Service
+	var err error
+
+	// the services of all the domains aren't cached
+	if options.Domain == registry.WildcardDomain {
+		services, err = c.Registry.GetService(service, opts...)
+	} else {
+		services, err = c.get(options.Domain, service)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	// leave out the unhealthy nodes
+	c.RLock()
+	services = health.Filter(services, c.healthy)
+	c.RUnlock()
+
 	// if there's nothing return err
 	if len(services) == 0 {
 		return nil, registry.ErrNotFound
@@ -435,6 +774,10 @@ func (c *cache) GetService(service string, opts ...registry.GetOption) ([]*regis
 	return services, nil
 }
 
+func (c *cache) Stale() bool {
+	return c.getStatus() != nil
+}
+
 func (c *cache) Stop() {
 	c.Lock()
 	defer c.Unlock()
@@ -455,7 +798,9 @@ func (c *cache) String() string {
 func New(r registry.Registry, opts ...Option) Cache {
 	rand.Seed(time.Now().UnixNano())
 	options := Options{
-		TTL: DefaultTTL,
+		TTL:              DefaultTTL,
+		HealthInterval:   DefaultHealthInterval,
+		SnapshotInterval: DefaultSnapshotInterval,
 	}
 
 	for _, o := range opts {
@@ -463,11 +808,14 @@ func New(r registry.Registry, opts ...Option) Cache {
 	}
 
 	return &cache{
-		Registry: r,
-		opts:     options,
-		watched:  make(map[string]bool),
-		cache:    make(map[string][]*registry.Service),
-		ttls:     make(map[string]time.Time),
-		exit:     make(chan bool),
+		Registry:  r,
+		opts:      options,
+		watched:   make(map[string]bool),
+		cache:     make(map[string][]*registry.Service),
+		ttls:      make(map[string]time.Time),
+		unhealthy: make(map[nodeKey]bool),
+		checked:   make(map[nodeKey]bool),
+		saved:     make(map[string][]*registry.Service),
+		exit:      make(chan bool),
 	}
 }
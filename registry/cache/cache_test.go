@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/health"
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/memory"
+	"github.com/micro/go-micro/v2/registry/test"
+)
+
+func nodes(t *testing.T, c Cache) []string {
+	services, err := c.GetService("foo")
+	if err == registry.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range services {
+		for _, n := range s.Nodes {
+			ids = append(ids, n.Id)
+		}
+	}
+	return ids
+}
+
+func TestCacheHealthStatus(t *testing.T) {
+	r := memory.NewRegistry()
+	c := New(r)
+	defer c.Stop()
+
+	service := &registry.Service{
+		Name:    "foo",
+		Version: "1",
+		Nodes: []*registry.Node{
+			{Id: "foo-1", Address: "10.0.0.1:8080"},
+			{Id: "foo-2", Address: "10.0.0.2:8080", Metadata: map[string]string{health.StatusKey: health.Failing}},
+		},
+	}
+	if err := r.Register(service); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := nodes(t, c); len(ids) != 1 || ids[0] != "foo-1" {
+		t.Fatalf("Expected foo-1, got %v", ids)
+	}
+}
+
+func TestCacheHealthCheck(t *testing.T) {
+	var mtx sync.Mutex
+	failing := map[string]bool{"foo-2": true}
+
+	check := func(ctx context.Context, service string, node *registry.Node) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if failing[node.Id] {
+			return errors.New("unhealthy")
+		}
+		return nil
+	}
+
+	r := memory.NewRegistry()
+	c := New(r, WithHealthCheck(check), WithHealthInterval(50*time.Millisecond))
+	defer c.Stop()
+
+	if err := r.Register(&registry.Service{
+		Name:    "foo",
+		Version: "1",
+		Nodes: []*registry.Node{
+			{Id: "foo-1", Address: "10.0.0.1:8080"},
+			{Id: "foo-2", Address: "10.0.0.2:8080"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the nodes are returned until they're checked
+	nodes(t, c)
+	time.Sleep(200 * time.Millisecond)
+	if ids := nodes(t, c); len(ids) != 1 || ids[0] != "foo-1" {
+		t.Fatalf("Expected foo-1, got %v", ids)
+	}
+
+	// all the nodes failing
+	mtx.Lock()
+	failing["foo-1"] = true
+	mtx.Unlock()
+	time.Sleep(200 * time.Millisecond)
+	if _, err := c.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	// nodes are returned once they pass again
+	mtx.Lock()
+	failing = map[string]bool{}
+	mtx.Unlock()
+	time.Sleep(200 * time.Millisecond)
+	if ids := nodes(t, c); len(ids) != 2 {
+		t.Fatalf("Expected 2 nodes, got %v", ids)
+	}
+}
+
+func TestCacheHealthCheckNewNodes(t *testing.T) {
+	check := func(ctx context.Context, service string, node *registry.Node) error {
+		if service == "foo" && node.Id == "node-2" {
+			return errors.New("unhealthy")
+		}
+		return nil
+	}
+
+	// the nodes are checked once cached rather than on the interval
+	r := memory.NewRegistry()
+	c := New(r, WithHealthCheck(check), WithHealthInterval(time.Minute))
+	defer c.Stop()
+
+	// the nodes of different services with the same id are checked apart
+	for _, name := range []string{"foo", "bar"} {
+		if err := r.Register(&registry.Service{
+			Name:    name,
+			Version: "1",
+			Nodes: []*registry.Node{
+				{Id: "node-1", Address: "10.0.0.1:8080"},
+				{Id: "node-2", Address: "10.0.0.2:8080"},
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := func(name string) int {
+		services, err := c.GetService(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		for _, s := range services {
+			n += len(s.Nodes)
+		}
+		return n
+	}
+
+	count("foo")
+	count("bar")
+	time.Sleep(100 * time.Millisecond)
+
+	if n := count("foo"); n != 1 {
+		t.Fatalf("Expected 1 node of foo, got %d", n)
+	}
+	if n := count("bar"); n != 2 {
+		t.Fatalf("Expected 2 nodes of bar, got %d", n)
+	}
+}
+
+type mapSnapshot struct {
+	sync.Mutex
+	services map[string][]*registry.Service
+}
+
+func (m *mapSnapshot) Save(service string, services []*registry.Service) error {
+	m.Lock()
+	defer m.Unlock()
+	m.services[service] = services
+	return nil
+}
+
+func (m *mapSnapshot) Load(service string) ([]*registry.Service, error) {
+	m.Lock()
+	defer m.Unlock()
+	services, ok := m.services[service]
+	if !ok {
+		return nil, registry.ErrNotFound
+	}
+	return services, nil
+}
+
+// downRegistry fails while it's down
+type downRegistry struct {
+	registry.Registry
+
+	sync.Mutex
+	down bool
+}
+
+func (d *downRegistry) err() error {
+	d.Lock()
+	defer d.Unlock()
+	if d.down {
+		return errors.New("registry down")
+	}
+	return nil
+}
+
+func (d *downRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return d.Registry.GetService(name, opts...)
+}
+
+func (d *downRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return d.Registry.Watch(opts...)
+}
+
+func TestCacheSnapshot(t *testing.T) {
+	snapshot := &mapSnapshot{services: make(map[string][]*registry.Service)}
+	r := &downRegistry{Registry: memory.NewRegistry()}
+
+	if err := r.Register(&registry.Service{
+		Name:    "foo",
+		Version: "1",
+		Nodes:   []*registry.Node{{Id: "foo-1", Address: "10.0.0.1:8080"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(r, WithSnapshot(snapshot), WithSnapshotInterval(50*time.Millisecond))
+	if ids := nodes(t, c); len(ids) != 1 || ids[0] != "foo-1" {
+		t.Fatalf("Expected foo-1, got %v", ids)
+	}
+	if c.Stale() {
+		t.Fatal("Expected the services not to be stale")
+	}
+	time.Sleep(150 * time.Millisecond)
+	c.Stop()
+
+	if _, err := snapshot.Load("foo"); err != nil {
+		t.Fatalf("Expected foo in the snapshot, got %v", err)
+	}
+
+	// a cold start while the registry is down is served from the snapshot
+	r.Lock()
+	r.down = true
+	r.Unlock()
+
+	c = New(r, WithSnapshot(snapshot))
+	defer c.Stop()
+
+	if ids := nodes(t, c); len(ids) != 1 || ids[0] != "foo-1" {
+		t.Fatalf("Expected foo-1, got %v", ids)
+	}
+	if !c.Stale() {
+		t.Fatal("Expected the services to be stale")
+	}
+	if _, err := c.GetService("bar"); err == nil {
+		t.Fatal("Expected an error for a service not in the snapshot")
+	}
+
+	// the registry is asked again once it's back
+	r.Lock()
+	r.down = false
+	r.Unlock()
+
+	if err := r.Register(&registry.Service{
+		Name:    "foo",
+		Version: "1",
+		Nodes:   []*registry.Node{{Id: "foo-2", Address: "10.0.0.2:8080"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := nodes(t, c); len(ids) != 2 {
+		t.Fatalf("Expected 2 nodes, got %v", ids)
+	}
+	if c.Stale() {
+		t.Fatal("Expected the services not to be stale")
+	}
+}
+
+func TestCacheConformance(t *testing.T) {
+	test.Run(t, func() registry.Registry {
+		return New(memory.NewRegistry())
+	})
+}
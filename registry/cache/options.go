@@ -2,6 +2,8 @@ package cache
 
 import (
 	"time"
+
+	"github.com/micro/go-micro/v2/health"
 )
 
 // WithTTL sets the cache TTL
@@ -10,3 +12,34 @@ func WithTTL(t time.Duration) Option {
 		o.TTL = t
 	}
 }
+
+// WithHealthCheck checks the nodes of the cached services when they're cached and
+// on the health check interval, leaving out those which fail until they pass again.
+// Without it only the nodes which published a failing status are left out.
+func WithHealthCheck(c health.Check) Option {
+	return func(o *Options) {
+		o.HealthCheck = c
+	}
+}
+
+// WithHealthInterval sets the interval on which the nodes are checked
+func WithHealthInterval(t time.Duration) Option {
+	return func(o *Options) {
+		o.HealthInterval = t
+	}
+}
+
+// WithSnapshot saves the cached services in the snapshot, to be served
+// when the registry can't be reached, including when starting up
+func WithSnapshot(s Snapshot) Option {
+	return func(o *Options) {
+		o.Snapshot = s
+	}
+}
+
+// WithSnapshotInterval sets how often the cached services are saved
+func WithSnapshotInterval(t time.Duration) Option {
+	return func(o *Options) {
+		o.SnapshotInterval = t
+	}
+}
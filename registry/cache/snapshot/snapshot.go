@@ -0,0 +1,115 @@
+// Package snapshot provides snapshots for the registry cache, keeping the services
+// last cached in a store or in local files so they outlive the process
+package snapshot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/cache"
+	"github.com/micro/go-micro/v2/store"
+)
+
+var (
+	// DefaultPrefix is the key prefix of the services in a store
+	DefaultPrefix = "registry/cache/"
+)
+
+type storeSnapshot struct {
+	store  store.Store
+	prefix string
+}
+
+func (s *storeSnapshot) Save(service string, services []*registry.Service) error {
+	b, err := json.Marshal(services)
+	if err != nil {
+		return err
+	}
+	return s.store.Write(&store.Record{Key: s.prefix + service, Value: b})
+}
+
+func (s *storeSnapshot) Load(service string) ([]*registry.Service, error) {
+	records, err := s.store.Read(s.prefix + service)
+	if err == store.ErrNotFound {
+		return nil, registry.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var services []*registry.Service
+	if err := json.Unmarshal(records[0].Value, &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+type fileSnapshot struct {
+	dir string
+}
+
+// path of the file of the service, escaped so any name can be used
+func (f *fileSnapshot) path(service string) string {
+	return filepath.Join(f.dir, url.PathEscape(service)+".json")
+}
+
+func (f *fileSnapshot) Save(service string, services []*registry.Service) error {
+	b, err := json.Marshal(services)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return err
+	}
+
+	// write a temporary file and rename it so the file is never partially written
+	tmp, err := ioutil.TempFile(f.dir, ".snapshot")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(service))
+}
+
+func (f *fileSnapshot) Load(service string) ([]*registry.Service, error) {
+	b, err := ioutil.ReadFile(f.path(service))
+	if os.IsNotExist(err) {
+		return nil, registry.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var services []*registry.Service
+	if err := json.Unmarshal(b, &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// NewStore returns a snapshot keeping the services in the store, as json
+// records keyed by the name of the service after the DefaultPrefix
+func NewStore(s store.Store) cache.Snapshot {
+	return &storeSnapshot{
+		store:  s,
+		prefix: DefaultPrefix,
+	}
+}
+
+// NewFile returns a snapshot keeping the services in the directory,
+// as a json file for each service
+func NewFile(dir string) cache.Snapshot {
+	return &fileSnapshot{dir: dir}
+}
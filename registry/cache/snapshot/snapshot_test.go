@@ -0,0 +1,48 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/cache"
+	"github.com/micro/go-micro/v2/store/memory"
+)
+
+func testSnapshot(t *testing.T, s cache.Snapshot) {
+	if _, err := s.Load("go.micro.srv.foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	services := []*registry.Service{{
+		Name:    "go.micro.srv.foo",
+		Version: "1",
+		Nodes:   []*registry.Node{{Id: "foo-1", Address: "10.0.0.1:8080"}},
+	}}
+	if err := s.Save("go.micro.srv.foo", services); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := s.Load("go.micro.srv.foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 || len(loaded[0].Nodes) != 1 || loaded[0].Nodes[0].Address != "10.0.0.1:8080" {
+		t.Fatalf("Unexpected services %+v", loaded)
+	}
+}
+
+func TestStore(t *testing.T) {
+	testSnapshot(t, NewStore(memory.NewStore()))
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testSnapshot(t, NewFile(dir))
+}
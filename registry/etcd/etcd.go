@@ -15,6 +15,7 @@ import (
 
 	"go.etcd.io/etcd/clientv3"
 	"go.etcd.io/etcd/etcdserver/api/v3rpc/rpctypes"
+	"go.etcd.io/etcd/mvcc/mvccpb"
 	"github.com/micro/go-micro/v2/logger"
 	"github.com/micro/go-micro/v2/registry"
 	hash "github.com/mitchellh/hashstructure"
@@ -22,7 +23,11 @@ import (
 )
 
 var (
-	prefix = "/micro/registry/"
+	// the prefix of the default domain, and of the other domains by name
+	prefix       = "/micro/registry/"
+	domainPrefix = "/micro/domain/"
+	// the prefix common to all the domains
+	commonPrefix = "/micro/"
 )
 
 type etcdRegistry struct {
@@ -120,14 +125,46 @@ func decode(ds []byte) *registry.Service {
 	return s
 }
 
-func nodePath(s, id string) string {
+// domainPath returns the prefix of the keys of the domain. The default
+// domain keeps the prefix used before there were domains.
+func domainPath(domain string) string {
+	if len(domain) == 0 || domain == registry.DefaultDomain {
+		return prefix
+	}
+	return path.Join(domainPrefix, strings.Replace(domain, "/", "-", -1)) + "/"
+}
+
+// keyDomain returns the domain of the key, and false if it isn't a registry key
+func keyDomain(key string) (string, bool) {
+	if strings.HasPrefix(key, prefix) {
+		return registry.DefaultDomain, true
+	}
+	if !strings.HasPrefix(key, domainPrefix) {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, domainPrefix), "/", 2)
+	if len(parts) < 2 {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// nodeKey is the key of the node in the hashes and leases
+func nodeKey(domain, s, id string) string {
+	if len(domain) == 0 || domain == registry.DefaultDomain {
+		return s + id
+	}
+	return domain + "/" + s + id
+}
+
+func nodePath(domain, s, id string) string {
 	service := strings.Replace(s, "/", "-", -1)
 	node := strings.Replace(id, "/", "-", -1)
-	return path.Join(prefix, service, node)
+	return path.Join(domainPath(domain), service, node)
 }
 
-func servicePath(s string) string {
-	return path.Join(prefix, strings.Replace(s, "/", "-", -1))
+func servicePath(domain, s string) string {
+	return path.Join(domainPath(domain), strings.Replace(s, "/", "-", -1))
 }
 
 func (e *etcdRegistry) Init(opts ...registry.Option) error {
@@ -143,9 +180,16 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 		return errors.New("Require at least one node")
 	}
 
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	key := nodeKey(options.Domain, s.Name, node.Id)
+
 	// check existing lease cache
 	e.RLock()
-	leaseID, ok := e.leases[s.Name+node.Id]
+	leaseID, ok := e.leases[key]
 	e.RUnlock()
 
 	if !ok {
@@ -154,7 +198,7 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 		defer cancel()
 
 		// look for the existing key
-		rsp, err := e.client.Get(ctx, nodePath(s.Name, node.Id), clientv3.WithSerializable())
+		rsp, err := e.client.Get(ctx, nodePath(options.Domain, s.Name, node.Id), clientv3.WithSerializable())
 		if err != nil {
 			return err
 		}
@@ -178,8 +222,8 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 
 				// save the info
 				e.Lock()
-				e.leases[s.Name+node.Id] = leaseID
-				e.register[s.Name+node.Id] = h
+				e.leases[key] = leaseID
+				e.register[key] = h
 				e.Unlock()
 
 				break
@@ -215,7 +259,7 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 
 	// get existing hash for the service node
 	e.Lock()
-	v, ok := e.register[s.Name+node.Id]
+	v, ok := e.register[key]
 	e.Unlock()
 
 	// the service is unchanged, skip registering
@@ -234,11 +278,6 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 		Nodes:     []*registry.Node{node},
 	}
 
-	var options registry.RegisterOptions
-	for _, o := range opts {
-		o(&options)
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
 	defer cancel()
 
@@ -256,9 +295,9 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 	}
 	// create an entry for the node
 	if lgr != nil {
-		_, err = e.client.Put(ctx, nodePath(service.Name, node.Id), encode(service), clientv3.WithLease(lgr.ID))
+		_, err = e.client.Put(ctx, nodePath(options.Domain, service.Name, node.Id), encode(service), clientv3.WithLease(lgr.ID))
 	} else {
-		_, err = e.client.Put(ctx, nodePath(service.Name, node.Id), encode(service))
+		_, err = e.client.Put(ctx, nodePath(options.Domain, service.Name, node.Id), encode(service))
 	}
 	if err != nil {
 		return err
@@ -266,10 +305,10 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 
 	e.Lock()
 	// save our hash of the service
-	e.register[s.Name+node.Id] = h
+	e.register[key] = h
 	// save our leaseID of the service
 	if lgr != nil {
-		e.leases[s.Name+node.Id] = lgr.ID
+		e.leases[key] = lgr.ID
 	}
 	e.Unlock()
 
@@ -281,12 +320,19 @@ func (e *etcdRegistry) Deregister(s *registry.Service, opts ...registry.Deregist
 		return errors.New("Require at least one node")
 	}
 
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	for _, node := range s.Nodes {
+		key := nodeKey(options.Domain, s.Name, node.Id)
+
 		e.Lock()
 		// delete our hash of the service
-		delete(e.register, s.Name+node.Id)
+		delete(e.register, key)
 		// delete our lease of the service
-		delete(e.leases, s.Name+node.Id)
+		delete(e.leases, key)
 		e.Unlock()
 
 		ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
@@ -295,7 +341,7 @@ func (e *etcdRegistry) Deregister(s *registry.Service, opts ...registry.Deregist
 		if logger.V(logger.TraceLevel, logger.DefaultLogger) {
 			logger.Tracef("Deregistering %s id %s", s.Name, node.Id)
 		}
-		_, err := e.client.Delete(ctx, nodePath(s.Name, node.Id))
+		_, err := e.client.Delete(ctx, nodePath(options.Domain, s.Name, node.Id))
 		if err != nil {
 			return err
 		}
@@ -322,80 +368,113 @@ func (e *etcdRegistry) Register(s *registry.Service, opts ...registry.RegisterOp
 	return gerr
 }
 
-func (e *etcdRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+// get the keys with the prefix in the domain, which for the wildcard domain
+// are those of the default domain and all the other domains
+func (e *etcdRegistry) get(domain, key string) ([]*mvccpb.KeyValue, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
 	defer cancel()
 
-	rsp, err := e.client.Get(ctx, servicePath(name)+"/", clientv3.WithPrefix(), clientv3.WithSerializable())
-	if err != nil {
-		return nil, err
+	if domain != registry.WildcardDomain {
+		rsp, err := e.client.Get(ctx, domainPath(domain)+key, clientv3.WithPrefix(), clientv3.WithSerializable())
+		if err != nil {
+			return nil, err
+		}
+		return rsp.Kvs, nil
 	}
 
-	if len(rsp.Kvs) == 0 {
-		return nil, registry.ErrNotFound
+	var kvs []*mvccpb.KeyValue
+	for _, p := range []string{prefix, domainPrefix} {
+		rsp, err := e.client.Get(ctx, p, clientv3.WithPrefix(), clientv3.WithSerializable())
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range rsp.Kvs {
+			// only keep the services with the key
+			d, ok := keyDomain(string(kv.Key))
+			if !ok || !strings.HasPrefix(string(kv.Key), domainPath(d)+key) {
+				continue
+			}
+			kvs = append(kvs, kv)
+		}
 	}
+	return kvs, nil
+}
 
-	serviceMap := map[string]*registry.Service{}
+// services returns the services of the keys, with their domain
+// in the metadata if they were looked up in the wildcard domain
+func services(domain string, kvs []*mvccpb.KeyValue) []*registry.Service {
+	versions := make(map[string]*registry.Service)
+	var services []*registry.Service
 
-	for _, n := range rsp.Kvs {
-		if sn := decode(n.Value); sn != nil {
-			s, ok := serviceMap[sn.Version]
-			if !ok {
-				s = &registry.Service{
-					Name:      sn.Name,
-					Version:   sn.Version,
-					Metadata:  sn.Metadata,
-					Endpoints: sn.Endpoints,
+	for _, n := range kvs {
+		sn := decode(n.Value)
+		if sn == nil {
+			continue
+		}
+
+		var d string
+		if domain == registry.WildcardDomain {
+			d, _ = keyDomain(string(n.Key))
+		}
+
+		k := d + "/" + sn.Name + "/" + sn.Version
+		s, ok := versions[k]
+		if !ok {
+			s = &registry.Service{
+				Name:      sn.Name,
+				Version:   sn.Version,
+				Metadata:  sn.Metadata,
+				Endpoints: sn.Endpoints,
+			}
+			if len(d) > 0 {
+				s.Metadata = make(map[string]string, len(sn.Metadata)+1)
+				for k, v := range sn.Metadata {
+					s.Metadata[k] = v
 				}
-				serviceMap[s.Version] = s
+				s.Metadata[registry.DomainKey] = d
 			}
-
-			s.Nodes = append(s.Nodes, sn.Nodes...)
+			versions[k] = s
+			services = append(services, s)
 		}
-	}
 
-	services := make([]*registry.Service, 0, len(serviceMap))
-	for _, service := range serviceMap {
-		services = append(services, service)
+		// append to service:version nodes
+		s.Nodes = append(s.Nodes, sn.Nodes...)
 	}
 
-	return services, nil
+	return services
 }
 
-func (e *etcdRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
-	versions := make(map[string]*registry.Service)
-
-	ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
-	defer cancel()
+func (e *etcdRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
 
-	rsp, err := e.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithSerializable())
+	kvs, err := e.get(options.Domain, strings.Replace(name, "/", "-", -1)+"/")
 	if err != nil {
 		return nil, err
 	}
 
-	if len(rsp.Kvs) == 0 {
-		return []*registry.Service{}, nil
+	if len(kvs) == 0 {
+		return nil, registry.ErrNotFound
 	}
 
-	for _, n := range rsp.Kvs {
-		sn := decode(n.Value)
-		if sn == nil {
-			continue
-		}
-		v, ok := versions[sn.Name+sn.Version]
-		if !ok {
-			versions[sn.Name+sn.Version] = sn
-			continue
-		}
-		// append to service:version nodes
-		v.Nodes = append(v.Nodes, sn.Nodes...)
+	return services(options.Domain, kvs), nil
+}
+
+func (e *etcdRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
 	}
 
-	services := make([]*registry.Service, 0, len(versions))
-	for _, service := range versions {
-		services = append(services, service)
+	kvs, err := e.get(options.Domain, "")
+	if err != nil {
+		return nil, err
 	}
 
+	services := services(options.Domain, kvs)
+
 	// sort the services
 	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
 
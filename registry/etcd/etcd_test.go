@@ -0,0 +1,36 @@
+package etcd
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/test"
+)
+
+func TestEtcdRegistryConformance(t *testing.T) {
+	if len(os.Getenv("IN_TRAVIS_CI")) != 0 {
+		t.Skip()
+	}
+
+	if _, err := NewRegistry(registry.Timeout(time.Second)).ListServices(); err != nil {
+		t.Skip("registry/etcd: can't connect to etcd")
+	}
+
+	test.Run(t, func() registry.Registry {
+		r := NewRegistry()
+
+		// remove the services left by the previous tests
+		for _, name := range []string{"test.service", "other.service"} {
+			services, _ := r.GetService(name, registry.GetDomain(registry.WildcardDomain))
+			for _, s := range services {
+				if err := r.Deregister(s, registry.DeregisterDomain(s.Metadata[registry.DomainKey])); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		return r
+	})
+}
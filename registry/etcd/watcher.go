@@ -10,6 +10,7 @@ import (
 )
 
 type etcdWatcher struct {
+	wo      registry.WatchOptions
 	stop    chan bool
 	w       clientv3.WatchChan
 	client  *clientv3.Client
@@ -30,12 +31,17 @@ func newEtcdWatcher(r *etcdRegistry, timeout time.Duration, opts ...registry.Wat
 		cancel()
 	}()
 
-	watchPath := prefix
-	if len(wo.Service) > 0 {
-		watchPath = servicePath(wo.Service) + "/"
+	// the wildcard domain watches the prefixes of all
+	// the domains, filtering out the other keys
+	watchPath := domainPath(wo.Domain)
+	if wo.Domain == registry.WildcardDomain {
+		watchPath = commonPrefix
+	} else if len(wo.Service) > 0 {
+		watchPath = servicePath(wo.Domain, wo.Service) + "/"
 	}
 
 	return &etcdWatcher{
+		wo:      wo,
 		stop:    stop,
 		w:       r.client.Watch(ctx, watchPath, clientv3.WithPrefix(), clientv3.WithPrevKV()),
 		client:  r.client,
@@ -72,6 +78,21 @@ func (ew *etcdWatcher) Next() (*registry.Result, error) {
 			if service == nil {
 				continue
 			}
+
+			if ew.wo.Domain == registry.WildcardDomain {
+				domain, ok := keyDomain(string(ev.Kv.Key))
+				if !ok {
+					continue
+				}
+				if len(ew.wo.Service) > 0 && service.Name != ew.wo.Service {
+					continue
+				}
+				if service.Metadata == nil {
+					service.Metadata = make(map[string]string)
+				}
+				service.Metadata[registry.DomainKey] = domain
+			}
+
 			return &registry.Result{
 				Action:  action,
 				Service: service,
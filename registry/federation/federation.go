@@ -0,0 +1,412 @@
+// Package federation provides a registry federating several registries, e.g. those of
+// different clusters. Services are registered with some or all of them, lookups merge
+// the services of all of them and watchers get the events of all of them. A registry
+// which fails is left out until it's back, and the status of each one is reported.
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/registry"
+	util "github.com/micro/go-micro/v2/util/registry"
+)
+
+var (
+	// DefaultTimeout is how long lookups wait for each registry, unless set with registry.Timeout
+	DefaultTimeout = 3 * time.Second
+
+	// ErrTimeout is the error of a registry which didn't answer a lookup in time
+	ErrTimeout = errors.New("registry timed out")
+
+	// how long to wait before watching a registry again after it failed
+	retryTime = time.Second
+)
+
+// Registry is a federation of registries
+type Registry interface {
+	registry.Registry
+	// Status returns the health of the federated registries, in their order
+	Status() []Status
+}
+
+// Status is the health of a federated registry
+type Status struct {
+	// Registry is the federated registry
+	Registry registry.Registry
+	// Write is true if services are registered with the registry
+	Write bool
+	// Error is the error of the last call to the registry, nil if it succeeded
+	Error error
+	// Updated is when the last call to the registry returned
+	Updated time.Time
+}
+
+type member struct {
+	registry.Registry
+	write bool
+
+	sync.RWMutex
+	err     error
+	updated time.Time
+}
+
+func (m *member) setStatus(err error) {
+	// not finding a service is a successful call
+	if err == registry.ErrNotFound {
+		err = nil
+	}
+
+	m.Lock()
+	m.err = err
+	m.updated = time.Now()
+	m.Unlock()
+}
+
+func (m *member) status() Status {
+	m.RLock()
+	defer m.RUnlock()
+
+	return Status{
+		Registry: m.Registry,
+		Write:    m.write,
+		Error:    m.err,
+		Updated:  m.updated,
+	}
+}
+
+type federation struct {
+	sync.RWMutex
+	options registry.Options
+	members []*member
+}
+
+func (f *federation) configure() error {
+	registries, _ := f.options.Context.Value(registriesKey{}).([]registry.Registry)
+	write, ok := f.options.Context.Value(writeKey{}).([]registry.Registry)
+
+	members := make([]*member, len(registries))
+	for i, r := range registries {
+		members[i] = &member{Registry: r, write: !ok}
+	}
+
+	for _, w := range write {
+		var found bool
+		for _, m := range members {
+			if m.Registry == w {
+				m.write = true
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("registry %s isn't federated", w)
+		}
+	}
+
+	f.Lock()
+	f.members = members
+	f.Unlock()
+
+	return nil
+}
+
+func (f *federation) getMembers() []*member {
+	f.RLock()
+	defer f.RUnlock()
+	return f.members
+}
+
+// write calls fn on the registries written to at once. It succeeds if any of them
+// succeeds, as the others are written to again when the service is registered again.
+func (f *federation) write(fn func(registry.Registry) error) error {
+	members := f.getMembers()
+	errs := make([]error, len(members))
+
+	var wg sync.WaitGroup
+	for i, m := range members {
+		if !m.write {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, m *member) {
+			defer wg.Done()
+			errs[i] = fn(m.Registry)
+			m.setStatus(errs[i])
+		}(i, m)
+	}
+	wg.Wait()
+
+	var written bool
+	var err error
+
+	for i, m := range members {
+		if !m.write {
+			continue
+		}
+		if errs[i] == nil {
+			written = true
+			continue
+		}
+		if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+			logger.Warnf("Federated registry %s failed: %v", m, errs[i])
+		}
+		if err == nil {
+			err = errs[i]
+		}
+	}
+
+	if written {
+		return nil
+	}
+	return err
+}
+
+// read calls fn on all the registries at once, returning the services of those which
+// succeeded merged, and the first error other than registry.ErrNotFound. Each registry
+// is waited for up to the timeout, and those whose last call failed aren't waited for
+// once the others answered, so that a registry which is down doesn't hold up lookups.
+func (f *federation) read(fn func(registry.Registry) ([]*registry.Service, error)) ([]*registry.Service, int, error) {
+	type answer struct {
+		index    int
+		services []*registry.Service
+		err      error
+	}
+
+	members := f.getMembers()
+	results := make([][]*registry.Service, len(members))
+	errs := make([]error, len(members))
+	answered := make([]bool, len(members))
+	// the errors of the registries whose last call failed
+	failing := make([]error, len(members))
+
+	answers := make(chan answer, len(members))
+	for i, m := range members {
+		failing[i] = m.status().Error
+		go func(i int, m *member) {
+			services, err := fn(m.Registry)
+			m.setStatus(err)
+			answers <- answer{i, services, err}
+		}(i, m)
+	}
+
+	// wait for the registries which aren't failing to answer
+	waiting := func() bool {
+		for i := range members {
+			if !answered[i] && failing[i] == nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	timeout := time.NewTimer(f.timeout())
+	defer timeout.Stop()
+
+wait:
+	for waiting() {
+		select {
+		case a := <-answers:
+			results[a.index], errs[a.index] = a.services, a.err
+			answered[a.index] = true
+		case <-timeout.C:
+			break wait
+		}
+	}
+
+	// take the answers of the failing registries which are in
+	for drained := false; !drained; {
+		select {
+		case a := <-answers:
+			results[a.index], errs[a.index] = a.services, a.err
+			answered[a.index] = true
+		default:
+			drained = true
+		}
+	}
+
+	for i, m := range members {
+		switch {
+		case answered[i]:
+		case failing[i] != nil:
+			errs[i] = failing[i]
+		default:
+			errs[i] = ErrTimeout
+			m.setStatus(ErrTimeout)
+		}
+	}
+
+	var failed int
+	var err error
+
+	for i, m := range members {
+		if errs[i] == nil || errs[i] == registry.ErrNotFound {
+			continue
+		}
+		if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+			logger.Warnf("Federated registry %s failed: %v", m, errs[i])
+		}
+		failed++
+		if err == nil {
+			err = errs[i]
+		}
+	}
+
+	return merge(results...), failed, err
+}
+
+// deleted returns the result of a registry deleting a service of the domain without the
+// nodes which other registries still have, or nil if they have all of them
+func (f *federation) deleted(from *member, domain string, res *registry.Result) *registry.Result {
+	if res.Action != "delete" || res.Service == nil {
+		return res
+	}
+
+	// the events of all the domains carry their domain
+	if d, ok := res.Service.Metadata[registry.DomainKey]; ok && domain == registry.WildcardDomain {
+		domain = d
+	}
+
+	nodes := res.Service.Nodes
+	var found bool
+
+	for _, m := range f.getMembers() {
+		if m == from {
+			continue
+		}
+
+		services, err := m.GetService(res.Service.Name, registry.GetDomain(domain))
+		m.setStatus(err)
+		if err != nil {
+			continue
+		}
+
+		for _, s := range services {
+			if s.Version != res.Service.Version {
+				continue
+			}
+			found = true
+			nodes = without(nodes, s.Nodes)
+		}
+	}
+
+	// the service is still in other registries
+	if found && len(nodes) == 0 {
+		return nil
+	}
+
+	service := util.CopyService(res.Service)
+	service.Nodes = nodes
+
+	return &registry.Result{Action: res.Action, Service: service}
+}
+
+// timeout returns how long lookups wait for each registry
+func (f *federation) timeout() time.Duration {
+	f.RLock()
+	defer f.RUnlock()
+	if f.options.Timeout > 0 {
+		return f.options.Timeout
+	}
+	return DefaultTimeout
+}
+
+func (f *federation) Init(opts ...registry.Option) error {
+	f.Lock()
+	for _, o := range opts {
+		o(&f.options)
+	}
+	f.Unlock()
+
+	return f.configure()
+}
+
+func (f *federation) Options() registry.Options {
+	f.RLock()
+	defer f.RUnlock()
+	return f.options
+}
+
+func (f *federation) Register(s *registry.Service, opts ...registry.RegisterOption) error {
+	return f.write(func(r registry.Registry) error {
+		return r.Register(s, opts...)
+	})
+}
+
+func (f *federation) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
+	return f.write(func(r registry.Registry) error {
+		return r.Deregister(s, opts...)
+	})
+}
+
+// GetService returns the service merged from all the registries. If it isn't found
+// but some registries failed, the error of the first one is returned.
+func (f *federation) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	services, _, err := f.read(func(r registry.Registry) ([]*registry.Service, error) {
+		return r.GetService(name, opts...)
+	})
+	if len(services) > 0 {
+		return services, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, registry.ErrNotFound
+}
+
+// ListServices returns the services merged from all the registries,
+// returning an error only if all of them failed
+func (f *federation) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	services, failed, err := f.read(func(r registry.Registry) ([]*registry.Service, error) {
+		return r.ListServices(opts...)
+	})
+	if failed > 0 && failed == len(f.getMembers()) {
+		return nil, err
+	}
+	return services, nil
+}
+
+// Watch returns a watcher of all the registries. The registries which can't be
+// watched are watched again until the watcher is stopped.
+func (f *federation) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	return newWatcher(f, opts...), nil
+}
+
+func (f *federation) Status() []Status {
+	members := f.getMembers()
+
+	status := make([]Status, len(members))
+	for i, m := range members {
+		status[i] = m.status()
+	}
+
+	return status
+}
+
+func (f *federation) String() string {
+	return "federation"
+}
+
+// NewRegistry returns a registry federating the registries set with the Registries option
+func NewRegistry(opts ...registry.Option) Registry {
+	options := registry.Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	f := &federation{
+		options: options,
+	}
+
+	if err := f.configure(); err != nil && logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+		logger.Errorf("Federated registry misconfigured: %v", err)
+	}
+
+	return f
+}
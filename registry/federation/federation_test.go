@@ -0,0 +1,311 @@
+package federation
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/memory"
+	"github.com/micro/go-micro/v2/registry/test"
+)
+
+var errDown = errors.New("registry down")
+
+// downRegistry fails every call while it's down
+type downRegistry struct {
+	registry.Registry
+
+	sync.Mutex
+	down bool
+}
+
+func (d *downRegistry) setDown(down bool) {
+	d.Lock()
+	d.down = down
+	d.Unlock()
+}
+
+func (d *downRegistry) err() error {
+	d.Lock()
+	defer d.Unlock()
+	if d.down {
+		return errDown
+	}
+	return nil
+}
+
+func (d *downRegistry) Register(s *registry.Service, opts ...registry.RegisterOption) error {
+	if err := d.err(); err != nil {
+		return err
+	}
+	return d.Registry.Register(s, opts...)
+}
+
+func (d *downRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return d.Registry.GetService(name, opts...)
+}
+
+func (d *downRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return d.Registry.ListServices(opts...)
+}
+
+// slowRegistry answers lookups after the delay
+type slowRegistry struct {
+	registry.Registry
+	delay time.Duration
+}
+
+func (s *slowRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	time.Sleep(s.delay)
+	return s.Registry.GetService(name, opts...)
+}
+
+func service(nodes ...*registry.Node) *registry.Service {
+	return &registry.Service{Name: "foo", Version: "1", Nodes: nodes}
+}
+
+func addresses(t *testing.T, r registry.Registry) map[string]string {
+	t.Helper()
+
+	services, err := r.GetService("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(services))
+	}
+
+	addrs := make(map[string]string)
+	for _, n := range services[0].Nodes {
+		if _, ok := addrs[n.Id]; ok {
+			t.Fatalf("Node %s is duplicated", n.Id)
+		}
+		addrs[n.Id] = n.Address
+	}
+	return addrs
+}
+
+func TestFederationMerge(t *testing.T) {
+	a := memory.NewRegistry()
+	b := memory.NewRegistry()
+	r := NewRegistry(Registries(a, b))
+
+	if err := a.Register(service(&registry.Node{Id: "foo-1", Address: "10.0.0.1:8080"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Register(service(
+		&registry.Node{Id: "foo-1", Address: "10.1.0.1:8080"},
+		&registry.Node{Id: "foo-2", Address: "10.1.0.2:8080"},
+	)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the nodes of the first registry take precedence
+	addrs := addresses(t, r)
+	if len(addrs) != 2 || addrs["foo-1"] != "10.0.0.1:8080" || addrs["foo-2"] != "10.1.0.2:8080" {
+		t.Fatalf("Unexpected nodes %v", addrs)
+	}
+
+	r = NewRegistry(Registries(b, a))
+	if addrs := addresses(t, r); addrs["foo-1"] != "10.1.0.1:8080" {
+		t.Fatalf("Unexpected nodes %v", addrs)
+	}
+
+	services, err := r.ListServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(services))
+	}
+
+	if _, err := r.GetService("bar"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+}
+
+func TestFederationWrite(t *testing.T) {
+	a := memory.NewRegistry()
+	b := memory.NewRegistry()
+	c := memory.NewRegistry()
+	r := NewRegistry(Registries(a, b, c), Write(a, b))
+
+	if err := r.Register(service(&registry.Node{Id: "foo-1", Address: "10.0.0.1:8080"})); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, m := range []registry.Registry{a, b} {
+		if _, err := m.GetService("foo"); err != nil {
+			t.Fatalf("Expected the service in registry %d, got %v", i, err)
+		}
+	}
+	if _, err := c.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected the service not to be written, got %v", err)
+	}
+
+	if err := r.Deregister(service(&registry.Node{Id: "foo-1"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	if err := r.Init(Write(memory.NewRegistry())); err == nil {
+		t.Fatal("Expected an error writing to a registry which isn't federated")
+	}
+}
+
+func TestFederationDown(t *testing.T) {
+	a := &downRegistry{Registry: memory.NewRegistry()}
+	b := memory.NewRegistry()
+	r := NewRegistry(Registries(a, b))
+
+	a.setDown(true)
+
+	// the registries which are up are used
+	if err := r.Register(service(&registry.Node{Id: "foo-1", Address: "10.0.0.1:8080"})); err != nil {
+		t.Fatal(err)
+	}
+	if addrs := addresses(t, r); len(addrs) != 1 {
+		t.Fatalf("Unexpected nodes %v", addrs)
+	}
+	if _, err := r.ListServices(); err != nil {
+		t.Fatal(err)
+	}
+
+	status := r.Status()
+	if len(status) != 2 {
+		t.Fatalf("Expected the status of 2 registries, got %d", len(status))
+	}
+	if status[0].Error != errDown || status[0].Registry != a {
+		t.Fatalf("Expected the first registry to be down, got %v", status[0].Error)
+	}
+	if status[1].Error != nil || status[1].Updated.IsZero() {
+		t.Fatalf("Expected the second registry to be up, got %v", status[1].Error)
+	}
+
+	// a service which isn't found might be in the registry which is down
+	if _, err := r.GetService("bar"); err != errDown {
+		t.Fatalf("Expected %v, got %v", errDown, err)
+	}
+
+	// the registry which is down isn't waited for, and is used again once it's back
+	a.setDown(false)
+	if _, err := r.GetService("foo"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; r.Status()[0].Error != nil; i++ {
+		if i == 100 {
+			t.Fatalf("Expected the first registry to be up, got %v", r.Status()[0].Error)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFederationTimeout(t *testing.T) {
+	a := &slowRegistry{Registry: memory.NewRegistry(), delay: time.Second}
+	b := memory.NewRegistry()
+	r := NewRegistry(Registries(a, b), registry.Timeout(50*time.Millisecond))
+
+	if err := b.Register(service(&registry.Node{Id: "foo-1", Address: "10.0.0.1:8080"})); err != nil {
+		t.Fatal(err)
+	}
+
+	// a registry which hangs is given up on after the timeout
+	start := time.Now()
+	if addrs := addresses(t, r); len(addrs) != 1 {
+		t.Fatalf("Unexpected nodes %v", addrs)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("Expected the lookup to time out, took %v", d)
+	}
+	if status := r.Status(); status[0].Error != ErrTimeout {
+		t.Fatalf("Expected the first registry to time out, got %v", status[0].Error)
+	}
+
+	// and not waited for again while it's failing
+	start = time.Now()
+	addresses(t, r)
+	if d := time.Since(start); d > 40*time.Millisecond {
+		t.Fatalf("Expected the lookup not to wait for the failing registry, took %v", d)
+	}
+}
+
+func TestFederationWatch(t *testing.T) {
+	a := memory.NewRegistry()
+	b := memory.NewRegistry()
+	r := NewRegistry(Registries(a, b))
+
+	w, err := r.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	next := func() *registry.Result {
+		t.Helper()
+
+		ch := make(chan *registry.Result, 1)
+		go func() {
+			res, err := w.Next()
+			if err == nil {
+				ch <- res
+			}
+		}()
+
+		select {
+		case res := <-ch:
+			return res
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for an event")
+		}
+		return nil
+	}
+
+	// the watchers are started in the background
+	time.Sleep(50 * time.Millisecond)
+
+	if err := a.Register(service(&registry.Node{Id: "foo-1", Address: "10.0.0.1:8080"})); err != nil {
+		t.Fatal(err)
+	}
+	if res := next(); res.Action != "update" || res.Service.Nodes[0].Id != "foo-1" {
+		t.Fatalf("Unexpected event %s of %v", res.Action, res.Service.Nodes)
+	}
+
+	if err := b.Register(service(
+		&registry.Node{Id: "foo-1", Address: "10.0.0.1:8080"},
+		&registry.Node{Id: "foo-2", Address: "10.0.0.2:8080"},
+	)); err != nil {
+		t.Fatal(err)
+	}
+	if res := next(); res.Action != "update" || len(res.Service.Nodes) != 2 {
+		t.Fatalf("Unexpected event %s of %v", res.Action, res.Service.Nodes)
+	}
+
+	// the nodes still in the other registry aren't deleted
+	if err := b.Deregister(service(
+		&registry.Node{Id: "foo-1", Address: "10.0.0.1:8080"},
+		&registry.Node{Id: "foo-2", Address: "10.0.0.2:8080"},
+	)); err != nil {
+		t.Fatal(err)
+	}
+	if res := next(); res.Action != "delete" || len(res.Service.Nodes) != 1 || res.Service.Nodes[0].Id != "foo-2" {
+		t.Fatalf("Unexpected event %s of %v", res.Action, res.Service.Nodes)
+	}
+}
+
+func TestFederationConformance(t *testing.T) {
+	test.Run(t, func() registry.Registry {
+		// the services are written to one of the registries, so each change is one event
+		a := memory.NewRegistry()
+		return NewRegistry(Registries(a, memory.NewRegistry()), Write(a))
+	})
+}
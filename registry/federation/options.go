@@ -0,0 +1,33 @@
+package federation
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+type registriesKey struct{}
+type writeKey struct{}
+
+// Registries sets the registries which are federated. Their order is the precedence
+// of their services when merging them, so the nodes of the first one are kept when
+// several registries have nodes with the same id.
+func Registries(r ...registry.Registry) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, registriesKey{}, r)
+	}
+}
+
+// Write sets the registries services are registered with, which
+// must be among the federated ones. Defaults to all of them.
+func Write(r ...registry.Registry) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, writeKey{}, r)
+	}
+}
@@ -0,0 +1,61 @@
+package federation
+
+import (
+	"github.com/micro/go-micro/v2/registry"
+	util "github.com/micro/go-micro/v2/util/registry"
+)
+
+// merge the services of the registries in their order of precedence. The services
+// with the same name, version and domain are merged into one with the nodes of all of
+// them, keeping the first node of those with the same id.
+func merge(results ...[]*registry.Service) []*registry.Service {
+	type key struct {
+		name    string
+		version string
+		domain  string
+	}
+
+	var merged []*registry.Service
+	seen := make(map[key]*registry.Service)
+
+	for _, services := range results {
+		for _, s := range services {
+			k := key{s.Name, s.Version, s.Metadata[registry.DomainKey]}
+
+			cur, ok := seen[k]
+			if !ok {
+				cur = util.CopyService(s)
+				seen[k] = cur
+				merged = append(merged, cur)
+				continue
+			}
+
+			// some registries don't return the endpoints
+			if len(cur.Endpoints) == 0 {
+				cur.Endpoints = util.CopyService(s).Endpoints
+			}
+
+			cur.Nodes = append(cur.Nodes, without(util.CopyService(s).Nodes, cur.Nodes)...)
+		}
+	}
+
+	return merged
+}
+
+// without returns the nodes without those with the ids of others
+func without(nodes, others []*registry.Node) []*registry.Node {
+	var filtered []*registry.Node
+	for _, n := range nodes {
+		var seen bool
+		for _, o := range others {
+			if n.Id == o.Id {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
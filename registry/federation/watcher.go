@@ -0,0 +1,123 @@
+package federation
+
+import (
+	"time"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+type watcher struct {
+	f      *federation
+	opts   []registry.WatchOption
+	domain string
+	res    chan *registry.Result
+	exit   chan bool
+}
+
+// watch the registry until the watcher is stopped, sending the events of rw, or of
+// a new watcher if it's nil, and of the watchers created again after failures
+func (w *watcher) watch(m *member, rw registry.Watcher) {
+	for {
+		var err error
+		if rw == nil {
+			rw, err = m.Watch(w.opts...)
+			m.setStatus(err)
+		}
+
+		if err == nil {
+			err = w.forward(m, rw)
+		}
+		rw = nil
+
+		select {
+		case <-w.exit:
+			return
+		default:
+		}
+
+		if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+			logger.Warnf("Federated registry %s watch failed: %v", m, err)
+		}
+
+		select {
+		case <-w.exit:
+			return
+		case <-time.After(retryTime):
+		}
+	}
+}
+
+// forward the events of the registry watcher until it fails or the watcher is stopped
+func (w *watcher) forward(m *member, rw registry.Watcher) error {
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		select {
+		case <-w.exit:
+		case <-done:
+		}
+		rw.Stop()
+	}()
+
+	for {
+		res, err := rw.Next()
+		if err != nil {
+			m.setStatus(err)
+			return err
+		}
+
+		// the nodes deleted from one registry may still be in others
+		if res = w.f.deleted(m, w.domain, res); res == nil {
+			continue
+		}
+
+		select {
+		case w.res <- res:
+		case <-w.exit:
+			return nil
+		}
+	}
+}
+
+func (w *watcher) Next() (*registry.Result, error) {
+	select {
+	case res := <-w.res:
+		return res, nil
+	case <-w.exit:
+		return nil, registry.ErrWatcherStopped
+	}
+}
+
+func (w *watcher) Stop() {
+	select {
+	case <-w.exit:
+	default:
+		close(w.exit)
+	}
+}
+
+func newWatcher(f *federation, opts ...registry.WatchOption) registry.Watcher {
+	var wo registry.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+
+	w := &watcher{
+		f:      f,
+		opts:   opts,
+		domain: wo.Domain,
+		res:    make(chan *registry.Result),
+		exit:   make(chan bool),
+	}
+
+	// watch the registries before returning so no event after this is missed
+	for _, m := range f.getMembers() {
+		rw, err := m.Watch(opts...)
+		m.setStatus(err)
+		go w.watch(m, rw)
+	}
+
+	return w
+}
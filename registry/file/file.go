@@ -0,0 +1,550 @@
+// Package file provides a static registry for sites without a registry to run, whose
+// services are read from a file or a directory of files. The files are watched, so
+// editing them sends events to the watchers and clients pick up the new nodes.
+//
+// A file lists the services along with their nodes, and optionally the domain
+// they're in if it isn't the default one, e.g. in yaml
+//
+//	domain: acme
+//	services:
+//	- name: greeter
+//	  version: latest
+//	  nodes:
+//	  - id: greeter-1
+//	    address: 10.0.0.1:8080
+//
+// Files with the .zone extension are DNS zone files, whose SRV records are the
+// nodes of the services, see zoneEncoder.
+package file
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v2/config/encoder"
+	"github.com/micro/go-micro/v2/config/encoder/json"
+	"github.com/micro/go-micro/v2/config/encoder/toml"
+	"github.com/micro/go-micro/v2/config/encoder/yaml"
+	"github.com/micro/go-micro/v2/config/source"
+	fsource "github.com/micro/go-micro/v2/config/source/file"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/registry"
+)
+
+var (
+	// DefaultPath is the file the services are read from if no path is set
+	DefaultPath = "registry.json"
+
+	// the encoders of the file formats, by extension
+	encoders = map[string]encoder.Encoder{
+		"json": json.NewEncoder(),
+		"yaml": yaml.NewEncoder(),
+		"yml":  yaml.NewEncoder(),
+		"toml": toml.NewEncoder(),
+		"zone": zoneEncoder{},
+	}
+
+	sendEventTime = 10 * time.Millisecond
+	retryTime     = time.Second
+	// how long to wait after a change before loading the files,
+	// so the files being written are loaded once they're complete
+	loadTime = 100 * time.Millisecond
+)
+
+// file is the contents of a file
+type file struct {
+	// Domain of the services, the default domain if not set
+	Domain   string              `json:"domain"`
+	Services []*registry.Service `json:"services"`
+}
+
+// services by domain, name and version
+type domains map[string]map[string]map[string]*registry.Service
+
+// add the service to the services of the domain
+func (d domains) add(domain string, s *registry.Service) {
+	if _, ok := d[domain]; !ok {
+		d[domain] = make(map[string]map[string]*registry.Service)
+	}
+	add(d[domain], s)
+}
+
+// domainOf returns the domain set in the options, or the default domain if none is
+func domainOf(domain string) string {
+	if len(domain) == 0 {
+		return registry.DefaultDomain
+	}
+	return domain
+}
+
+type fileRegistry struct {
+	options registry.Options
+	// closed to stop watching the files
+	exit chan bool
+
+	sync.RWMutex
+	// the services read from the files
+	files domains
+	// the services registered by this process
+	local domains
+	// the services of both, which is what's served
+	services domains
+
+	wmtx     sync.RWMutex
+	watchers map[string]*Watcher
+}
+
+// sendEvent of the domain to the watchers of the domain.
+// Must be called with the lock held so events are sent in order.
+func (f *fileRegistry) sendEvent(domain string, res *registry.Result) {
+	f.wmtx.RLock()
+	watchers := make([]*Watcher, 0, len(f.watchers))
+	for _, w := range f.watchers {
+		watchers = append(watchers, w)
+	}
+	f.wmtx.RUnlock()
+
+	for _, w := range watchers {
+		r := res
+		switch w.wo.Domain {
+		case domain:
+		case registry.WildcardDomain:
+			r = &registry.Result{Action: res.Action, Service: copyService(res.Service)}
+			r.Service.Metadata[registry.DomainKey] = domain
+		default:
+			continue
+		}
+
+		select {
+		case <-w.exit:
+			f.wmtx.Lock()
+			delete(f.watchers, w.id)
+			f.wmtx.Unlock()
+		default:
+			select {
+			case w.res <- r:
+			case <-time.After(sendEventTime):
+			}
+		}
+	}
+}
+
+// update the services served from the files and the local services, sending
+// the events for the changes. Must be called with the lock held.
+func (r *fileRegistry) update() {
+	services := make(domains)
+	for _, d := range []domains{r.files, r.local} {
+		for domain, names := range d {
+			for _, versions := range names {
+				for _, s := range versions {
+					services.add(domain, s)
+				}
+			}
+		}
+	}
+
+	changed := make(map[string]bool)
+	for domain := range r.services {
+		changed[domain] = true
+	}
+	for domain := range services {
+		changed[domain] = true
+	}
+
+	for domain := range changed {
+		for _, res := range diff(r.services[domain], services[domain]) {
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("Registry %s service: %s, version: %s, domain: %s", res.Action, res.Service.Name, res.Service.Version, domain)
+			}
+			r.sendEvent(domain, res)
+		}
+	}
+
+	r.services = services
+}
+
+// paths returns the files to read, which are the files in the directory
+// with a known format if the path is a directory
+func (r *fileRegistry) paths(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		if _, ok := encoders[strings.TrimPrefix(filepath.Ext(info.Name()), ".")]; !ok {
+			continue
+		}
+		paths = append(paths, filepath.Join(path, info.Name()))
+	}
+
+	return paths, nil
+}
+
+// load the services from the files. If any of them can't be read
+// the services loaded before are kept.
+func (r *fileRegistry) load(path string) error {
+	paths, err := r.paths(path)
+	if err != nil {
+		return err
+	}
+
+	services := make(domains)
+
+	for _, p := range paths {
+		cs, err := fsource.NewSource(fsource.WithPath(p)).Read()
+		if err != nil {
+			return err
+		}
+
+		enc, ok := encoders[cs.Format]
+		if !ok {
+			return fmt.Errorf("unknown format %s of %s", cs.Format, p)
+		}
+
+		var f file
+		if err := enc.Decode(cs.Data, &f); err != nil {
+			return fmt.Errorf("error decoding %s: %v", p, err)
+		}
+
+		for _, s := range f.Services {
+			if s == nil || len(s.Name) == 0 {
+				return fmt.Errorf("service without a name in %s", p)
+			}
+			services.add(domainOf(f.Domain), s)
+		}
+	}
+
+	r.Lock()
+	r.files = services
+	r.update()
+	r.Unlock()
+
+	return nil
+}
+
+// watch the path for changes, loading the services on every change until
+// exit is closed or the watcher fails
+func (r *fileRegistry) watch(path string, exit chan bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	var next func() error
+	var stop func() error
+
+	if info.IsDir() {
+		// watch the directory for the files being added and removed as well
+		fw, err := fsnotify.NewWatcher()
+		if err != nil {
+			return err
+		}
+		if err := fw.Add(path); err != nil {
+			fw.Close()
+			return err
+		}
+
+		next = func() error {
+			select {
+			case _, ok := <-fw.Events:
+				if !ok {
+					return source.ErrWatcherStopped
+				}
+				return nil
+			case err := <-fw.Errors:
+				return err
+			}
+		}
+		stop = fw.Close
+	} else {
+		w, err := fsource.NewSource(fsource.WithPath(path)).Watch()
+		if err != nil {
+			return err
+		}
+
+		next = func() error {
+			_, err := w.Next()
+			return err
+		}
+		stop = w.Stop
+	}
+
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		select {
+		case <-exit:
+		case <-done:
+		}
+		stop()
+	}()
+
+	// load again in case the files changed before they were watched
+	if err := r.load(path); err != nil && logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+		logger.Errorf("Registry failed to load %s: %v", path, err)
+	}
+
+	for {
+		err := next()
+
+		select {
+		case <-exit:
+			return nil
+		default:
+		}
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-exit:
+			return nil
+		case <-time.After(loadTime):
+		}
+
+		if err := r.load(path); err != nil && logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			logger.Errorf("Registry failed to load %s: %v", path, err)
+		}
+	}
+}
+
+// run watches the path until exit is closed, watching it again after errors
+func (r *fileRegistry) run(path string, exit chan bool) {
+	for {
+		err := r.watch(path, exit)
+
+		select {
+		case <-exit:
+			return
+		default:
+		}
+
+		if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+			logger.Warnf("Registry failed to watch %s: %v", path, err)
+		}
+
+		select {
+		case <-exit:
+			return
+		case <-time.After(retryTime):
+		}
+	}
+}
+
+func (r *fileRegistry) configure() error {
+	path := DefaultPath
+	if p, ok := r.options.Context.Value(pathKey{}).(string); ok {
+		path = p
+	} else if len(r.options.Addrs) > 0 {
+		// the path given as the address e.g. with --registry_address
+		path = r.options.Addrs[0]
+	}
+
+	// stop watching the old path
+	r.Lock()
+	if r.exit != nil {
+		close(r.exit)
+	}
+	exit := make(chan bool)
+	r.exit = exit
+	r.Unlock()
+
+	err := r.load(path)
+
+	go r.run(path, exit)
+
+	return err
+}
+
+func (r *fileRegistry) Init(opts ...registry.Option) error {
+	r.Lock()
+	for _, o := range opts {
+		o(&r.options)
+	}
+	r.Unlock()
+
+	return r.configure()
+}
+
+func (r *fileRegistry) Options() registry.Options {
+	return r.options
+}
+
+// Register adds the nodes of the service to those read from the files. The
+// registrations are only seen by this process and aren't expired.
+func (r *fileRegistry) Register(s *registry.Service, opts ...registry.RegisterOption) error {
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.local.add(domainOf(options.Domain), s)
+	r.update()
+
+	return nil
+}
+
+// Deregister removes nodes registered by this process. The nodes in the files
+// can only be removed by editing them.
+func (r *fileRegistry) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	domain := domainOf(options.Domain)
+
+	r.Lock()
+	defer r.Unlock()
+
+	if services, ok := r.local[domain]; ok {
+		remove(services, s)
+		if len(services) == 0 {
+			delete(r.local, domain)
+		}
+	}
+	r.update()
+
+	return nil
+}
+
+// lookup returns the services of the domains looked up, with their
+// domain in the metadata when all the domains are looked up.
+// Must be called with the lock held.
+func (r *fileRegistry) lookup(domain string, fn func(names map[string]map[string]*registry.Service) []*registry.Service) []*registry.Service {
+	domain = domainOf(domain)
+	if domain != registry.WildcardDomain {
+		return fn(r.services[domain])
+	}
+
+	var services []*registry.Service
+	for d, names := range r.services {
+		for _, s := range fn(names) {
+			s.Metadata[registry.DomainKey] = d
+			services = append(services, s)
+		}
+	}
+	return services
+}
+
+func (r *fileRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	services := r.lookup(options.Domain, func(names map[string]map[string]*registry.Service) []*registry.Service {
+		var services []*registry.Service
+		for _, s := range names[name] {
+			services = append(services, copyService(s))
+		}
+		return services
+	})
+
+	if len(services) == 0 {
+		return nil, registry.ErrNotFound
+	}
+
+	return services, nil
+}
+
+func (r *fileRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	services := r.lookup(options.Domain, func(names map[string]map[string]*registry.Service) []*registry.Service {
+		var services []*registry.Service
+		for _, versions := range names {
+			for _, s := range versions {
+				services = append(services, copyService(s))
+			}
+		}
+		return services
+	})
+
+	return services, nil
+}
+
+func (r *fileRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	var wo registry.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+	wo.Domain = domainOf(wo.Domain)
+
+	w := &Watcher{
+		exit: make(chan bool),
+		res:  make(chan *registry.Result, 64),
+		id:   uuid.New().String(),
+		wo:   wo,
+	}
+
+	r.wmtx.Lock()
+	r.watchers[w.id] = w
+	r.wmtx.Unlock()
+
+	return w, nil
+}
+
+func (r *fileRegistry) String() string {
+	return "file"
+}
+
+// NewRegistry returns a registry serving the services in the files at the path
+// set with the Path option, which are loaded again whenever they change.
+func NewRegistry(opts ...registry.Option) registry.Registry {
+	options := registry.Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	r := &fileRegistry{
+		options:  options,
+		files:    make(domains),
+		local:    make(domains),
+		services: make(domains),
+		watchers: make(map[string]*Watcher),
+	}
+
+	if err := r.configure(); err != nil && logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+		logger.Errorf("Registry failed to load the services: %v", err)
+	}
+
+	return r
+}
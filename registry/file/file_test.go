@@ -0,0 +1,291 @@
+package file
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/test"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func next(t *testing.T, w registry.Watcher) *registry.Result {
+	t.Helper()
+
+	ch := make(chan *registry.Result, 1)
+	go func() {
+		res, err := w.Next()
+		if err != nil {
+			return
+		}
+		ch <- res
+	}()
+
+	select {
+	case res := <-ch:
+		return res
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for an event")
+	}
+	return nil
+}
+
+func expect(t *testing.T, res *registry.Result, action, name string, nodes ...string) {
+	t.Helper()
+
+	if res.Action != action || res.Service.Name != name {
+		t.Fatalf("Expected %s %s, got %s %s", action, name, res.Action, res.Service.Name)
+	}
+	if len(res.Service.Nodes) != len(nodes) {
+		t.Fatalf("Expected nodes %v, got %d nodes", nodes, len(res.Service.Nodes))
+	}
+	for i, n := range res.Service.Nodes {
+		if n.Id != nodes[i] {
+			t.Fatalf("Expected nodes %v, got %s at %d", nodes, n.Id, i)
+		}
+	}
+}
+
+func TestFileRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "registry.yaml")
+	writeFile(t, path, `
+services:
+- name: foo
+  version: "1"
+  nodes:
+  - id: foo-1
+    address: 10.0.0.1:8080
+    metadata:
+      region: eu
+`)
+
+	r := NewRegistry(Path(path))
+
+	services, err := r.GetService("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Nodes) != 1 || services[0].Nodes[0].Address != "10.0.0.1:8080" {
+		t.Fatalf("Unexpected services %+v", services)
+	}
+	if services[0].Nodes[0].Metadata["region"] != "eu" {
+		t.Fatalf("Expected the metadata of the node, got %v", services[0].Nodes[0].Metadata)
+	}
+
+	w, err := r.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// a node is added
+	writeFile(t, path, `
+services:
+- name: foo
+  version: "1"
+  nodes:
+  - id: foo-1
+    address: 10.0.0.1:8080
+    metadata:
+      region: eu
+  - id: foo-2
+    address: 10.0.0.2:8080
+`)
+	expect(t, next(t, w), "update", "foo", "foo-1", "foo-2")
+
+	// a node is removed and another service added
+	writeFile(t, path, `
+services:
+- name: foo
+  version: "1"
+  nodes:
+  - id: foo-2
+    address: 10.0.0.2:8080
+- name: bar
+  version: "1"
+  nodes:
+  - id: bar-1
+    address: 10.0.0.3:8080
+`)
+	for i := 0; i < 2; i++ {
+		res := next(t, w)
+		switch res.Service.Name {
+		case "foo":
+			expect(t, res, "delete", "foo", "foo-1")
+		default:
+			expect(t, res, "create", "bar", "bar-1")
+		}
+	}
+
+	// the services registered are served along with those in the file
+	local := &registry.Service{
+		Name:    "foo",
+		Version: "1",
+		Nodes:   []*registry.Node{{Id: "foo-3", Address: "10.0.0.4:8080"}},
+	}
+	if err := r.Register(local); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, next(t, w), "update", "foo", "foo-2", "foo-3")
+
+	if err := r.Deregister(local); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, next(t, w), "delete", "foo", "foo-3")
+
+	// a file which can't be decoded keeps the services loaded before
+	writeFile(t, path, `services: [`)
+	time.Sleep(3 * loadTime)
+
+	services, err = r.ListServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(services))
+	}
+
+	// a service is removed
+	writeFile(t, path, `{"services": [{"name": "bar", "version": "1", "nodes": [{"id": "bar-1", "address": "10.0.0.3:8080"}]}]}`)
+	expect(t, next(t, w), "delete", "foo", "foo-2")
+
+	if _, err := r.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+}
+
+func TestFileRegistryDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile(t, filepath.Join(dir, "foo.json"), `{"services": [{"name": "foo", "version": "1", "nodes": [{"id": "foo-1", "address": "10.0.0.1:8080"}]}]}`)
+	// files of other formats are ignored
+	writeFile(t, filepath.Join(dir, "README"), `services`)
+
+	r := NewRegistry(Path(dir))
+
+	services, err := r.ListServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Name != "foo" {
+		t.Fatalf("Unexpected services %+v", services)
+	}
+
+	w, err := r.Watch(registry.WatchService("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// a file is added
+	writeFile(t, filepath.Join(dir, "bar.toml"), `
+[[services]]
+name = "bar"
+version = "1"
+
+[[services.nodes]]
+id = "bar-1"
+address = "10.0.0.2:8080"
+`)
+	expect(t, next(t, w), "create", "bar", "bar-1")
+
+	// the file is removed
+	if err := os.Remove(filepath.Join(dir, "bar.toml")); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, next(t, w), "delete", "bar", "bar-1")
+}
+
+func TestFileRegistryZone(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "registry.zone")
+	writeFile(t, path, `
+$ORIGIN example.com.
+$TTL 60
+_foo._tcp  IN SRV 0 0 8080 foo-1
+_foo._tcp  IN SRV 0 0 8081 foo-2.example.com.
+_foo._tcp  IN TXT "version=1" "team=edge"
+foo-1      IN A   10.0.0.1
+foo-1      IN TXT "region=eu"
+foo-2      IN AAAA ::1
+`)
+
+	r := NewRegistry(registry.Addrs(path))
+
+	services, err := r.GetService("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Version != "1" || services[0].Metadata["team"] != "edge" {
+		t.Fatalf("Unexpected services %+v", services)
+	}
+
+	nodes := services[0].Nodes
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(nodes))
+	}
+	if nodes[0].Id != "foo-1.example.com" || nodes[0].Address != "10.0.0.1:8080" || nodes[0].Metadata["region"] != "eu" {
+		t.Fatalf("Unexpected node %+v", nodes[0])
+	}
+	if nodes[1].Id != "foo-2.example.com" || nodes[1].Address != "[::1]:8081" {
+		t.Fatalf("Unexpected node %+v", nodes[1])
+	}
+
+	w, err := r.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// a node is removed
+	writeFile(t, path, `
+$ORIGIN example.com.
+$TTL 60
+_foo._tcp  IN SRV 0 0 8080 foo-1
+_foo._tcp  IN TXT "version=1" "team=edge"
+foo-1      IN A   10.0.0.1
+foo-1      IN TXT "region=eu"
+`)
+	expect(t, next(t, w), "delete", "foo", "foo-2.example.com")
+}
+
+func TestFileRegistryConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var i int
+	test.Run(t, func() registry.Registry {
+		i++
+		path := filepath.Join(dir, fmt.Sprintf("registry-%d.json", i))
+		writeFile(t, path, `{"services": []}`)
+		return NewRegistry(Path(path))
+	})
+}
@@ -0,0 +1,21 @@
+package file
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+type pathKey struct{}
+
+// Path sets the file, or the directory of files, the services are read from.
+// The format of a file is taken from its extension, which is json, yaml, yml, toml
+// or zone. If it isn't set the first of the registry addresses is the path.
+func Path(p string) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, pathKey{}, p)
+	}
+}
@@ -0,0 +1,168 @@
+package file
+
+import (
+	"reflect"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+func copyNode(n *registry.Node) *registry.Node {
+	metadata := make(map[string]string, len(n.Metadata))
+	for k, v := range n.Metadata {
+		metadata[k] = v
+	}
+
+	return &registry.Node{
+		Id:       n.Id,
+		Address:  n.Address,
+		Metadata: metadata,
+	}
+}
+
+func copyService(s *registry.Service) *registry.Service {
+	metadata := make(map[string]string, len(s.Metadata))
+	for k, v := range s.Metadata {
+		metadata[k] = v
+	}
+
+	endpoints := make([]*registry.Endpoint, len(s.Endpoints))
+	copy(endpoints, s.Endpoints)
+
+	nodes := make([]*registry.Node, len(s.Nodes))
+	for i, n := range s.Nodes {
+		nodes[i] = copyNode(n)
+	}
+
+	return &registry.Service{
+		Name:      s.Name,
+		Version:   s.Version,
+		Metadata:  metadata,
+		Endpoints: endpoints,
+		Nodes:     nodes,
+	}
+}
+
+// add a copy of the service to the services. A service which is already there
+// gets the nodes added, replacing those with the same id.
+func add(services map[string]map[string]*registry.Service, s *registry.Service) {
+	if _, ok := services[s.Name]; !ok {
+		services[s.Name] = make(map[string]*registry.Service)
+	}
+
+	cur, ok := services[s.Name][s.Version]
+	if !ok {
+		services[s.Name][s.Version] = copyService(s)
+		return
+	}
+
+	for k, v := range s.Metadata {
+		if _, ok := cur.Metadata[k]; !ok {
+			cur.Metadata[k] = v
+		}
+	}
+	if len(cur.Endpoints) == 0 {
+		cur.Endpoints = copyService(s).Endpoints
+	}
+
+	for _, n := range s.Nodes {
+		var seen bool
+		for i, node := range cur.Nodes {
+			if node.Id == n.Id {
+				cur.Nodes[i] = copyNode(n)
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			cur.Nodes = append(cur.Nodes, copyNode(n))
+		}
+	}
+}
+
+// remove the nodes of the service from the services, and the service once
+// it has none left
+func remove(services map[string]map[string]*registry.Service, s *registry.Service) {
+	cur, ok := services[s.Name][s.Version]
+	if !ok {
+		return
+	}
+
+	var nodes []*registry.Node
+	for _, node := range cur.Nodes {
+		var seen bool
+		for _, n := range s.Nodes {
+			if n.Id == node.Id {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			nodes = append(nodes, node)
+		}
+	}
+	cur.Nodes = nodes
+
+	if len(cur.Nodes) == 0 {
+		delete(services[s.Name], s.Version)
+	}
+	if len(services[s.Name]) == 0 {
+		delete(services, s.Name)
+	}
+}
+
+// diff returns the events turning the old services into the new ones. Nodes which
+// are gone are deleted before the services with changes are updated, the same as
+// registries whose nodes come and go one at a time.
+func diff(old, new map[string]map[string]*registry.Service) []*registry.Result {
+	var results []*registry.Result
+
+	for name, versions := range new {
+		for version, s := range versions {
+			o, ok := old[name][version]
+			if !ok {
+				results = append(results, &registry.Result{Action: "create", Service: copyService(s)})
+				continue
+			}
+
+			kept := copyService(o)
+			kept.Nodes = nil
+			gone := copyService(o)
+			gone.Nodes = nil
+
+			for _, node := range o.Nodes {
+				var seen bool
+				for _, n := range s.Nodes {
+					if n.Id == node.Id {
+						seen = true
+						break
+					}
+				}
+				if seen {
+					kept.Nodes = append(kept.Nodes, copyNode(node))
+				} else {
+					gone.Nodes = append(gone.Nodes, copyNode(node))
+				}
+			}
+
+			if len(gone.Nodes) > 0 {
+				results = append(results, &registry.Result{Action: "delete", Service: gone})
+			}
+			if kept.Nodes == nil {
+				kept.Nodes = []*registry.Node{}
+			}
+			if !reflect.DeepEqual(kept, s) {
+				results = append(results, &registry.Result{Action: "update", Service: copyService(s)})
+			}
+		}
+	}
+
+	for name, versions := range old {
+		for version, o := range versions {
+			if _, ok := new[name][version]; !ok {
+				results = append(results, &registry.Result{Action: "delete", Service: copyService(o)})
+			}
+		}
+	}
+
+	return results
+}
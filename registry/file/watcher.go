@@ -0,0 +1,35 @@
+package file
+
+import (
+	"github.com/micro/go-micro/v2/registry"
+)
+
+type Watcher struct {
+	id   string
+	wo   registry.WatchOptions
+	res  chan *registry.Result
+	exit chan bool
+}
+
+func (w *Watcher) Next() (*registry.Result, error) {
+	for {
+		select {
+		case r := <-w.res:
+			if len(w.wo.Service) > 0 && w.wo.Service != r.Service.Name {
+				continue
+			}
+			return r, nil
+		case <-w.exit:
+			return nil, registry.ErrWatcherStopped
+		}
+	}
+}
+
+func (w *Watcher) Stop() {
+	select {
+	case <-w.exit:
+		return
+	default:
+		close(w.exit)
+	}
+}
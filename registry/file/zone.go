@@ -0,0 +1,165 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/miekg/dns"
+)
+
+// zoneEncoder decodes the services of a DNS zone file. Every SRV record named
+// _<service>._tcp or _<service>._udp is a node of the service, addressed by
+// the A and AAAA records of its target, or by the target itself if it has none.
+// The key=value strings of the TXT records of the SRV record name are the
+// metadata of the service, with the version in the version key, and those of
+// the target are the metadata of the node, e.g.
+//
+//	$ORIGIN example.com.
+//	_greeter._tcp   60 IN SRV 0 0 8080 greeter-1
+//	_greeter._tcp   60 IN TXT "version=latest"
+//	greeter-1       60 IN A   10.0.0.1
+//	greeter-1       60 IN TXT "zone=eu-west-1a"
+//
+// The services of a zone file are in the default domain.
+type zoneEncoder struct{}
+
+type zoneNode struct {
+	target string
+	port   uint16
+}
+
+type zoneService struct {
+	// the SRV record name
+	owner string
+	name  string
+	nodes []zoneNode
+}
+
+func (zoneEncoder) Encode(v interface{}) ([]byte, error) {
+	return nil, errors.New("zone files can't be encoded")
+}
+
+func (zoneEncoder) Decode(b []byte, v interface{}) error {
+	f, ok := v.(*file)
+	if !ok {
+		return fmt.Errorf("can't decode a zone file into %T", v)
+	}
+
+	// the services in the order of their first SRV record
+	var services []*zoneService
+	byOwner := make(map[string]*zoneService)
+	addrs := make(map[string][]string)
+	txts := make(map[string]map[string]string)
+
+	zp := dns.NewZoneParser(bytes.NewReader(b), ".", "")
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		owner := strings.ToLower(rr.Header().Name)
+
+		switch r := rr.(type) {
+		case *dns.SRV:
+			s, ok := byOwner[owner]
+			if !ok {
+				name, err := serviceName(owner)
+				if err != nil {
+					return err
+				}
+				s = &zoneService{owner: owner, name: name}
+				byOwner[owner] = s
+				services = append(services, s)
+			}
+			s.nodes = append(s.nodes, zoneNode{target: strings.ToLower(r.Target), port: r.Port})
+		case *dns.A:
+			addrs[owner] = append(addrs[owner], r.A.String())
+		case *dns.AAAA:
+			addrs[owner] = append(addrs[owner], r.AAAA.String())
+		case *dns.TXT:
+			if _, ok := txts[owner]; !ok {
+				txts[owner] = make(map[string]string)
+			}
+			for _, txt := range r.Txt {
+				parts := strings.SplitN(txt, "=", 2)
+				if len(parts) != 2 {
+					continue
+				}
+				txts[owner][parts[0]] = parts[1]
+			}
+		}
+	}
+	if err := zp.Err(); err != nil {
+		return err
+	}
+
+	// services by name and version
+	versions := make(map[string]*registry.Service)
+
+	for _, zs := range services {
+		metadata := make(map[string]string)
+		version := "latest"
+		for k, v := range txts[zs.owner] {
+			if k == "version" {
+				version = v
+				continue
+			}
+			metadata[k] = v
+		}
+
+		s, ok := versions[zs.name+":"+version]
+		if !ok {
+			s = &registry.Service{
+				Name:     zs.name,
+				Version:  version,
+				Metadata: metadata,
+			}
+			versions[zs.name+":"+version] = s
+			f.Services = append(f.Services, s)
+		}
+
+		for _, n := range zs.nodes {
+			id := strings.TrimSuffix(n.target, ".")
+
+			hosts := addrs[n.target]
+			if len(hosts) == 0 {
+				hosts = []string{id}
+			}
+
+			for i, host := range hosts {
+				metadata := make(map[string]string, len(txts[n.target]))
+				for k, v := range txts[n.target] {
+					metadata[k] = v
+				}
+
+				// a target with several addresses is a node per address
+				nid := id
+				if i > 0 {
+					nid = fmt.Sprintf("%s-%d", id, i)
+				}
+
+				s.Nodes = append(s.Nodes, &registry.Node{
+					Id:       nid,
+					Address:  net.JoinHostPort(host, strconv.Itoa(int(n.port))),
+					Metadata: metadata,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+func (zoneEncoder) String() string {
+	return "zone"
+}
+
+// serviceName returns the service of the SRV record name _<service>._<proto>
+func serviceName(owner string) (string, error) {
+	labels := dns.SplitDomainName(owner)
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || (labels[1] != "_tcp" && labels[1] != "_udp") {
+		return "", fmt.Errorf("SRV record %s isn't named _<service>._tcp or _<service>._udp", owner)
+	}
+	return strings.TrimPrefix(labels[0], "_"), nil
+}
@@ -0,0 +1,392 @@
+// Package kubernetes provides a registry storing services in the pods they run in.
+// A service registered is stored in an annotation of the pod, along with labels
+// selecting the pods of the service, and pods are listed and watched through the
+// kubernetes api. Nodes go away along with their pod, so no ttl is needed.
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/util/kubernetes/client"
+)
+
+var (
+	// DefaultAddress is the address of the api server served by `kubectl proxy`,
+	// used when neither an address is set nor the process runs in a pod
+	DefaultAddress = "http://localhost:8001"
+
+	// labelTypeKey is the label of the pods with services
+	labelTypeKey   = "micro.mu/type"
+	labelTypeValue = "service"
+	// labelServicePrefix is the prefix of the labels selecting the pods of a service
+	labelServicePrefix = "micro.mu/selector-"
+	// annotationServicePrefix is the prefix of the annotations of the services,
+	// which hold the service registered in each domain encoded in json
+	annotationServicePrefix = "micro.mu/service-"
+	// podRunning is the phase of the pods running
+	podRunning = "Running"
+)
+
+type kregistry struct {
+	sync.RWMutex
+	options   registry.Options
+	client    client.Client
+	namespace string
+	pod       string
+
+	// the services registered in the pod by name and domain,
+	// which are all written to its annotation at once
+	mtx      sync.Mutex
+	services map[string]map[string]*registry.Service
+}
+
+func serviceLabel(name string) string {
+	return labelServicePrefix + name
+}
+
+func serviceAnnotation(name string) string {
+	return annotationServicePrefix + name
+}
+
+// domainOf returns the domain set in the options, or the default domain if none is
+func domainOf(domain string) string {
+	if len(domain) == 0 {
+		return registry.DefaultDomain
+	}
+	return domain
+}
+
+// podServices returns the services of the pod by name and domain,
+// or none if the pod isn't running
+func podServices(pod *client.Pod) map[string]map[string]*registry.Service {
+	if pod.Metadata == nil || pod.Status == nil || pod.Status.Phase != podRunning {
+		return nil
+	}
+
+	services := make(map[string]map[string]*registry.Service)
+
+	for k, v := range pod.Metadata.Annotations {
+		if !strings.HasPrefix(k, annotationServicePrefix) || len(v) == 0 {
+			continue
+		}
+
+		var domains map[string]*registry.Service
+		if err := json.Unmarshal([]byte(v), &domains); err != nil {
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("Registry skipping annotation %s of pod %s: %v", k, pod.Metadata.Name, err)
+			}
+			continue
+		}
+
+		name := strings.TrimPrefix(k, annotationServicePrefix)
+		for domain, s := range domains {
+			if s == nil || len(s.Nodes) == 0 {
+				continue
+			}
+			if _, ok := services[name]; !ok {
+				services[name] = make(map[string]*registry.Service)
+			}
+			services[name][domain] = s
+		}
+	}
+
+	return services
+}
+
+func (k *kregistry) configure() {
+	c, ok := k.options.Context.Value(clientKey{}).(client.Client)
+	if !ok {
+		switch {
+		case len(k.options.Addrs) > 0:
+			c = client.NewLocalClient(k.options.Addrs...)
+		case len(os.Getenv("KUBERNETES_SERVICE_HOST")) > 0:
+			c = client.NewClusterClient()
+		default:
+			c = client.NewLocalClient(DefaultAddress)
+		}
+	}
+
+	namespace, ok := k.options.Context.Value(namespaceKey{}).(string)
+	if !ok {
+		namespace = client.DefaultNamespace
+	}
+
+	pod, ok := k.options.Context.Value(podKey{}).(string)
+	if !ok {
+		pod = os.Getenv("HOSTNAME")
+	}
+
+	k.Lock()
+	k.client = c
+	k.namespace = namespace
+	k.pod = pod
+	k.Unlock()
+}
+
+func (k *kregistry) getClient() (client.Client, string, string) {
+	k.RLock()
+	defer k.RUnlock()
+	return k.client, k.namespace, k.pod
+}
+
+// patch the pod with the annotation and labels of the service, which are
+// emptied once the service isn't registered anymore.
+// Must be called with the services lock held so patches are applied in order.
+func (k *kregistry) patch(name string) error {
+	c, namespace, pod := k.getClient()
+	if len(pod) == 0 {
+		return errors.New("pod name not set")
+	}
+
+	var annotation, label, typ string
+	if domains, ok := k.services[name]; ok {
+		b, err := json.Marshal(domains)
+		if err != nil {
+			return err
+		}
+		annotation, label = string(b), labelTypeValue
+	}
+	if len(k.services) > 0 {
+		typ = labelTypeValue
+	}
+
+	return c.Update(&client.Resource{
+		Kind: "pod",
+		Name: pod,
+		Value: &client.Pod{
+			Metadata: &client.Metadata{
+				Labels: map[string]string{
+					labelTypeKey:       typ,
+					serviceLabel(name): label,
+				},
+				Annotations: map[string]string{
+					serviceAnnotation(name): annotation,
+				},
+			},
+		},
+	}, client.UpdateNamespace(namespace))
+}
+
+// lookup the services of the pods in the domain, those with the name if set
+func (k *kregistry) lookup(name, domain string) ([]*registry.Service, error) {
+	c, namespace, _ := k.getClient()
+
+	labels := map[string]string{labelTypeKey: labelTypeValue}
+	if len(name) > 0 {
+		labels = map[string]string{serviceLabel(name): labelTypeValue}
+	}
+
+	var pods client.PodList
+	if err := c.Get(&client.Resource{Kind: "pod", Value: &pods}, client.GetNamespace(namespace), client.GetLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		name    string
+		version string
+		domain  string
+	}
+
+	var services []*registry.Service
+	seen := make(map[key]*registry.Service)
+
+	for i := range pods.Items {
+		for n, domains := range podServices(&pods.Items[i]) {
+			if len(name) > 0 && n != name {
+				continue
+			}
+
+			for d, s := range domains {
+				if domain != registry.WildcardDomain && d != domain {
+					continue
+				}
+
+				k := key{n, s.Version, d}
+				cur, ok := seen[k]
+				if !ok {
+					cur = &registry.Service{
+						Name:      n,
+						Version:   s.Version,
+						Metadata:  s.Metadata,
+						Endpoints: s.Endpoints,
+					}
+					if domain == registry.WildcardDomain {
+						if cur.Metadata == nil {
+							cur.Metadata = make(map[string]string)
+						}
+						cur.Metadata[registry.DomainKey] = d
+					}
+					seen[k] = cur
+					services = append(services, cur)
+				}
+
+				cur.Nodes = append(cur.Nodes, s.Nodes...)
+			}
+		}
+	}
+
+	return services, nil
+}
+
+func (k *kregistry) Init(opts ...registry.Option) error {
+	k.Lock()
+	for _, o := range opts {
+		o(&k.options)
+	}
+	k.Unlock()
+
+	k.configure()
+	return nil
+}
+
+func (k *kregistry) Options() registry.Options {
+	k.RLock()
+	defer k.RUnlock()
+	return k.options
+}
+
+func (k *kregistry) Register(s *registry.Service, opts ...registry.RegisterOption) error {
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	domain := domainOf(options.Domain)
+
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if _, ok := k.services[s.Name]; !ok {
+		k.services[s.Name] = make(map[string]*registry.Service)
+	}
+
+	// keep the nodes registered before which aren't registered again
+	service := &registry.Service{
+		Name:      s.Name,
+		Version:   s.Version,
+		Metadata:  s.Metadata,
+		Endpoints: s.Endpoints,
+		Nodes:     s.Nodes,
+	}
+	if cur, ok := k.services[s.Name][domain]; ok && cur.Version == s.Version {
+		for _, n := range cur.Nodes {
+			var found bool
+			for _, sn := range s.Nodes {
+				if sn.Id == n.Id {
+					found = true
+					break
+				}
+			}
+			if !found {
+				service.Nodes = append(service.Nodes, n)
+			}
+		}
+	}
+
+	k.services[s.Name][domain] = service
+
+	return k.patch(s.Name)
+}
+
+func (k *kregistry) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	domain := domainOf(options.Domain)
+
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	cur, ok := k.services[s.Name][domain]
+	if !ok {
+		return nil
+	}
+
+	var nodes []*registry.Node
+	for _, n := range cur.Nodes {
+		var found bool
+		for _, sn := range s.Nodes {
+			if sn.Id == n.Id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			nodes = append(nodes, n)
+		}
+	}
+
+	if len(nodes) > 0 {
+		service := *cur
+		service.Nodes = nodes
+		k.services[s.Name][domain] = &service
+	} else {
+		delete(k.services[s.Name], domain)
+		if len(k.services[s.Name]) == 0 {
+			delete(k.services, s.Name)
+		}
+	}
+
+	return k.patch(s.Name)
+}
+
+func (k *kregistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	services, err := k.lookup(name, domainOf(options.Domain))
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, registry.ErrNotFound
+	}
+
+	return services, nil
+}
+
+func (k *kregistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return k.lookup("", domainOf(options.Domain))
+}
+
+func (k *kregistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	return newWatcher(k, opts...)
+}
+
+func (k *kregistry) String() string {
+	return "kubernetes"
+}
+
+// NewRegistry returns a registry storing services in the pods they run in
+func NewRegistry(opts ...registry.Option) registry.Registry {
+	options := registry.Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	k := &kregistry{
+		options:  options,
+		services: make(map[string]map[string]*registry.Service),
+	}
+
+	k.configure()
+
+	return k
+}
@@ -0,0 +1,365 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/util/kubernetes/api"
+	"github.com/micro/go-micro/v2/util/kubernetes/client"
+)
+
+const podsPath = "/api/v1/namespaces/default/pods/"
+
+type watch struct {
+	selector string
+	events   chan client.Event
+}
+
+// apiServer is a fake kubernetes api server serving the pods
+type apiServer struct {
+	sync.Mutex
+	pods     map[string]*client.Pod
+	watchers map[*watch]bool
+}
+
+// matches returns true if the pod has the labels of the selector
+func matches(pod *client.Pod, selector string) bool {
+	if pod == nil {
+		return false
+	}
+	for _, l := range strings.Split(selector, ",") {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 || pod.Metadata.Labels[kv[0]] != kv[1] {
+			return false
+		}
+	}
+	return true
+}
+
+func event(typ client.EventType, v interface{}) client.Event {
+	b, _ := json.Marshal(v)
+	return client.Event{Type: typ, Object: b}
+}
+
+// set the pod, sending the events of the change to the watchers.
+// Must be called with the lock held.
+func (a *apiServer) set(name string, pod *client.Pod) {
+	old := a.pods[name]
+	if pod != nil {
+		a.pods[name] = pod
+	} else {
+		delete(a.pods, name)
+	}
+
+	for w := range a.watchers {
+		var ev client.Event
+		switch before, after := matches(old, w.selector), matches(pod, w.selector); {
+		case !before && after:
+			ev = event(client.Added, pod)
+		case before && after:
+			ev = event(client.Modified, pod)
+		case before && !after:
+			ev = event(client.Deleted, old)
+		default:
+			continue
+		}
+		select {
+		case w.events <- ev:
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (a *apiServer) addPod(name, ip string) {
+	a.Lock()
+	defer a.Unlock()
+	a.set(name, &client.Pod{
+		Metadata: &client.Metadata{Name: name, Namespace: "default"},
+		Status:   &client.PodStatus{Phase: podRunning, PodIP: ip},
+	})
+}
+
+func (a *apiServer) deletePod(name string) {
+	a.Lock()
+	defer a.Unlock()
+	a.set(name, nil)
+}
+
+func (a *apiServer) error(w http.ResponseWriter, code int, msg string) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(&api.Status{Kind: "Status", Status: "Failure", Message: msg, Code: code})
+}
+
+func (a *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, podsPath) {
+		a.error(w, http.StatusNotFound, "not found")
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, podsPath)
+	selector := r.URL.Query().Get("labelSelector")
+
+	switch {
+	case r.Method == "GET" && r.URL.Query().Get("watch") == "true":
+		a.watch(w, r, selector)
+	case r.Method == "GET":
+		a.Lock()
+		var pods client.PodList
+		for _, pod := range a.pods {
+			if matches(pod, selector) {
+				pods.Items = append(pods.Items, *pod)
+			}
+		}
+		a.Unlock()
+		json.NewEncoder(w).Encode(&pods)
+	case r.Method == "PATCH":
+		var patch client.Pod
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			a.error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		a.Lock()
+		defer a.Unlock()
+
+		cur, ok := a.pods[name]
+		if !ok {
+			a.error(w, http.StatusNotFound, "pod not found")
+			return
+		}
+
+		// merge the labels and annotations like a strategic merge patch
+		pod := *cur
+		meta := *cur.Metadata
+		meta.Labels = make(map[string]string)
+		meta.Annotations = make(map[string]string)
+		for _, m := range []*client.Metadata{cur.Metadata, patch.Metadata} {
+			for k, v := range m.Labels {
+				meta.Labels[k] = v
+			}
+			for k, v := range m.Annotations {
+				meta.Annotations[k] = v
+			}
+		}
+		pod.Metadata = &meta
+
+		a.set(name, &pod)
+		json.NewEncoder(w).Encode(&pod)
+	default:
+		a.error(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// watch streams the pods matching the selector, followed by their changes
+func (a *apiServer) watch(w http.ResponseWriter, r *http.Request, selector string) {
+	wt := &watch{selector: selector, events: make(chan client.Event, 16)}
+
+	a.Lock()
+	for _, pod := range a.pods {
+		if matches(pod, selector) {
+			wt.events <- event(client.Added, pod)
+		}
+	}
+	a.watchers[wt] = true
+	a.Unlock()
+
+	defer func() {
+		a.Lock()
+		delete(a.watchers, wt)
+		a.Unlock()
+	}()
+
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-wt.events:
+			enc.Encode(&ev)
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func newAPIServer(t *testing.T) (*apiServer, *httptest.Server) {
+	a := &apiServer{
+		pods:     make(map[string]*client.Pod),
+		watchers: make(map[*watch]bool),
+	}
+	return a, httptest.NewServer(a)
+}
+
+func newTestRegistry(url, pod string) registry.Registry {
+	return NewRegistry(Client(client.NewLocalClient(url)), Pod(pod))
+}
+
+func service(id, address string) *registry.Service {
+	return &registry.Service{
+		Name:    "foo",
+		Version: "1",
+		Nodes:   []*registry.Node{{Id: id, Address: address}},
+	}
+}
+
+func TestKubernetesRegistry(t *testing.T) {
+	a, s := newAPIServer(t)
+	defer s.Close()
+
+	a.addPod("foo-1", "10.0.0.1")
+	a.addPod("foo-2", "10.0.0.2")
+	r1 := newTestRegistry(s.URL, "foo-1")
+	r2 := newTestRegistry(s.URL, "foo-2")
+
+	if _, err := r1.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	if err := r1.Register(service("foo-1", "10.0.0.1:8080")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r2.Register(service("foo-2", "10.0.0.2:8080")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r2.Register(service("bar-1", "10.0.0.2:8081"), registry.RegisterDomain("acme")); err != nil {
+		t.Fatal(err)
+	}
+
+	services, err := r1.GetService("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Nodes) != 2 {
+		t.Fatalf("Expected 1 service with 2 nodes, got %+v", services)
+	}
+
+	services, err = r1.GetService("foo", registry.GetDomain("acme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Nodes) != 1 || services[0].Nodes[0].Id != "bar-1" {
+		t.Fatalf("Expected bar-1 in the acme domain, got %+v", services)
+	}
+
+	services, err = r1.ListServices(registry.ListDomain(registry.WildcardDomain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(services))
+	}
+	for _, s := range services {
+		if d := s.Metadata[registry.DomainKey]; d != registry.DefaultDomain && d != "acme" {
+			t.Fatalf("Unexpected domain %q", d)
+		}
+	}
+
+	// the services of the pods which aren't running are left out
+	a.deletePod("foo-2")
+	services, err = r1.GetService("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Nodes) != 1 || services[0].Nodes[0].Id != "foo-1" {
+		t.Fatalf("Expected foo-1, got %+v", services)
+	}
+
+	if err := r1.Deregister(service("foo-1", "10.0.0.1:8080")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r1.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	// the pod to register in must exist
+	if err := newTestRegistry(s.URL, "foo-3").Register(service("foo-3", "10.0.0.3:8080")); err == nil {
+		t.Fatal("Expected an error registering in a pod which doesn't exist")
+	}
+}
+
+func TestKubernetesWatcher(t *testing.T) {
+	a, s := newAPIServer(t)
+	defer s.Close()
+
+	a.addPod("foo-1", "10.0.0.1")
+	a.addPod("foo-2", "10.0.0.2")
+	r1 := newTestRegistry(s.URL, "foo-1")
+	r2 := newTestRegistry(s.URL, "foo-2")
+
+	// the services registered before watching are created
+	if err := r1.Register(service("foo-1", "10.0.0.1:8080")); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := r1.Watch(registry.WatchService("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	next := func(action string, ids ...string) {
+		t.Helper()
+
+		ch := make(chan *registry.Result, 1)
+		go func() {
+			res, err := w.Next()
+			if err == nil {
+				ch <- res
+			}
+		}()
+
+		var res *registry.Result
+		select {
+		case res = <-ch:
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %s", action)
+		}
+
+		if res.Action != action || len(res.Service.Nodes) != len(ids) {
+			t.Fatalf("Expected %s of %v, got %s of %+v", action, ids, res.Action, res.Service.Nodes)
+		}
+		for i, id := range ids {
+			if res.Service.Nodes[i].Id != id {
+				t.Fatalf("Expected %s of %v, got %s of %+v", action, ids, res.Action, res.Service.Nodes)
+			}
+		}
+	}
+
+	next("create", "foo-1")
+
+	if err := r2.Register(service("foo-2", "10.0.0.2:8080")); err != nil {
+		t.Fatal(err)
+	}
+	next("create", "foo-2")
+
+	// the services of other domains aren't watched
+	if err := r2.Register(service("bar-1", "10.0.0.2:8081"), registry.RegisterDomain("acme")); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := service("foo-2", "10.0.0.2:9090")
+	if err := r2.Register(svc); err != nil {
+		t.Fatal(err)
+	}
+	next("update", "foo-2")
+
+	if err := r2.Deregister(svc); err != nil {
+		t.Fatal(err)
+	}
+	next("delete", "foo-2")
+
+	a.deletePod("foo-1")
+	next("delete", "foo-1")
+
+	w.Stop()
+	if _, err := w.Next(); err != registry.ErrWatcherStopped {
+		t.Fatalf("Expected %v, got %v", registry.ErrWatcherStopped, err)
+	}
+}
@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/util/kubernetes/client"
+)
+
+type clientKey struct{}
+type namespaceKey struct{}
+type podKey struct{}
+
+// Client sets the kubernetes client used to talk to the api server. Defaults to
+// a client of the api server at the registry address if set, the one of the
+// cluster when running in a pod, or the one served by `kubectl proxy` otherwise.
+func Client(c client.Client) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, clientKey{}, c)
+	}
+}
+
+// Namespace sets the namespace of the pods. Defaults to the default namespace.
+func Namespace(ns string) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, namespaceKey{}, ns)
+	}
+}
+
+// Pod sets the name of the pod services are registered in.
+// Defaults to the hostname, which kubernetes sets to the pod name.
+func Pod(name string) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, podKey{}, name)
+	}
+}
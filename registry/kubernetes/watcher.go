@@ -0,0 +1,202 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/util/kubernetes/api"
+	"github.com/micro/go-micro/v2/util/kubernetes/client"
+)
+
+type kwatcher struct {
+	wo   registry.WatchOptions
+	w    client.Watcher
+	exit chan bool
+
+	// the services of the pods by pod name, name and domain
+	pods map[string]map[string]map[string]*registry.Service
+	// the results of the last event which weren't returned yet
+	next []*registry.Result
+}
+
+// result returns the result of the service in the domain, with
+// the domain in the metadata when watching all the domains
+func (w *kwatcher) result(action, domain string, s *registry.Service) *registry.Result {
+	service := *s
+	if w.wo.Domain == registry.WildcardDomain {
+		service.Metadata = make(map[string]string)
+		for k, v := range s.Metadata {
+			service.Metadata[k] = v
+		}
+		service.Metadata[registry.DomainKey] = domain
+	}
+	return &registry.Result{Action: action, Service: &service}
+}
+
+// diff returns the results of the services of the pod changing
+func (w *kwatcher) diff(pod string, services map[string]map[string]*registry.Service) []*registry.Result {
+	old := w.pods[pod]
+	if len(services) > 0 {
+		w.pods[pod] = services
+	} else {
+		delete(w.pods, pod)
+	}
+
+	names := make(map[string]bool)
+	for name := range old {
+		names[name] = true
+	}
+	for name := range services {
+		names[name] = true
+	}
+
+	var sorted []string
+	for name := range names {
+		if len(w.wo.Service) > 0 && name != w.wo.Service {
+			continue
+		}
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var results []*registry.Result
+
+	for _, name := range sorted {
+		for domain, s := range old[name] {
+			if w.wo.Domain != registry.WildcardDomain && domain != w.wo.Domain {
+				continue
+			}
+
+			cur, ok := services[name][domain]
+			if !ok || cur.Version != s.Version {
+				results = append(results, w.result("delete", domain, s))
+				continue
+			}
+
+			// the nodes gone are deleted, as updates keep the nodes seen before
+			var nodes []*registry.Node
+			for _, n := range s.Nodes {
+				var found bool
+				for _, cn := range cur.Nodes {
+					if cn.Id == n.Id {
+						found = true
+						break
+					}
+				}
+				if !found {
+					nodes = append(nodes, n)
+				}
+			}
+			if len(nodes) > 0 {
+				deleted := *s
+				deleted.Nodes = nodes
+				results = append(results, w.result("delete", domain, &deleted))
+			}
+
+			if !reflect.DeepEqual(s, cur) {
+				results = append(results, w.result("update", domain, cur))
+			}
+		}
+
+		for domain, s := range services[name] {
+			if w.wo.Domain != registry.WildcardDomain && domain != w.wo.Domain {
+				continue
+			}
+			if cur, ok := old[name][domain]; !ok || cur.Version != s.Version {
+				results = append(results, w.result("create", domain, s))
+			}
+		}
+	}
+
+	return results
+}
+
+func (w *kwatcher) Next() (*registry.Result, error) {
+	for len(w.next) == 0 {
+		var ev client.Event
+		var ok bool
+
+		select {
+		case <-w.exit:
+			return nil, registry.ErrWatcherStopped
+		case ev, ok = <-w.w.Chan():
+		}
+
+		if !ok {
+			select {
+			case <-w.exit:
+				return nil, registry.ErrWatcherStopped
+			default:
+				return nil, errors.New("kubernetes watch ended")
+			}
+		}
+
+		if ev.Type == client.Error {
+			var status api.Status
+			if err := json.Unmarshal(ev.Object, &status); err != nil || len(status.Message) == 0 {
+				return nil, errors.New("kubernetes watch failed")
+			}
+			return nil, errors.New(status.Message)
+		}
+
+		var pod client.Pod
+		if err := json.Unmarshal(ev.Object, &pod); err != nil || pod.Metadata == nil {
+			continue
+		}
+
+		var services map[string]map[string]*registry.Service
+		if ev.Type != client.Deleted {
+			services = podServices(&pod)
+		}
+
+		w.next = w.diff(pod.Metadata.Name, services)
+	}
+
+	res := w.next[0]
+	w.next = w.next[1:]
+	return res, nil
+}
+
+func (w *kwatcher) Stop() {
+	select {
+	case <-w.exit:
+	default:
+		close(w.exit)
+		w.w.Stop()
+	}
+}
+
+func newWatcher(k *kregistry, opts ...registry.WatchOption) (registry.Watcher, error) {
+	var wo registry.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+	wo.Domain = domainOf(wo.Domain)
+
+	selector := labelTypeKey + "=" + labelTypeValue
+	if len(wo.Service) > 0 {
+		selector = serviceLabel(wo.Service) + "=" + labelTypeValue
+	}
+
+	c, namespace, _ := k.getClient()
+
+	// the pods which are running are sent first, so their services are created
+	w, err := c.Watch(
+		&client.Resource{Kind: "pod"},
+		client.WatchNamespace(namespace),
+		client.WatchParams(map[string]string{"labelSelector": selector}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kwatcher{
+		wo:   wo,
+		w:    w,
+		exit: make(chan bool),
+		pods: make(map[string]map[string]map[string]*registry.Service),
+	}, nil
+}
@@ -39,11 +39,13 @@ type mdnsEntry struct {
 
 type mdnsRegistry struct {
 	opts Options
-	// the mdns domain
+	// the mdns domain of the default domain, the mdns
+	// domain of the other domains is their name
 	domain string
 
 	sync.Mutex
-	services map[string][]*mdnsEntry
+	// the entries of the services by domain
+	domains map[string]map[string][]*mdnsEntry
 
 	mtx sync.RWMutex
 
@@ -148,11 +150,42 @@ func newRegistry(opts ...Option) Registry {
 	return &mdnsRegistry{
 		opts:     options,
 		domain:   domain,
-		services: make(map[string][]*mdnsEntry),
+		domains:  make(map[string]map[string][]*mdnsEntry),
 		watchers: make(map[string]*mdnsWatcher),
 	}
 }
 
+// mdnsDomain returns the mdns domain of the domain
+func (m *mdnsRegistry) mdnsDomain(domain string) string {
+	if len(domain) == 0 || domain == DefaultDomain {
+		return m.domain
+	}
+	return domain
+}
+
+// lookupDomains returns the domains to look up, which for the wildcard domain are
+// the default domain and those this process registered services in, as there's no
+// way to find the domains of the services on the network
+func (m *mdnsRegistry) lookupDomains(domain string) []string {
+	if len(domain) == 0 {
+		return []string{DefaultDomain}
+	}
+	if domain != WildcardDomain {
+		return []string{domain}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	domains := []string{DefaultDomain}
+	for d := range m.domains {
+		if d != DefaultDomain {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
 func (m *mdnsRegistry) Init(opts ...Option) error {
 	for _, o := range opts {
 		o(&m.opts)
@@ -165,16 +198,29 @@ func (m *mdnsRegistry) Options() Options {
 }
 
 func (m *mdnsRegistry) Register(service *Service, opts ...RegisterOption) error {
+	var options RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.Domain) == 0 {
+		options.Domain = DefaultDomain
+	}
+
 	m.Lock()
 	defer m.Unlock()
 
-	entries, ok := m.services[service.Name]
+	if _, ok := m.domains[options.Domain]; !ok {
+		m.domains[options.Domain] = make(map[string][]*mdnsEntry)
+	}
+	domain := m.mdnsDomain(options.Domain)
+
+	entries, ok := m.domains[options.Domain][service.Name]
 	// first entry, create wildcard used for list queries
 	if !ok {
 		s, err := mdns.NewMDNSService(
 			service.Name,
 			"_services",
-			m.domain+".",
+			domain+".",
 			"",
 			9999,
 			[]net.IP{net.ParseIP("0.0.0.0")},
@@ -241,7 +287,7 @@ func (m *mdnsRegistry) Register(service *Service, opts ...RegisterOption) error
 		s, err := mdns.NewMDNSService(
 			node.Id,
 			service.Name,
-			m.domain+".",
+			domain+".",
 			"",
 			port,
 			[]net.IP{net.ParseIP(host)},
@@ -264,19 +310,32 @@ func (m *mdnsRegistry) Register(service *Service, opts ...RegisterOption) error
 	}
 
 	// save
-	m.services[service.Name] = entries
+	m.domains[options.Domain][service.Name] = entries
 
 	return gerr
 }
 
 func (m *mdnsRegistry) Deregister(service *Service, opts ...DeregisterOption) error {
+	var options DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.Domain) == 0 {
+		options.Domain = DefaultDomain
+	}
+
 	m.Lock()
 	defer m.Unlock()
 
+	services, ok := m.domains[options.Domain]
+	if !ok {
+		return nil
+	}
+
 	var newEntries []*mdnsEntry
 
 	// loop existing entries, check if any match, shutdown those that do
-	for _, entry := range m.services[service.Name] {
+	for _, entry := range services[service.Name] {
 		var remove bool
 
 		for _, node := range service.Nodes {
@@ -296,15 +355,44 @@ func (m *mdnsRegistry) Deregister(service *Service, opts ...DeregisterOption) er
 	// last entry is the wildcard for list queries. Remove it.
 	if len(newEntries) == 1 && newEntries[0].id == "*" {
 		newEntries[0].node.Shutdown()
-		delete(m.services, service.Name)
+		delete(services, service.Name)
 	} else {
-		m.services[service.Name] = newEntries
+		services[service.Name] = newEntries
+	}
+
+	if len(services) == 0 {
+		delete(m.domains, options.Domain)
 	}
 
 	return nil
 }
 
 func (m *mdnsRegistry) GetService(service string, opts ...GetOption) ([]*Service, error) {
+	var options GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	var services []*Service
+
+	for _, domain := range m.lookupDomains(options.Domain) {
+		srvs, err := m.getService(service, domain)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range srvs {
+			if options.Domain == WildcardDomain {
+				s.Metadata = map[string]string{DomainKey: domain}
+			}
+			services = append(services, s)
+		}
+	}
+
+	return services, nil
+}
+
+// getService looks up the service in the domain
+func (m *mdnsRegistry) getService(service, domain string) ([]*Service, error) {
 	serviceMap := make(map[string]*Service)
 	entries := make(chan *mdns.ServiceEntry, 10)
 	done := make(chan bool)
@@ -317,7 +405,7 @@ func (m *mdnsRegistry) GetService(service string, opts ...GetOption) ([]*Service
 	// set entries channel
 	p.Entries = entries
 	// set the domain
-	p.Domain = m.domain
+	p.Domain = m.mdnsDomain(domain)
 
 	go func() {
 		for {
@@ -327,9 +415,6 @@ func (m *mdnsRegistry) GetService(service string, opts ...GetOption) ([]*Service
 				if p.Service == "_services" {
 					continue
 				}
-				if p.Domain != m.domain {
-					continue
-				}
 				if e.TTL == 0 {
 					continue
 				}
@@ -397,6 +482,31 @@ func (m *mdnsRegistry) GetService(service string, opts ...GetOption) ([]*Service
 }
 
 func (m *mdnsRegistry) ListServices(opts ...ListOption) ([]*Service, error) {
+	var options ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	var services []*Service
+
+	for _, domain := range m.lookupDomains(options.Domain) {
+		srvs, err := m.listServices(domain)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range srvs {
+			if options.Domain == WildcardDomain {
+				s.Metadata = map[string]string{DomainKey: domain}
+			}
+			services = append(services, s)
+		}
+	}
+
+	return services, nil
+}
+
+// listServices lists the services of the domain
+func (m *mdnsRegistry) listServices(domain string) ([]*Service, error) {
 	serviceMap := make(map[string]bool)
 	entries := make(chan *mdns.ServiceEntry, 10)
 	done := make(chan bool)
@@ -409,7 +519,7 @@ func (m *mdnsRegistry) ListServices(opts ...ListOption) ([]*Service, error) {
 	// set entries channel
 	p.Entries = entries
 	// set domain
-	p.Domain = m.domain
+	p.Domain = m.mdnsDomain(domain)
 
 	var services []*Service
 
@@ -451,6 +561,9 @@ func (m *mdnsRegistry) Watch(opts ...WatchOption) (Watcher, error) {
 	for _, o := range opts {
 		o(&wo)
 	}
+	if len(wo.Domain) == 0 {
+		wo.Domain = DefaultDomain
+	}
 
 	md := &mdnsWatcher{
 		id:       uuid.New().String(),
@@ -577,10 +690,23 @@ func (m *mdnsWatcher) Next() (*Result, error) {
 			}
 
 			// skip anything without the domain we care about
-			suffix := fmt.Sprintf(".%s.%s.", service.Name, m.domain)
-			if !strings.HasSuffix(e.Name, suffix) {
+			idx := strings.LastIndex(e.Name, "."+service.Name+".")
+			if idx == -1 {
+				continue
+			}
+			domain := strings.TrimSuffix(e.Name[idx+len(service.Name)+2:], ".")
+			if domain == m.domain {
+				domain = DefaultDomain
+			}
+
+			switch m.wo.Domain {
+			case domain:
+			case WildcardDomain:
+				service.Metadata = map[string]string{DomainKey: domain}
+			default:
 				continue
 			}
+			suffix := e.Name[idx:]
 
 			var addr string
 			if len(e.AddrV4) > 0 {
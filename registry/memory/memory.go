@@ -3,6 +3,7 @@ package memory
 
 import (
 	"context"
+	"reflect"
 	"sync"
 	"time"
 
@@ -12,8 +13,7 @@ import (
 )
 
 var (
-	sendEventTime = 10 * time.Millisecond
-	ttlPruneTime  = time.Second
+	ttlPruneTime = time.Second
 )
 
 type node struct {
@@ -34,7 +34,10 @@ type Registry struct {
 	options registry.Options
 
 	sync.RWMutex
-	records  map[string]map[string]*record
+	// records by domain, service name and version
+	records map[string]map[string]map[string]*record
+
+	wmtx     sync.RWMutex
 	watchers map[string]*Watcher
 }
 
@@ -47,9 +50,9 @@ func NewRegistry(opts ...registry.Option) registry.Registry {
 		o(&options)
 	}
 
-	records := getServiceRecords(options.Context)
-	if records == nil {
-		records = make(map[string]map[string]*record)
+	records := make(map[string]map[string]map[string]*record)
+	if services := getServiceRecords(options.Context); services != nil {
+		records[registry.DefaultDomain] = services
 	}
 
 	reg := &Registry{
@@ -71,14 +74,16 @@ func (m *Registry) ttlPrune() {
 		select {
 		case <-prune.C:
 			m.Lock()
-			for name, records := range m.records {
-				for version, record := range records {
-					for id, n := range record.Nodes {
-						if n.TTL != 0 && time.Since(n.LastSeen) > n.TTL {
-							if logger.V(logger.DebugLevel, logger.DefaultLogger) {
-								logger.Debugf("Registry TTL expired for node %s of service %s", n.Id, name)
+			for _, services := range m.records {
+				for name, records := range services {
+					for _, record := range records {
+						for id, n := range record.Nodes {
+							if n.TTL != 0 && time.Since(n.LastSeen) > n.TTL {
+								if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+									logger.Debugf("Registry TTL expired for node %s of service %s", n.Id, name)
+								}
+								delete(record.Nodes, id)
 							}
-							delete(m.records[name][version].Nodes, id)
 						}
 					}
 				}
@@ -88,25 +93,47 @@ func (m *Registry) ttlPrune() {
 	}
 }
 
-func (m *Registry) sendEvent(r *registry.Result) {
-	m.RLock()
-	watchers := make([]*Watcher, 0, len(m.watchers))
-	for _, w := range m.watchers {
-		watchers = append(watchers, w)
+// domainOf returns the domain set in the options, or the default domain if none is
+func domainOf(domain string) string {
+	if len(domain) == 0 {
+		return registry.DefaultDomain
 	}
-	m.RUnlock()
+	return domain
+}
+
+// withDomain returns a copy of the service with its domain in the metadata
+func withDomain(s *registry.Service, domain string) *registry.Service {
+	metadata := make(map[string]string, len(s.Metadata)+1)
+	for k, v := range s.Metadata {
+		metadata[k] = v
+	}
+	metadata[registry.DomainKey] = domain
+
+	srv := new(registry.Service)
+	*srv = *s
+	srv.Metadata = metadata
+	return srv
+}
 
-	for _, w := range watchers {
+// sendEvent of the domain to the watchers of the domain. Called with the
+// lock held so events are queued in order, the watchers never block it.
+func (m *Registry) sendEvent(domain string, r *registry.Result) {
+	m.wmtx.Lock()
+	defer m.wmtx.Unlock()
+
+	for id, w := range m.watchers {
 		select {
 		case <-w.exit:
-			m.Lock()
-			delete(m.watchers, w.id)
-			m.Unlock()
+			delete(m.watchers, id)
+			continue
 		default:
-			select {
-			case w.res <- r:
-			case <-time.After(sendEventTime):
-			}
+		}
+
+		switch w.wo.Domain {
+		case domain:
+			w.push(r)
+		case registry.WildcardDomain:
+			w.push(&registry.Result{Action: r.Action, Service: withDomain(r.Service, domain)})
 		}
 	}
 }
@@ -121,16 +148,20 @@ func (m *Registry) Init(opts ...registry.Option) error {
 	defer m.Unlock()
 
 	records := getServiceRecords(m.options.Context)
+	if len(records) > 0 && m.records[registry.DefaultDomain] == nil {
+		m.records[registry.DefaultDomain] = make(map[string]map[string]*record)
+	}
+	services := m.records[registry.DefaultDomain]
 	for name, record := range records {
 		// add a whole new service including all of its versions
-		if _, ok := m.records[name]; !ok {
-			m.records[name] = record
+		if _, ok := services[name]; !ok {
+			services[name] = record
 			continue
 		}
 		// add the versions of the service we dont track yet
 		for version, r := range record {
-			if _, ok := m.records[name][version]; !ok {
-				m.records[name][version] = r
+			if _, ok := services[name][version]; !ok {
+				services[name][version] = r
 				continue
 			}
 		}
@@ -154,45 +185,55 @@ func (m *Registry) Register(s *registry.Service, opts ...registry.RegisterOption
 
 	r := serviceToRecord(s, options.TTL)
 
-	if _, ok := m.records[s.Name]; !ok {
-		m.records[s.Name] = make(map[string]*record)
+	domain := domainOf(options.Domain)
+	if _, ok := m.records[domain]; !ok {
+		m.records[domain] = make(map[string]map[string]*record)
+	}
+	records := m.records[domain]
+
+	if _, ok := records[s.Name]; !ok {
+		records[s.Name] = make(map[string]*record)
 	}
 
-	if _, ok := m.records[s.Name][s.Version]; !ok {
-		m.records[s.Name][s.Version] = r
+	if _, ok := records[s.Name][s.Version]; !ok {
+		records[s.Name][s.Version] = r
 		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 			logger.Debugf("Registry added new service: %s, version: %s", s.Name, s.Version)
 		}
-		go m.sendEvent(&registry.Result{Action: "update", Service: s})
+		m.sendEvent(domain, &registry.Result{Action: "update", Service: s})
 		return nil
 	}
 
-	addedNodes := false
+	changedNodes := false
 	for _, n := range s.Nodes {
-		if _, ok := m.records[s.Name][s.Version].Nodes[n.Id]; !ok {
-			addedNodes = true
-			metadata := make(map[string]string)
-			for k, v := range n.Metadata {
-				metadata[k] = v
-				m.records[s.Name][s.Version].Nodes[n.Id] = &node{
-					Node: &registry.Node{
-						Id:       n.Id,
-						Address:  n.Address,
-						Metadata: metadata,
-					},
-					TTL:      options.TTL,
-					LastSeen: time.Now(),
+		metadata := make(map[string]string)
+		for k, v := range n.Metadata {
+			metadata[k] = v
+		}
+
+		// update the nodes whose address or metadata changed
+		if cur, ok := records[s.Name][s.Version].Nodes[n.Id]; ok {
+			if cur.Address != n.Address || !reflect.DeepEqual(cur.Metadata, metadata) {
+				changedNodes = true
+				cur.Node = &registry.Node{
+					Id:       n.Id,
+					Address:  n.Address,
+					Metadata: metadata,
 				}
 			}
+			continue
 		}
-	}
 
-	if addedNodes {
-		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
-			logger.Debugf("Registry added new node to service: %s, version: %s", s.Name, s.Version)
+		changedNodes = true
+		records[s.Name][s.Version].Nodes[n.Id] = &node{
+			Node: &registry.Node{
+				Id:       n.Id,
+				Address:  n.Address,
+				Metadata: metadata,
+			},
+			TTL:      options.TTL,
+			LastSeen: time.Now(),
 		}
-		go m.sendEvent(&registry.Result{Action: "update", Service: s})
-		return nil
 	}
 
 	// refresh TTL and timestamp
@@ -200,8 +241,15 @@ func (m *Registry) Register(s *registry.Service, opts ...registry.RegisterOption
 		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 			logger.Debugf("Updated registration for service: %s, version: %s", s.Name, s.Version)
 		}
-		m.records[s.Name][s.Version].Nodes[n.Id].TTL = options.TTL
-		m.records[s.Name][s.Version].Nodes[n.Id].LastSeen = time.Now()
+		records[s.Name][s.Version].Nodes[n.Id].TTL = options.TTL
+		records[s.Name][s.Version].Nodes[n.Id].LastSeen = time.Now()
+	}
+
+	if changedNodes {
+		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+			logger.Debugf("Registry added or updated nodes of service: %s, version: %s", s.Name, s.Version)
+		}
+		m.sendEvent(domain, &registry.Result{Action: "update", Service: s})
 	}
 
 	return nil
@@ -211,84 +259,127 @@ func (m *Registry) Deregister(s *registry.Service, opts ...registry.DeregisterOp
 	m.Lock()
 	defer m.Unlock()
 
-	if _, ok := m.records[s.Name]; ok {
-		if _, ok := m.records[s.Name][s.Version]; ok {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	domain := domainOf(options.Domain)
+	records := m.records[domain]
+
+	if _, ok := records[s.Name]; ok {
+		if _, ok := records[s.Name][s.Version]; ok {
 			for _, n := range s.Nodes {
-				if _, ok := m.records[s.Name][s.Version].Nodes[n.Id]; ok {
+				if _, ok := records[s.Name][s.Version].Nodes[n.Id]; ok {
 					if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 						logger.Debugf("Registry removed node from service: %s, version: %s", s.Name, s.Version)
 					}
-					delete(m.records[s.Name][s.Version].Nodes, n.Id)
+					delete(records[s.Name][s.Version].Nodes, n.Id)
 				}
 			}
-			if len(m.records[s.Name][s.Version].Nodes) == 0 {
-				delete(m.records[s.Name], s.Version)
+			if len(records[s.Name][s.Version].Nodes) == 0 {
+				delete(records[s.Name], s.Version)
 				if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 					logger.Debugf("Registry removed service: %s, version: %s", s.Name, s.Version)
 				}
 			}
 		}
-		if len(m.records[s.Name]) == 0 {
-			delete(m.records, s.Name)
+		if len(records[s.Name]) == 0 {
+			delete(records, s.Name)
 			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 				logger.Debugf("Registry removed service: %s", s.Name)
 			}
 		}
-		go m.sendEvent(&registry.Result{Action: "delete", Service: s})
+		if len(records) == 0 {
+			delete(m.records, domain)
+		}
+		m.sendEvent(domain, &registry.Result{Action: "delete", Service: s})
 	}
 
 	return nil
 }
 
 func (m *Registry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	m.RLock()
 	defer m.RUnlock()
 
-	records, ok := m.records[name]
-	if !ok {
-		return nil, registry.ErrNotFound
+	var services []*registry.Service
+	for domain, records := range m.domains(options.Domain) {
+		for _, record := range records[name] {
+			services = append(services, m.service(record, domain, options.Domain))
+		}
 	}
 
-	services := make([]*registry.Service, len(m.records[name]))
-	i := 0
-	for _, record := range records {
-		services[i] = recordToService(record)
-		i++
+	if len(services) == 0 {
+		return nil, registry.ErrNotFound
 	}
 
 	return services, nil
 }
 
 func (m *Registry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	m.RLock()
 	defer m.RUnlock()
 
 	var services []*registry.Service
-	for _, records := range m.records {
-		for _, record := range records {
-			services = append(services, recordToService(record))
+	for domain, records := range m.domains(options.Domain) {
+		for _, versions := range records {
+			for _, record := range versions {
+				services = append(services, m.service(record, domain, options.Domain))
+			}
 		}
 	}
 
 	return services, nil
 }
 
+// domains returns the records of the domains to look up, which is all
+// of them for the wildcard domain. Must be called with the lock held.
+func (m *Registry) domains(domain string) map[string]map[string]map[string]*record {
+	domain = domainOf(domain)
+	if domain == registry.WildcardDomain {
+		return m.records
+	}
+	return map[string]map[string]map[string]*record{domain: m.records[domain]}
+}
+
+// service returns the service of the record, with its domain in
+// the metadata if it was looked up in the wildcard domain
+func (m *Registry) service(r *record, domain, lookup string) *registry.Service {
+	s := recordToService(r)
+	if lookup == registry.WildcardDomain {
+		s.Metadata[registry.DomainKey] = domain
+	}
+	return s
+}
+
 func (m *Registry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
 	var wo registry.WatchOptions
 	for _, o := range opts {
 		o(&wo)
 	}
+	wo.Domain = domainOf(wo.Domain)
 
 	w := &Watcher{
-		exit: make(chan bool),
-		res:  make(chan *registry.Result),
-		id:   uuid.New().String(),
-		wo:   wo,
+		exit:   make(chan bool),
+		notify: make(chan bool, 1),
+		id:     uuid.New().String(),
+		wo:     wo,
 	}
 
-	m.Lock()
+	m.wmtx.Lock()
 	m.watchers[w.id] = w
-	m.Unlock()
+	m.wmtx.Unlock()
 
 	return w, nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/micro/go-micro/v2/registry"
+	"github.com/micro/go-micro/v2/registry/test"
 )
 
 var (
@@ -166,6 +167,12 @@ func TestMemoryRegistry(t *testing.T) {
 	}
 }
 
+func TestMemoryRegistryConformance(t *testing.T) {
+	test.Run(t, func() registry.Registry {
+		return NewRegistry()
+	})
+}
+
 func TestMemoryRegistryTTL(t *testing.T) {
 	m := NewRegistry()
 
@@ -244,3 +251,74 @@ func TestMemoryRegistryTTLConcurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryRegistryDomains(t *testing.T) {
+	m := NewRegistry()
+
+	foo := &registry.Service{Name: "foo", Version: "1", Nodes: []*registry.Node{{Id: "foo-1", Address: "10.0.0.1:8080"}}}
+	bar := &registry.Service{Name: "foo", Version: "1", Nodes: []*registry.Node{{Id: "foo-2", Address: "10.0.0.2:8080"}}}
+
+	w, err := m.Watch(registry.WatchDomain("acme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	if err := m.Register(foo); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register(bar, registry.RegisterDomain("acme")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the watcher only gets the events of its domain
+	res, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Service.Nodes[0].Id != "foo-2" {
+		t.Fatalf("Expected foo-2, got %s", res.Service.Nodes[0].Id)
+	}
+
+	for domain, id := range map[string]string{"": "foo-1", registry.DefaultDomain: "foo-1", "acme": "foo-2"} {
+		services, err := m.GetService("foo", registry.GetDomain(domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(services) != 1 || services[0].Nodes[0].Id != id {
+			t.Fatalf("Expected %s in domain %q, got %v", id, domain, services)
+		}
+	}
+
+	if _, err := m.GetService("foo", registry.GetDomain("other")); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	services, err := m.GetService("foo", registry.GetDomain(registry.WildcardDomain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(services))
+	}
+	for _, s := range services {
+		if d := s.Metadata[registry.DomainKey]; (d == "acme") != (s.Nodes[0].Id == "foo-2") {
+			t.Fatalf("Unexpected domain %q of %s", d, s.Nodes[0].Id)
+		}
+	}
+
+	domains, err := registry.ListDomains(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(domains) != fmt.Sprint([]string{"acme", registry.DefaultDomain}) {
+		t.Fatalf("Unexpected domains %v", domains)
+	}
+
+	if err := m.Deregister(bar, registry.DeregisterDomain("acme")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetService("foo", registry.GetDomain("acme")); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+}
@@ -1,7 +1,7 @@
 package memory
 
 import (
-	"errors"
+	"sync"
 
 	"github.com/micro/go-micro/v2/registry"
 )
@@ -9,21 +9,61 @@ import (
 type Watcher struct {
 	id   string
 	wo   registry.WatchOptions
-	res  chan *registry.Result
 	exit chan bool
+
+	// results queued until they're read
+	sync.Mutex
+	results []*registry.Result
+	notify  chan bool
+}
+
+// push queues a result without waiting for it to be read
+func (m *Watcher) push(r *registry.Result) {
+	m.Lock()
+	m.results = append(m.results, r)
+	m.Unlock()
+
+	select {
+	case m.notify <- true:
+	default:
+	}
+}
+
+// pop returns the next queued result, if there's one
+func (m *Watcher) pop() (*registry.Result, bool) {
+	m.Lock()
+	defer m.Unlock()
+	if len(m.results) == 0 {
+		return nil, false
+	}
+	r := m.results[0]
+	m.results[0] = nil
+	m.results = m.results[1:]
+	return r, true
 }
 
 func (m *Watcher) Next() (*registry.Result, error) {
 	for {
 		select {
-		case r := <-m.res:
-			if len(m.wo.Service) > 0 && m.wo.Service != r.Service.Name {
-				continue
-			}
-			return r, nil
 		case <-m.exit:
-			return nil, errors.New("watcher stopped")
+			return nil, registry.ErrWatcherStopped
+		default:
+		}
+
+		r, ok := m.pop()
+		if !ok {
+			select {
+			case <-m.notify:
+			case <-m.exit:
+				return nil, registry.ErrWatcherStopped
+			}
+			continue
+		}
+
+		if len(m.wo.Service) > 0 && m.wo.Service != r.Service.Name {
+			continue
 		}
+		return r, nil
 	}
 }
 
@@ -8,13 +8,13 @@ import (
 
 func TestWatcher(t *testing.T) {
 	w := &Watcher{
-		id:   "test",
-		res:  make(chan *registry.Result),
-		exit: make(chan bool),
+		id:     "test",
+		exit:   make(chan bool),
+		notify: make(chan bool, 1),
 	}
 
 	go func() {
-		w.res <- &registry.Result{}
+		w.push(&registry.Result{})
 	}()
 
 	_, err := w.Next()
@@ -18,6 +18,8 @@ type Options struct {
 
 type RegisterOptions struct {
 	TTL time.Duration
+	// Domain to register the service in
+	Domain string
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -27,6 +29,8 @@ type WatchOptions struct {
 	// Specify a service to watch
 	// If blank, the watch is for all services
 	Service string
+	// Domain to watch
+	Domain string
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -34,14 +38,20 @@ type WatchOptions struct {
 
 type DeregisterOptions struct {
 	Context context.Context
+	// Domain the service was registered in
+	Domain string
 }
 
 type GetOptions struct {
 	Context context.Context
+	// Domain to look up the service in
+	Domain string
 }
 
 type ListOptions struct {
 	Context context.Context
+	// Domain to list the services of
+	Domain string
 }
 
 // Addrs is the registry addresses to use
@@ -77,6 +87,13 @@ func RegisterTTL(t time.Duration) RegisterOption {
 	}
 }
 
+// RegisterDomain sets the domain the service is registered in
+func RegisterDomain(d string) RegisterOption {
+	return func(o *RegisterOptions) {
+		o.Domain = d
+	}
+}
+
 func RegisterContext(ctx context.Context) RegisterOption {
 	return func(o *RegisterOptions) {
 		o.Context = ctx
@@ -90,24 +107,52 @@ func WatchService(name string) WatchOption {
 	}
 }
 
+// WatchDomain sets the domain to watch, which is all of them for the WildcardDomain
+func WatchDomain(d string) WatchOption {
+	return func(o *WatchOptions) {
+		o.Domain = d
+	}
+}
+
 func WatchContext(ctx context.Context) WatchOption {
 	return func(o *WatchOptions) {
 		o.Context = ctx
 	}
 }
 
+// DeregisterDomain sets the domain the service is deregistered from
+func DeregisterDomain(d string) DeregisterOption {
+	return func(o *DeregisterOptions) {
+		o.Domain = d
+	}
+}
+
 func DeregisterContext(ctx context.Context) DeregisterOption {
 	return func(o *DeregisterOptions) {
 		o.Context = ctx
 	}
 }
 
+// GetDomain sets the domain the service is looked up in, which is all of them for the WildcardDomain
+func GetDomain(d string) GetOption {
+	return func(o *GetOptions) {
+		o.Domain = d
+	}
+}
+
 func GetContext(ctx context.Context) GetOption {
 	return func(o *GetOptions) {
 		o.Context = ctx
 	}
 }
 
+// ListDomain sets the domain the services are listed from, which is all of them for the WildcardDomain
+func ListDomain(d string) ListOption {
+	return func(o *ListOptions) {
+		o.Domain = d
+	}
+}
+
 func ListContext(ctx context.Context) ListOption {
 	return func(o *ListOptions) {
 		o.Context = ctx
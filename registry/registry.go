@@ -8,6 +8,14 @@ import (
 var (
 	DefaultRegistry = NewRegistry()
 
+	// DefaultDomain is the domain of the services registered or looked up without one
+	DefaultDomain = "micro"
+	// WildcardDomain is the domain to look up or watch the services of all the domains
+	WildcardDomain = "*"
+	// DomainKey is the metadata field holding the domain of the services
+	// returned by lookups and watches of the wildcard domain
+	DomainKey = "domain"
+
 	// Not found error when GetService is called
 	ErrNotFound = errors.New("service not found")
 	// Watcher stopped error when watcher is stopped
@@ -8,6 +8,7 @@ import (
 	"github.com/micro/go-micro/v2/client"
 	"github.com/micro/go-micro/v2/client/grpc"
 	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/metadata"
 	"github.com/micro/go-micro/v2/registry"
 	pb "github.com/micro/go-micro/v2/registry/service/proto"
 )
@@ -43,6 +44,17 @@ func (s *serviceRegistry) callOpts() []client.CallOption {
 	return opts
 }
 
+// withDomain returns the context of a call to the domain, which is passed to the
+// registry service as the namespace of the call. Calls without a domain, or to all
+// the domains which the registry service has no namespace for, are made in the
+// namespace already in the context, if any.
+func withDomain(ctx context.Context, domain string) context.Context {
+	if len(domain) == 0 || domain == registry.WildcardDomain {
+		return ctx
+	}
+	return metadata.Set(ctx, "Micro-Namespace", domain)
+}
+
 func (s *serviceRegistry) Init(opts ...registry.Option) error {
 	for _, o := range opts {
 		o(&s.opts)
@@ -83,7 +95,7 @@ func (s *serviceRegistry) Register(srv *registry.Service, opts ...registry.Regis
 	pbSrv.Options.Ttl = int64(options.TTL.Seconds())
 
 	// register the service
-	_, err := s.client.Register(options.Context, pbSrv, s.callOpts()...)
+	_, err := s.client.Register(withDomain(options.Context, options.Domain), pbSrv, s.callOpts()...)
 	if err != nil {
 		return err
 	}
@@ -101,7 +113,7 @@ func (s *serviceRegistry) Deregister(srv *registry.Service, opts ...registry.Der
 	}
 
 	// deregister the service
-	_, err := s.client.Deregister(options.Context, ToProto(srv), s.callOpts()...)
+	_, err := s.client.Deregister(withDomain(options.Context, options.Domain), ToProto(srv), s.callOpts()...)
 	if err != nil {
 		return err
 	}
@@ -117,7 +129,7 @@ func (s *serviceRegistry) GetService(name string, opts ...registry.GetOption) ([
 		options.Context = context.TODO()
 	}
 
-	rsp, err := s.client.GetService(options.Context, &pb.GetRequest{
+	rsp, err := s.client.GetService(withDomain(options.Context, options.Domain), &pb.GetRequest{
 		Service: name,
 	}, s.callOpts()...)
 
@@ -143,7 +155,7 @@ func (s *serviceRegistry) ListServices(opts ...registry.ListOption) ([]*registry
 		options.Context = context.TODO()
 	}
 
-	rsp, err := s.client.ListServices(options.Context, &pb.ListRequest{}, s.callOpts()...)
+	rsp, err := s.client.ListServices(withDomain(options.Context, options.Domain), &pb.ListRequest{}, s.callOpts()...)
 	if err != nil {
 		return nil, err
 	}
@@ -165,7 +177,7 @@ func (s *serviceRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher,
 		options.Context = context.TODO()
 	}
 
-	stream, err := s.client.Watch(options.Context, &pb.WatchRequest{
+	stream, err := s.client.Watch(withDomain(options.Context, options.Domain), &pb.WatchRequest{
 		Service: options.Service,
 	}, s.callOpts()...)
 
@@ -0,0 +1,454 @@
+// Package test provides a conformance test suite for registry implementations
+package test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/registry"
+)
+
+var (
+	// the time to wait for a watcher to return a result
+	watchTimeout = 5 * time.Second
+
+	// the domain other than the default one the services are registered in
+	testDomain = "test.domain"
+)
+
+// Run the conformance tests against the registries returned by newRegistry. Each
+// call to newRegistry must return a new, initialised registry with no services.
+func Run(t *testing.T, newRegistry func() registry.Registry) {
+	tests := []struct {
+		name string
+		test func(*testing.T, registry.Registry)
+	}{
+		{"Register", testRegister},
+		{"Nodes", testNodes},
+		{"Versions", testVersions},
+		{"Deregister", testDeregister},
+		{"Watch", testWatch},
+		{"WatchService", testWatchService},
+		{"Domains", testDomains},
+		{"WatchDomain", testWatchDomain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRegistry())
+		})
+	}
+}
+
+func newService(name, version string, nodes ...string) *registry.Service {
+	s := &registry.Service{
+		Name:     name,
+		Version:  version,
+		Metadata: map[string]string{"foo": "bar"},
+		Endpoints: []*registry.Endpoint{
+			{
+				Name:     "Test.Call",
+				Metadata: map[string]string{"foo": "bar"},
+			},
+		},
+	}
+	for _, id := range nodes {
+		s.Nodes = append(s.Nodes, &registry.Node{
+			Id:       id,
+			Address:  "10.0.0.1:" + id,
+			Metadata: map[string]string{"node": id},
+		})
+	}
+	return s
+}
+
+func register(t *testing.T, r registry.Registry, s *registry.Service) {
+	t.Helper()
+	if err := r.Register(s); err != nil {
+		t.Fatalf("Register %s %s: %v", s.Name, s.Version, err)
+	}
+}
+
+func deregister(t *testing.T, r registry.Registry, s *registry.Service) {
+	t.Helper()
+	if err := r.Deregister(s); err != nil {
+		t.Fatalf("Deregister %s %s: %v", s.Name, s.Version, err)
+	}
+}
+
+// nodes returns the sorted ids of the nodes of the version of the service
+func nodes(t *testing.T, r registry.Registry, name, version string) []string {
+	t.Helper()
+
+	services, err := r.GetService(name)
+	if err != nil {
+		t.Fatalf("GetService %s: %v", name, err)
+	}
+
+	var ids []string
+	for _, s := range services {
+		if s.Name != name {
+			t.Fatalf("GetService %s: got service %s", name, s.Name)
+		}
+		if s.Version != version {
+			continue
+		}
+		for _, n := range s.Nodes {
+			ids = append(ids, n.Id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testRegister(t *testing.T, r registry.Registry) {
+	if _, err := r.GetService("test.service"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	register(t, r, newService("test.service", "1.0.0", "1"))
+
+	services, err := r.GetService("test.service")
+	if err != nil {
+		t.Fatalf("GetService: %v", err)
+	}
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(services))
+	}
+
+	s := services[0]
+	if s.Name != "test.service" || s.Version != "1.0.0" {
+		t.Fatalf("Expected test.service 1.0.0, got %s %s", s.Name, s.Version)
+	}
+	if s.Metadata["foo"] != "bar" {
+		t.Fatalf("Expected metadata foo=bar, got %v", s.Metadata)
+	}
+	if len(s.Endpoints) != 1 || s.Endpoints[0].Name != "Test.Call" {
+		t.Fatalf("Expected endpoint Test.Call, got %+v", s.Endpoints)
+	}
+	if len(s.Nodes) != 1 {
+		t.Fatalf("Expected 1 node, got %d", len(s.Nodes))
+	}
+
+	n := s.Nodes[0]
+	if n.Id != "1" || n.Address != "10.0.0.1:1" || n.Metadata["node"] != "1" {
+		t.Fatalf("Expected node 1 at 10.0.0.1:1, got %+v", n)
+	}
+
+	services, err = r.ListServices()
+	if err != nil {
+		t.Fatalf("ListServices: %v", err)
+	}
+	var found bool
+	for _, s := range services {
+		if s.Name == "test.service" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected test.service to be listed, got %+v", services)
+	}
+}
+
+func testNodes(t *testing.T, r registry.Registry) {
+	register(t, r, newService("test.service", "1.0.0", "1"))
+	register(t, r, newService("test.service", "1.0.0", "2"))
+	register(t, r, newService("test.service", "1.0.0", "3"))
+
+	// registering a node again doesn't add another node
+	register(t, r, newService("test.service", "1.0.0", "1"))
+
+	if ids := nodes(t, r, "test.service", "1.0.0"); !equal(ids, []string{"1", "2", "3"}) {
+		t.Fatalf("Expected nodes [1 2 3], got %v", ids)
+	}
+}
+
+func testVersions(t *testing.T, r registry.Registry) {
+	register(t, r, newService("test.service", "1.0.0", "1"))
+	register(t, r, newService("test.service", "2.0.0", "2"))
+
+	services, err := r.GetService("test.service")
+	if err != nil {
+		t.Fatalf("GetService: %v", err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(services))
+	}
+
+	if ids := nodes(t, r, "test.service", "1.0.0"); !equal(ids, []string{"1"}) {
+		t.Fatalf("Expected nodes [1] for 1.0.0, got %v", ids)
+	}
+	if ids := nodes(t, r, "test.service", "2.0.0"); !equal(ids, []string{"2"}) {
+		t.Fatalf("Expected nodes [2] for 2.0.0, got %v", ids)
+	}
+}
+
+func testDeregister(t *testing.T, r registry.Registry) {
+	// deregistering a service which isn't registered is not an error
+	deregister(t, r, newService("test.service", "1.0.0", "1"))
+
+	register(t, r, newService("test.service", "1.0.0", "1"))
+	register(t, r, newService("test.service", "1.0.0", "2"))
+	register(t, r, newService("test.service", "2.0.0", "3"))
+
+	deregister(t, r, newService("test.service", "1.0.0", "1"))
+	if ids := nodes(t, r, "test.service", "1.0.0"); !equal(ids, []string{"2"}) {
+		t.Fatalf("Expected nodes [2], got %v", ids)
+	}
+
+	deregister(t, r, newService("test.service", "1.0.0", "2"))
+	services, err := r.GetService("test.service")
+	if err != nil {
+		t.Fatalf("GetService: %v", err)
+	}
+	for _, s := range services {
+		if s.Version == "1.0.0" && len(s.Nodes) > 0 {
+			t.Fatalf("Expected no nodes for 1.0.0, got %+v", s.Nodes)
+		}
+	}
+
+	deregister(t, r, newService("test.service", "2.0.0", "3"))
+	if _, err := r.GetService("test.service"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+
+	services, err = r.ListServices()
+	if err != nil {
+		t.Fatalf("ListServices: %v", err)
+	}
+	for _, s := range services {
+		if s.Name == "test.service" {
+			t.Fatalf("Expected test.service not to be listed, got %+v", s)
+		}
+	}
+}
+
+// next returns the next result of the watcher or fails the test after the watch timeout
+func next(t *testing.T, w registry.Watcher) *registry.Result {
+	t.Helper()
+
+	type result struct {
+		res *registry.Result
+		err error
+	}
+
+	ch := make(chan result, 1)
+	go func() {
+		res, err := w.Next()
+		ch <- result{res, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("Next: %v", r.err)
+		}
+		return r.res
+	case <-time.After(watchTimeout):
+		t.Fatalf("Next: no result after %v", watchTimeout)
+	}
+
+	return nil
+}
+
+func expect(t *testing.T, w registry.Watcher, action, name, node string) {
+	t.Helper()
+
+	res := next(t, w)
+	if res.Action != action || res.Service == nil || res.Service.Name != name {
+		t.Fatalf("Expected %s of %s, got %s of %+v", action, name, res.Action, res.Service)
+	}
+
+	if !includes(res.Service, node) {
+		t.Fatalf("Expected %s of %s to include node %s, got %+v", action, name, node, res.Service.Nodes)
+	}
+}
+
+// expectAdded expects the node to be added to the service. Registries report
+// a service registered for the first time as either a create or an update.
+func expectAdded(t *testing.T, w registry.Watcher, name, node string) {
+	t.Helper()
+
+	res := next(t, w)
+	if (res.Action != "create" && res.Action != "update") || res.Service == nil || res.Service.Name != name {
+		t.Fatalf("Expected create or update of %s, got %s of %+v", name, res.Action, res.Service)
+	}
+
+	if !includes(res.Service, node) {
+		t.Fatalf("Expected %s of %s to include node %s, got %+v", res.Action, name, node, res.Service.Nodes)
+	}
+}
+
+// includes returns true if the service has the node
+func includes(s *registry.Service, node string) bool {
+	if s == nil {
+		return false
+	}
+	for _, n := range s.Nodes {
+		if n.Id == node {
+			return true
+		}
+	}
+	return false
+}
+
+func testWatch(t *testing.T, r registry.Registry) {
+	w, err := r.Watch()
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer w.Stop()
+
+	register(t, r, newService("test.service", "1.0.0", "1"))
+	register(t, r, newService("test.service", "1.0.0", "2"))
+	deregister(t, r, newService("test.service", "1.0.0", "1"))
+
+	// results are returned in the order of the changes
+	expectAdded(t, w, "test.service", "1")
+	// registries keeping a key per node, like etcd, create the nodes added
+	expectAdded(t, w, "test.service", "2")
+	expect(t, w, "delete", "test.service", "1")
+
+	w.Stop()
+	if _, err := w.Next(); err == nil {
+		t.Fatal("Expected an error from a stopped watcher")
+	}
+}
+
+func testWatchService(t *testing.T, r registry.Registry) {
+	w, err := r.Watch(registry.WatchService("test.service"))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer w.Stop()
+
+	// other services aren't watched
+	register(t, r, newService("other.service", "1.0.0", "1"))
+	register(t, r, newService("test.service", "1.0.0", "2"))
+
+	expectAdded(t, w, "test.service", "2")
+}
+
+// domains returns the domains of the services named name
+func domains(services []*registry.Service, name string) []string {
+	var domains []string
+	for _, s := range services {
+		if s.Name == name {
+			domains = append(domains, s.Metadata[registry.DomainKey])
+		}
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+func testDomains(t *testing.T, r registry.Registry) {
+	s := newService("test.service", "1.0.0", "1")
+	if err := r.Register(s, registry.RegisterDomain(testDomain)); err != nil {
+		t.Fatalf("Register %s in %s: %v", s.Name, testDomain, err)
+	}
+	register(t, r, newService("other.service", "1.0.0", "2"))
+
+	// the service isn't in the default domain
+	if _, err := r.GetService("test.service"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v in the default domain, got %v", registry.ErrNotFound, err)
+	}
+
+	services, err := r.GetService("test.service", registry.GetDomain(testDomain))
+	if err != nil {
+		t.Fatalf("GetService in %s: %v", testDomain, err)
+	}
+	if len(services) != 1 || len(services[0].Nodes) != 1 || services[0].Nodes[0].Id != "1" {
+		t.Fatalf("Expected node 1 in %s, got %+v", testDomain, services)
+	}
+
+	// the services of all the domains have their domain in the metadata
+	services, err = r.GetService("test.service", registry.GetDomain(registry.WildcardDomain))
+	if err != nil {
+		t.Fatalf("GetService in all the domains: %v", err)
+	}
+	if d := domains(services, "test.service"); !equal(d, []string{testDomain}) {
+		t.Fatalf("Expected test.service in [%s], got %v", testDomain, d)
+	}
+
+	services, err = r.ListServices(registry.ListDomain(testDomain))
+	if err != nil {
+		t.Fatalf("ListServices in %s: %v", testDomain, err)
+	}
+	if len(domains(services, "test.service")) != 1 || len(domains(services, "other.service")) != 0 {
+		t.Fatalf("Expected only test.service to be listed in %s, got %+v", testDomain, services)
+	}
+
+	services, err = r.ListServices(registry.ListDomain(registry.WildcardDomain))
+	if err != nil {
+		t.Fatalf("ListServices in all the domains: %v", err)
+	}
+	if d := domains(services, "test.service"); !equal(d, []string{testDomain}) {
+		t.Fatalf("Expected test.service in [%s], got %v", testDomain, d)
+	}
+	if d := domains(services, "other.service"); !equal(d, []string{registry.DefaultDomain}) {
+		t.Fatalf("Expected other.service in [%s], got %v", registry.DefaultDomain, d)
+	}
+
+	// deregistering from the default domain leaves the service in its domain
+	deregister(t, r, s)
+	if _, err := r.GetService("test.service", registry.GetDomain(testDomain)); err != nil {
+		t.Fatalf("GetService in %s: %v", testDomain, err)
+	}
+
+	if err := r.Deregister(s, registry.DeregisterDomain(testDomain)); err != nil {
+		t.Fatalf("Deregister %s in %s: %v", s.Name, testDomain, err)
+	}
+	if _, err := r.GetService("test.service", registry.GetDomain(testDomain)); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v in %s, got %v", registry.ErrNotFound, testDomain, err)
+	}
+}
+
+func testWatchDomain(t *testing.T, r registry.Registry) {
+	w, err := r.Watch(registry.WatchDomain(testDomain))
+	if err != nil {
+		t.Fatalf("Watch %s: %v", testDomain, err)
+	}
+	defer w.Stop()
+
+	wa, err := r.Watch(registry.WatchDomain(registry.WildcardDomain))
+	if err != nil {
+		t.Fatalf("Watch all the domains: %v", err)
+	}
+	defer wa.Stop()
+
+	// the services of the default domain aren't watched in the domain
+	register(t, r, newService("other.service", "1.0.0", "1"))
+	s := newService("test.service", "1.0.0", "2")
+	if err := r.Register(s, registry.RegisterDomain(testDomain)); err != nil {
+		t.Fatalf("Register %s in %s: %v", s.Name, testDomain, err)
+	}
+
+	expectAdded(t, w, "test.service", "2")
+
+	// the services of all the domains are watched with their domain
+	seen := make(map[string]string)
+	for len(seen) < 2 {
+		res := next(t, wa)
+		if (res.Action != "create" && res.Action != "update") || res.Service == nil {
+			t.Fatalf("Expected a create or update, got %s of %+v", res.Action, res.Service)
+		}
+		seen[res.Service.Name] = res.Service.Metadata[registry.DomainKey]
+	}
+	if seen["test.service"] != testDomain || seen["other.service"] != registry.DefaultDomain {
+		t.Fatalf("Expected test.service in %s and other.service in %s, got %v", testDomain, registry.DefaultDomain, seen)
+	}
+}
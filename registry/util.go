@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"sort"
+)
+
+// ListDomains returns the domains which have services in the registry,
+// listing the services of the WildcardDomain
+func ListDomains(r Registry) ([]string, error) {
+	services, err := r.ListServices(ListDomain(WildcardDomain))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var domains []string
+
+	for _, s := range services {
+		d := s.Metadata[DomainKey]
+		if len(d) == 0 {
+			d = DefaultDomain
+		}
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		domains = append(domains, d)
+	}
+
+	sort.Strings(domains)
+	return domains, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/micro/go-micro/v2/broker"
 	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/health"
 	"github.com/micro/go-micro/v2/logger"
 	meta "github.com/micro/go-micro/v2/metadata"
 	"github.com/micro/go-micro/v2/registry"
@@ -60,6 +61,8 @@ type grpcServer struct {
 	started bool
 	// used for first registration
 	registered bool
+	// health status of the node
+	health string
 
 	// registry service instance
 	rsvc *registry.Service
@@ -566,6 +569,57 @@ func (g *grpcServer) Subscribe(sb server.Subscriber) error {
 	return nil
 }
 
+// checkHealth runs the health checks of the node, returning true if its status changed
+func (g *grpcServer) checkHealth() bool {
+	g.RLock()
+	config := g.opts
+	status := g.health
+	g.RUnlock()
+
+	if len(config.HealthChecks) == 0 {
+		return false
+	}
+
+	node := &registry.Node{
+		Id:      config.Name + "-" + config.Id,
+		Address: config.Address,
+	}
+	if len(config.Advertise) > 0 {
+		node.Address = config.Advertise
+	}
+
+	ctx := context.Background()
+	if config.HealthCheckInterval > time.Duration(0) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.HealthCheckInterval)
+		defer cancel()
+	}
+
+	next := health.Passing
+	if err := health.Run(ctx, config.Name, node, config.HealthChecks...); err != nil {
+		next = health.Failing
+		if status != next && logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			logger.Errorf("Server %s-%s health check error: %v", config.Name, config.Id, err)
+		}
+	}
+
+	if status == next {
+		return false
+	}
+
+	if logger.V(logger.InfoLevel, logger.DefaultLogger) {
+		logger.Infof("Server %s-%s health status: %s", config.Name, config.Id, next)
+	}
+
+	// the service is built again with the new status
+	g.Lock()
+	g.health = next
+	g.rsvc = nil
+	g.Unlock()
+
+	return true
+}
+
 func (g *grpcServer) Register() error {
 	g.RLock()
 	rsvc := g.rsvc
@@ -651,6 +705,12 @@ func (g *grpcServer) Register() error {
 	node.Metadata["protocol"] = "grpc"
 
 	g.RLock()
+
+	// publish the health status once checked
+	if len(config.HealthChecks) > 0 && len(g.health) > 0 {
+		node.Metadata[health.StatusKey] = g.health
+	}
+
 	// Maps are ordered randomly, sort the keys for consistency
 	var handlerList []string
 	for n, e := range g.handlers {
@@ -726,14 +786,25 @@ func (g *grpcServer) Register() error {
 			opts = append(opts, broker.DisableAutoAck())
 		}
 
+		// redelivery is applied here rather than by the broker so it
+		// works the same regardless of the broker implementation
+		ropts, cancel := broker.WithCancel(broker.NewSubscribeOptions(
+			append(opts,
+				broker.MaxAttempts(sb.Options().MaxAttempts),
+				broker.RedeliveryBackoff(sb.Options().Backoff),
+			)...,
+		))
+		handler = broker.Redeliver(config.Broker, sb.Topic(), handler, ropts)
+
 		if logger.V(logger.InfoLevel, logger.DefaultLogger) {
 			logger.Infof("Subscribing to topic: %s", sb.Topic())
 		}
 		sub, err := config.Broker.Subscribe(sb.Topic(), handler, opts...)
 		if err != nil {
+			cancel()
 			return err
 		}
-		g.subscribers[sb] = []broker.Subscriber{sub}
+		g.subscribers[sb] = []broker.Subscriber{&redeliverySubscriber{sub, cancel}}
 	}
 
 	g.registered = true
@@ -882,6 +953,9 @@ func (g *grpcServer) Start() error {
 		}
 	}
 
+	// check the health before registering
+	g.checkHealth()
+
 	// announce self to the world
 	if err := g.Register(); err != nil {
 		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
@@ -907,6 +981,13 @@ func (g *grpcServer) Start() error {
 			t = time.NewTicker(g.opts.RegisterInterval)
 		}
 
+		ht := new(time.Ticker)
+
+		// only check the health if there are checks
+		if len(g.opts.HealthChecks) > 0 && g.opts.HealthCheckInterval > time.Duration(0) {
+			ht = time.NewTicker(g.opts.HealthCheckInterval)
+		}
+
 		// return error chan
 		var ch chan error
 
@@ -920,8 +1001,20 @@ func (g *grpcServer) Start() error {
 						logger.Error("Server register error: ", err)
 					}
 				}
+			// register again when the health status changes
+			case <-ht.C:
+				if !g.checkHealth() {
+					continue
+				}
+				if err := g.Register(); err != nil {
+					if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+						logger.Error("Server register error: ", err)
+					}
+				}
 			// wait for exit
 			case ch = <-g.exit:
+				t.Stop()
+				ht.Stop()
 				break Loop
 			}
 		}
@@ -292,3 +292,14 @@ func (s *subscriber) Endpoints() []*registry.Endpoint {
 func (s *subscriber) Options() server.SubscriberOptions {
 	return s.opts
 }
+
+// redeliverySubscriber stops redelivering the messages of the subscriber once it unsubscribes
+type redeliverySubscriber struct {
+	broker.Subscriber
+	cancel context.CancelFunc
+}
+
+func (r *redeliverySubscriber) Unsubscribe() error {
+	r.cancel()
+	return r.Subscriber.Unsubscribe()
+}
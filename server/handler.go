@@ -1,6 +1,9 @@
 package server
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type HandlerOption func(*HandlerOptions)
 
@@ -17,7 +20,12 @@ type SubscriberOptions struct {
 	AutoAck  bool
 	Queue    string
 	Internal bool
-	Context  context.Context
+	// MaxAttempts is the number of times a message is handled before
+	// it's published to the dead letter topic. Zero disables redelivery.
+	MaxAttempts int
+	// Backoff returns the delay before the next attempt
+	Backoff func(attempts int) time.Duration
+	Context context.Context
 }
 
 // EndpointMetadata is a Handler option that allows metadata to be added to
@@ -78,3 +86,18 @@ func SubscriberContext(ctx context.Context) SubscriberOption {
 		o.Context = ctx
 	}
 }
+
+// SubscriberMaxAttempts redelivers a message up to n times in total when the
+// subscriber returns an error, after which it's published to <topic>.dlq
+func SubscriberMaxAttempts(n int) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.MaxAttempts = n
+	}
+}
+
+// SubscriberBackoff sets the delay between attempts to handle a message
+func SubscriberBackoff(fn func(attempts int) time.Duration) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.Backoff = fn
+	}
+}
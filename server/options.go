@@ -10,6 +10,7 @@ import (
 	"github.com/micro/go-micro/v2/broker"
 	"github.com/micro/go-micro/v2/codec"
 	"github.com/micro/go-micro/v2/debug/trace"
+	"github.com/micro/go-micro/v2/health"
 	"github.com/micro/go-micro/v2/registry"
 	"github.com/micro/go-micro/v2/transport"
 )
@@ -36,6 +37,10 @@ type Options struct {
 	RegisterTTL time.Duration
 	// The interval on which to register
 	RegisterInterval time.Duration
+	// HealthChecks are run on the node, its status is published in its metadata
+	HealthChecks []health.Check
+	// The interval on which to run the health checks
+	HealthCheckInterval time.Duration
 
 	// The router for requests
 	Router Router
@@ -50,10 +55,11 @@ type Options struct {
 
 func newOptions(opt ...Option) Options {
 	opts := Options{
-		Codecs:           make(map[string]codec.NewCodec),
-		Metadata:         map[string]string{},
-		RegisterInterval: DefaultRegisterInterval,
-		RegisterTTL:      DefaultRegisterTTL,
+		Codecs:              make(map[string]codec.NewCodec),
+		Metadata:            map[string]string{},
+		RegisterInterval:    DefaultRegisterInterval,
+		RegisterTTL:         DefaultRegisterTTL,
+		HealthCheckInterval: DefaultHealthCheckInterval,
 	}
 
 	for _, o := range opt {
@@ -199,6 +205,21 @@ func RegisterCheck(fn func(context.Context) error) Option {
 	}
 }
 
+// HealthCheck adds checks run on the node on the health check interval. The node
+// is registered again as soon as its status changes, so clients stop using it.
+func HealthCheck(checks ...health.Check) Option {
+	return func(o *Options) {
+		o.HealthChecks = append(o.HealthChecks, checks...)
+	}
+}
+
+// HealthCheckInterval sets the interval on which to run the health checks
+func HealthCheckInterval(t time.Duration) Option {
+	return func(o *Options) {
+		o.HealthCheckInterval = t
+	}
+}
+
 // Register the service with a TTL
 func RegisterTTL(t time.Duration) Option {
 	return func(o *Options) {
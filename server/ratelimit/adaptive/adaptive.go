@@ -0,0 +1,64 @@
+// Package adaptive provides a concurrency limit which adapts to the latency of a server
+package adaptive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	merrors "github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/server"
+)
+
+var (
+	// ErrLimitExceeded is returned when the concurrency limit is reached
+	ErrLimitExceeded = errors.New("concurrency limit exceeded")
+)
+
+// Limiter limits the number of requests handled concurrently. The limit
+// grows while latency is steady and shrinks as latency rises above the
+// long term average, which is a sign requests are queueing up.
+type Limiter interface {
+	Init(...Option) error
+	Options() Options
+	// Acquire admits a request if the limit has not been reached. The
+	// returned func must be called with the result of the request once done.
+	Acquire() (func(error), error)
+	// Limit returns the current concurrency limit
+	Limit() int
+	// Inflight returns the number of requests being handled
+	Inflight() int
+	// String returns the name of the implementation
+	String() string
+}
+
+// NewHandlerWrapper returns a handler wrapper which sheds requests over the
+// limit with a 429 Too Many Requests error, so that clients back off.
+// Streams and debug endpoints are not limited.
+func NewHandlerWrapper(l Limiter) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) (err error) {
+			// long lived streams would skew the latency
+			if req.Stream() || strings.HasPrefix(req.Endpoint(), "Debug.") {
+				return h(ctx, req, rsp)
+			}
+
+			done, err := l.Acquire()
+			if err != nil {
+				return merrors.TooManyRequests(req.Service(), "%s: %v", req.Endpoint(), err)
+			}
+
+			// release the request even if the handler panics, which is a failure
+			defer func() {
+				if r := recover(); r != nil {
+					done(fmt.Errorf("panic: %v", r))
+					panic(r)
+				}
+				done(err)
+			}()
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
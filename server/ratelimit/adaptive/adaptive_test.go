@@ -0,0 +1,132 @@
+package adaptive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/server"
+)
+
+type testRequest struct {
+	server.Request
+}
+
+func (r *testRequest) Service() string {
+	return "go.micro.srv.foo"
+}
+
+func (r *testRequest) Endpoint() string {
+	return "Foo.Bar"
+}
+
+func (r *testRequest) Stream() bool {
+	return false
+}
+
+func TestLimitExceeded(t *testing.T) {
+	l := NewLimiter(InitialLimit(2))
+
+	var done []func(error)
+	for i := 0; i < 2; i++ {
+		fn, err := l.Acquire()
+		if err != nil {
+			t.Fatal(err)
+		}
+		done = append(done, fn)
+	}
+
+	if _, err := l.Acquire(); err != ErrLimitExceeded {
+		t.Fatalf("expected %v got %v", ErrLimitExceeded, err)
+	}
+
+	done[0](nil)
+	done[0](nil)
+
+	if v := l.Inflight(); v != 1 {
+		t.Fatalf("expected 1 request in flight got %d", v)
+	}
+	if _, err := l.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimitAdapts(t *testing.T) {
+	l := NewLimiter(InitialLimit(10)).(*gradient)
+
+	// keep the limit busy with steady latency
+	for i := 0; i < 100; i++ {
+		l.sample(time.Millisecond, l.Limit())
+	}
+	steady := l.Limit()
+	if steady <= 10 {
+		t.Fatalf("expected the limit to grow from 10 got %d", steady)
+	}
+
+	// latency rises as requests queue up
+	for i := 0; i < 20; i++ {
+		l.sample(10*time.Millisecond, l.Limit())
+	}
+	if v := l.Limit(); v >= steady {
+		t.Fatalf("expected the limit to drop below %d got %d", steady, v)
+	}
+
+	// timeouts back off
+	limit := l.Limit()
+	fn, err := l.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn(context.DeadlineExceeded)
+	if v := l.Limit(); v >= limit {
+		t.Fatalf("expected the limit to drop below %d got %d", limit, v)
+	}
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	l := NewLimiter(InitialLimit(1))
+
+	block := make(chan bool)
+	started := make(chan bool)
+
+	h := NewHandlerWrapper(l)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		started <- true
+		<-block
+		return nil
+	})
+
+	req := &testRequest{}
+
+	go h(context.Background(), req, nil)
+	<-started
+
+	err := h(context.Background(), req, nil)
+	if e := errors.FromError(err); e == nil || e.Code != 429 {
+		t.Fatalf("expected 429 error got %v", err)
+	}
+
+	close(block)
+}
+
+func TestHandlerWrapperPanic(t *testing.T) {
+	l := NewLimiter(InitialLimit(1))
+
+	h := NewHandlerWrapper(l)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		panic("handler failed")
+	})
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected the panic to be passed on")
+			}
+		}()
+		h(context.Background(), &testRequest{}, nil)
+	}()
+
+	// the request is released despite the panic
+	if n := l.Inflight(); n != 0 {
+		t.Fatalf("expected no requests in flight got %d", n)
+	}
+}
@@ -0,0 +1,179 @@
+package adaptive
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	merrors "github.com/micro/go-micro/v2/errors"
+)
+
+var (
+	// DefaultInitialLimit of concurrent requests
+	DefaultInitialLimit = 20
+	// DefaultMinLimit of concurrent requests
+	DefaultMinLimit = 1
+	// DefaultMaxLimit of concurrent requests
+	DefaultMaxLimit = 1000
+	// DefaultTolerance of latency above the long term average
+	DefaultTolerance = 1.5
+	// DefaultSmoothing of changes to the limit
+	DefaultSmoothing = 0.2
+
+	// number of samples averaged by the short and long term latency
+	shortWindow = 10.0
+	longWindow  = 600.0
+	// backoff is applied to the limit on a timeout
+	backoff = 0.9
+)
+
+// gradient is a limiter based on the ratio of the long to the short term
+// latency, as used by Netflix's concurrency-limits. If latency rises the
+// ratio drops below one and the limit is reduced proportionally. A queue
+// of the square root of the limit is allowed on top to probe for capacity.
+type gradient struct {
+	sync.Mutex
+	opts Options
+
+	limit    float64
+	inflight int
+	// exponentially weighted moving averages of latency
+	short float64
+	long  float64
+}
+
+// NewLimiter returns a gradient based adaptive concurrency limiter
+func NewLimiter(opts ...Option) Limiter {
+	options := Options{
+		InitialLimit: DefaultInitialLimit,
+		MinLimit:     DefaultMinLimit,
+		MaxLimit:     DefaultMaxLimit,
+		Tolerance:    DefaultTolerance,
+		Smoothing:    DefaultSmoothing,
+		Context:      context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &gradient{
+		opts:  options,
+		limit: float64(options.InitialLimit),
+	}
+}
+
+func ewma(avg, sample, window float64) float64 {
+	if avg == 0 {
+		return sample
+	}
+	alpha := 2 / (window + 1)
+	return avg*(1-alpha) + sample*alpha
+}
+
+// timeout reports whether the error indicates the request ran out of time
+func timeout(err error) bool {
+	if err == context.DeadlineExceeded {
+		return true
+	}
+	e := merrors.Parse(err.Error())
+	return e.Code == 408
+}
+
+// clamp must be called with the lock held
+func (g *gradient) clamp(limit float64) float64 {
+	return math.Max(float64(g.opts.MinLimit), math.Min(float64(g.opts.MaxLimit), limit))
+}
+
+// sample updates the limit with the latency of a request handled while
+// inflight requests were in progress. Must be called with the lock held.
+func (g *gradient) sample(rtt time.Duration, inflight int) {
+	v := float64(rtt)
+
+	g.short = ewma(g.short, v, shortWindow)
+	g.long = ewma(g.long, v, longWindow)
+
+	// the long term average catches up quickly after latency drops
+	// so that a recovered server isn't held back by past load
+	if g.long/g.short > 2 {
+		g.long *= 0.95
+	}
+
+	// the limit isn't being tested, don't grow it
+	if float64(inflight) < g.limit/2 {
+		return
+	}
+
+	grad := math.Max(0.5, math.Min(1, g.opts.Tolerance*g.long/g.short))
+	limit := g.limit*grad + math.Sqrt(g.limit)
+	limit = g.limit*(1-g.opts.Smoothing) + limit*g.opts.Smoothing
+
+	g.limit = g.clamp(limit)
+}
+
+func (g *gradient) Init(opts ...Option) error {
+	g.Lock()
+	defer g.Unlock()
+
+	for _, o := range opts {
+		o(&g.opts)
+	}
+
+	g.limit = g.clamp(float64(g.opts.InitialLimit))
+	return nil
+}
+
+func (g *gradient) Options() Options {
+	g.Lock()
+	defer g.Unlock()
+	return g.opts
+}
+
+func (g *gradient) Acquire() (func(error), error) {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.inflight >= int(g.limit) {
+		return nil, ErrLimitExceeded
+	}
+
+	g.inflight++
+	inflight := g.inflight
+	start := time.Now()
+
+	var once sync.Once
+
+	return func(err error) {
+		once.Do(func() {
+			g.Lock()
+			defer g.Unlock()
+
+			g.inflight--
+
+			switch {
+			case err == nil:
+				g.sample(time.Since(start), inflight)
+			case timeout(err):
+				// requests are timing out, back off
+				g.limit = g.clamp(g.limit * backoff)
+			}
+		})
+	}, nil
+}
+
+func (g *gradient) Limit() int {
+	g.Lock()
+	defer g.Unlock()
+	return int(g.limit)
+}
+
+func (g *gradient) Inflight() int {
+	g.Lock()
+	defer g.Unlock()
+	return g.inflight
+}
+
+func (g *gradient) String() string {
+	return "gradient"
+}
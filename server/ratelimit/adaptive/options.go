@@ -0,0 +1,59 @@
+package adaptive
+
+import (
+	"context"
+)
+
+type Options struct {
+	// InitialLimit is the concurrency limit to start with
+	InitialLimit int
+	// MinLimit and MaxLimit bound the concurrency limit
+	MinLimit int
+	MaxLimit int
+	// Tolerance is the ratio of the short term to the long term latency
+	// which is tolerated before the limit is reduced
+	Tolerance float64
+	// Smoothing is how quickly the limit moves towards a new value, between 0 and 1
+	Smoothing float64
+
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
+}
+
+type Option func(o *Options)
+
+// InitialLimit of concurrent requests
+func InitialLimit(n int) Option {
+	return func(o *Options) {
+		o.InitialLimit = n
+	}
+}
+
+// MinLimit the concurrency limit can be reduced to
+func MinLimit(n int) Option {
+	return func(o *Options) {
+		o.MinLimit = n
+	}
+}
+
+// MaxLimit the concurrency limit can grow to
+func MaxLimit(n int) Option {
+	return func(o *Options) {
+		o.MaxLimit = n
+	}
+}
+
+// Tolerance of latency above the long term average e.g 1.5
+func Tolerance(t float64) Option {
+	return func(o *Options) {
+		o.Tolerance = t
+	}
+}
+
+// Smoothing of changes to the limit, between 0 and 1
+func Smoothing(s float64) Option {
+	return func(o *Options) {
+		o.Smoothing = s
+	}
+}
@@ -0,0 +1,205 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultIdle is how long the state of a limit is kept without requests
+	DefaultIdle = time.Minute
+)
+
+// bucket is the state of a limit for the requests counted together
+type bucket struct {
+	limit *Limit
+	// tokens available and when they were last refilled
+	tokens float64
+	last   time.Time
+	// number of requests in flight
+	inflight int
+}
+
+func newBucket(l *Limit, now time.Time) *bucket {
+	return &bucket{
+		limit:  l,
+		tokens: float64(burst(l)),
+		last:   now,
+	}
+}
+
+func burst(l *Limit) int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return int(math.Max(1, math.Ceil(l.Rate)))
+}
+
+// refill adds the tokens accrued since the last refill
+func (b *bucket) refill(now time.Time) {
+	if b.limit.Rate <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(burst(b.limit)), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+}
+
+func (b *bucket) allow() error {
+	if b.limit.Rate > 0 && b.tokens < 1 {
+		return ErrRateLimited
+	}
+	if b.limit.Concurrency > 0 && b.inflight >= b.limit.Concurrency {
+		return ErrConcurrencyLimited
+	}
+	return nil
+}
+
+// idle reports whether the bucket is back to its initial state
+func (b *bucket) idle(now time.Time) bool {
+	return b.inflight == 0 && now.Sub(b.last) > DefaultIdle
+}
+
+type limiter struct {
+	sync.Mutex
+	opts Options
+	// buckets keyed by limit and the caller or endpoint counted separately
+	buckets map[string]*bucket
+	calls   int
+}
+
+// NewLimiter returns a limiter which keeps the state of its limits in memory
+func NewLimiter(opts ...Option) Limiter {
+	options := Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &limiter{
+		opts:    options,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// key returns the key of the bucket counting the request under the limit
+func key(i int, l *Limit, caller, endpoint string) string {
+	k := strconv.Itoa(i)
+	if l.Service == "*" {
+		k += "/" + caller
+	}
+	if l.Endpoint == "*" {
+		k += "/" + endpoint
+	}
+	return k
+}
+
+// prune drops idle buckets e.g of callers which have gone away.
+// Must be called with the lock held.
+func (r *limiter) prune(now time.Time) {
+	r.calls++
+	if r.calls%1000 != 0 {
+		return
+	}
+
+	for k, b := range r.buckets {
+		if b.idle(now) {
+			delete(r.buckets, k)
+		}
+	}
+}
+
+func (r *limiter) Init(opts ...Option) error {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, o := range opts {
+		o(&r.opts)
+	}
+
+	// start counting afresh
+	r.buckets = make(map[string]*bucket)
+	return nil
+}
+
+func (r *limiter) Options() Options {
+	r.Lock()
+	defer r.Unlock()
+	return r.opts
+}
+
+func (r *limiter) Acquire(caller, endpoint string) (func(), error) {
+	r.Lock()
+	defer r.Unlock()
+
+	now := time.Now()
+	r.prune(now)
+
+	var buckets []*bucket
+
+	for i, l := range r.opts.Limits {
+		if !l.Match(caller, endpoint) {
+			continue
+		}
+
+		k := key(i, l, caller, endpoint)
+		b, ok := r.buckets[k]
+		if !ok {
+			b = newBucket(l, now)
+			r.buckets[k] = b
+		}
+
+		b.refill(now)
+
+		// requests must be within every limit
+		if err := b.allow(); err != nil {
+			return nil, err
+		}
+
+		buckets = append(buckets, b)
+	}
+
+	for _, b := range buckets {
+		if b.limit.Rate > 0 {
+			b.tokens--
+		}
+		b.inflight++
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			r.Lock()
+			defer r.Unlock()
+
+			for _, b := range buckets {
+				b.inflight--
+			}
+		})
+	}, nil
+}
+
+func (r *limiter) Update(limits []*Limit) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.opts.Limits = limits
+	// the buckets of the old limits are dropped, requests in flight
+	// release their slots in the buckets they were counted in
+	r.buckets = make(map[string]*bucket)
+}
+
+func (r *limiter) Limits() []*Limit {
+	r.Lock()
+	defer r.Unlock()
+	return r.opts.Limits
+}
+
+func (r *limiter) String() string {
+	return "memory"
+}
@@ -0,0 +1,23 @@
+package ratelimit
+
+import (
+	"context"
+)
+
+type Options struct {
+	// Limits enforced on requests
+	Limits []*Limit
+
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
+}
+
+type Option func(o *Options)
+
+// Limits to enforce, replacing any set before
+func Limits(limits ...*Limit) Option {
+	return func(o *Options) {
+		o.Limits = limits
+	}
+}
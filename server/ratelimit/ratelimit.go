@@ -0,0 +1,151 @@
+// Package ratelimit provides admission control for server handlers
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/micro/go-micro/v2/config"
+	merrors "github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/micro/go-micro/v2/server"
+)
+
+var (
+	// FromServiceHeader is the header holding the name of the calling service
+	FromServiceHeader = "Micro-From-Service"
+
+	// ErrRateLimited is returned when a request is over the rate of a limit
+	ErrRateLimited = errors.New("rate limit exceeded")
+	// ErrConcurrencyLimited is returned when a limit has too many requests in flight
+	ErrConcurrencyLimited = errors.New("concurrency limit exceeded")
+)
+
+// Limiter admits requests from calling services to the endpoints of a server
+type Limiter interface {
+	Init(...Option) error
+	Options() Options
+	// Acquire admits a request from the caller to the endpoint if it is
+	// within every matching limit. The returned func must be called once
+	// the request is done to release its concurrency slot.
+	Acquire(caller, endpoint string) (func(), error)
+	// Update replaces the limits
+	Update(limits []*Limit)
+	// Limits returns the current limits
+	Limits() []*Limit
+	// String returns the name of the implementation
+	String() string
+}
+
+// Limit is a token bucket and concurrency limit for the requests matching
+// the calling service and endpoint. An empty Service or Endpoint matches
+// any and counts all of the requests together, a "*" matches any but
+// counts the requests of each calling service or endpoint separately.
+type Limit struct {
+	// Service is the name of the calling service
+	Service string `json:"service"`
+	// Endpoint e.g Greeter.Hello
+	Endpoint string `json:"endpoint"`
+	// Rate of requests per second, zero is unlimited
+	Rate float64 `json:"rate"`
+	// Burst is the number of requests allowed above the rate,
+	// it defaults to the rate rounded up
+	Burst int `json:"burst"`
+	// Concurrency is the number of requests in flight, zero is unlimited
+	Concurrency int `json:"concurrency"`
+}
+
+// Match reports whether the limit applies to the caller and endpoint
+func (l *Limit) Match(caller, endpoint string) bool {
+	return match(l.Service, caller) && match(l.Endpoint, endpoint)
+}
+
+func match(pattern, v string) bool {
+	return len(pattern) == 0 || pattern == "*" || pattern == v
+}
+
+// NewHandlerWrapper returns a handler wrapper which rejects requests over
+// the limits of the limiter with a 429 Too Many Requests error
+func NewHandlerWrapper(l Limiter) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			caller, _ := metadata.Get(ctx, FromServiceHeader)
+
+			release, err := l.Acquire(caller, req.Endpoint())
+			if err != nil {
+				return merrors.TooManyRequests(req.Service(), "%s: %v", req.Endpoint(), err)
+			}
+			defer release()
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// Load the limits from the config at the path into the limiter and
+// update them whenever they change. The limits are a list of Limit.
+// Stop the returned watcher to stop reloading.
+func Load(l Limiter, c config.Config, path ...string) (config.Watcher, error) {
+	var limits []*Limit
+	if err := c.Get(path...).Scan(&limits); err != nil {
+		return nil, err
+	}
+	l.Update(limits)
+
+	cw, err := c.Watch(path...)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		Watcher: cw,
+		exit:    make(chan bool),
+	}
+
+	go w.run(l)
+
+	return w, nil
+}
+
+type watcher struct {
+	config.Watcher
+
+	once sync.Once
+	exit chan bool
+}
+
+func (w *watcher) run(l Limiter) {
+	for {
+		v, err := w.Next()
+		if err != nil {
+			select {
+			case <-w.exit:
+				return
+			default:
+			}
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("ratelimit: error watching limits: %v", err)
+			}
+			continue
+		}
+
+		var limits []*Limit
+		if err := v.Scan(&limits); err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("ratelimit: error reading limits: %v", err)
+			}
+			continue
+		}
+
+		l.Update(limits)
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.once.Do(func() {
+		close(w.exit)
+	})
+	return w.Watcher.Stop()
+}
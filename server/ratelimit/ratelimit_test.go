@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/config"
+	"github.com/micro/go-micro/v2/config/source"
+	"github.com/micro/go-micro/v2/config/source/memory"
+	"github.com/micro/go-micro/v2/errors"
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/micro/go-micro/v2/server"
+)
+
+type testRequest struct {
+	server.Request
+	service  string
+	endpoint string
+}
+
+func (r *testRequest) Service() string {
+	return r.service
+}
+
+func (r *testRequest) Endpoint() string {
+	return r.endpoint
+}
+
+func TestRate(t *testing.T) {
+	l := NewLimiter(Limits(&Limit{Endpoint: "Foo.Bar", Rate: 1, Burst: 2}))
+
+	for i := 0; i < 2; i++ {
+		release, err := l.Acquire("caller", "Foo.Bar")
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		release()
+	}
+
+	if _, err := l.Acquire("other", "Foo.Bar"); err != ErrRateLimited {
+		t.Fatalf("expected %v got %v", ErrRateLimited, err)
+	}
+
+	// other endpoints are not limited
+	if _, err := l.Acquire("caller", "Foo.Baz"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	l := NewLimiter(Limits(&Limit{Concurrency: 1}))
+
+	release, err := l.Acquire("caller", "Foo.Bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.Acquire("caller", "Foo.Baz"); err != ErrConcurrencyLimited {
+		t.Fatalf("expected %v got %v", ErrConcurrencyLimited, err)
+	}
+
+	// releasing more than once has no effect
+	release()
+	release()
+
+	if _, err := l.Acquire("caller", "Foo.Bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Acquire("caller", "Foo.Bar"); err != ErrConcurrencyLimited {
+		t.Fatalf("expected %v got %v", ErrConcurrencyLimited, err)
+	}
+}
+
+func TestPerCaller(t *testing.T) {
+	l := NewLimiter(Limits(
+		&Limit{Service: "*", Rate: 1, Burst: 1},
+		&Limit{Service: "trusted", Endpoint: "Foo.Bar", Concurrency: 10},
+	))
+
+	// each caller has its own bucket
+	for _, caller := range []string{"a", "b", "trusted"} {
+		if _, err := l.Acquire(caller, "Foo.Bar"); err != nil {
+			t.Fatalf("%s: %v", caller, err)
+		}
+	}
+
+	// every matching limit applies
+	if _, err := l.Acquire("trusted", "Foo.Bar"); err != ErrRateLimited {
+		t.Fatalf("expected %v got %v", ErrRateLimited, err)
+	}
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	l := NewLimiter(Limits(&Limit{Service: "greedy", Rate: 1, Burst: 1}))
+
+	h := NewHandlerWrapper(l)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+
+	req := &testRequest{service: "go.micro.srv.foo", endpoint: "Foo.Bar"}
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		FromServiceHeader: "greedy",
+	})
+
+	if err := h(ctx, req, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	err := h(ctx, req, nil)
+	if e := errors.FromError(err); e == nil || e.Code != 429 {
+		t.Fatalf("expected 429 error got %v", err)
+	}
+
+	// other callers are not limited
+	if err := h(context.Background(), req, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	src := memory.NewSource(memory.WithJSON([]byte(`{
+		"ratelimit": [{"endpoint": "Foo.Bar", "concurrency": 1}]
+	}`)))
+
+	c, err := config.NewConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Load(src); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLimiter()
+
+	w, err := Load(l, c, "ratelimit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	limits := l.Limits()
+	if len(limits) != 1 || limits[0].Endpoint != "Foo.Bar" || limits[0].Concurrency != 1 {
+		t.Fatalf("unexpected limits %+v", limits)
+	}
+
+	// let the config start watching the source
+	time.Sleep(100 * time.Millisecond)
+
+	err = src.Write(&source.ChangeSet{
+		Data:   []byte(`{"ratelimit": [{"endpoint": "Foo.Baz", "rate": 10}]}`),
+		Format: "json",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		limits = l.Limits()
+		if len(limits) == 1 && limits[0].Endpoint == "Foo.Baz" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("limits not reloaded %+v", limits)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/micro/go-micro/v2/broker"
 	"github.com/micro/go-micro/v2/codec"
 	raw "github.com/micro/go-micro/v2/codec/bytes"
+	"github.com/micro/go-micro/v2/health"
 	"github.com/micro/go-micro/v2/logger"
 	"github.com/micro/go-micro/v2/metadata"
 	"github.com/micro/go-micro/v2/registry"
@@ -41,6 +42,8 @@ type rpcServer struct {
 	subscriber broker.Subscriber
 	// graceful exit
 	wg *sync.WaitGroup
+	// health status of the node
+	health string
 
 	rsvc *registry.Service
 }
@@ -512,6 +515,57 @@ func (s *rpcServer) Subscribe(sb Subscriber) error {
 	return nil
 }
 
+// checkHealth runs the health checks of the node, returning true if its status changed
+func (s *rpcServer) checkHealth() bool {
+	s.RLock()
+	config := s.opts
+	status := s.health
+	s.RUnlock()
+
+	if len(config.HealthChecks) == 0 {
+		return false
+	}
+
+	node := &registry.Node{
+		Id:      config.Name + "-" + config.Id,
+		Address: config.Address,
+	}
+	if len(config.Advertise) > 0 {
+		node.Address = config.Advertise
+	}
+
+	ctx := context.Background()
+	if config.HealthCheckInterval > time.Duration(0) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.HealthCheckInterval)
+		defer cancel()
+	}
+
+	next := health.Passing
+	if err := health.Run(ctx, config.Name, node, config.HealthChecks...); err != nil {
+		next = health.Failing
+		if status != next && logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			log.Errorf("Server %s-%s health check error: %s", config.Name, config.Id, err)
+		}
+	}
+
+	if status == next {
+		return false
+	}
+
+	if logger.V(logger.InfoLevel, logger.DefaultLogger) {
+		log.Infof("Server %s-%s health status: %s", config.Name, config.Id, next)
+	}
+
+	// the service is built again with the new status
+	s.Lock()
+	s.health = next
+	s.rsvc = nil
+	s.Unlock()
+
+	return true
+}
+
 func (s *rpcServer) Register() error {
 	s.RLock()
 	rsvc := s.rsvc
@@ -604,6 +658,11 @@ func (s *rpcServer) Register() error {
 
 	s.RLock()
 
+	// publish the health status once checked
+	if len(config.HealthChecks) > 0 && len(s.health) > 0 {
+		node.Metadata[health.StatusKey] = s.health
+	}
+
 	// Maps are ordered randomly, sort the keys for consistency
 	var handlerList []string
 	for n, e := range s.handlers {
@@ -698,14 +757,25 @@ func (s *rpcServer) Register() error {
 			opts = append(opts, broker.DisableAutoAck())
 		}
 
-		sub, err := config.Broker.Subscribe(sb.Topic(), s.HandleEvent, opts...)
+		// redelivery is applied here rather than by the broker so it
+		// works the same regardless of the broker implementation
+		ropts, cancel := broker.WithCancel(broker.NewSubscribeOptions(
+			append(opts,
+				broker.MaxAttempts(sb.Options().MaxAttempts),
+				broker.RedeliveryBackoff(sb.Options().Backoff),
+			)...,
+		))
+		handler := broker.Redeliver(config.Broker, sb.Topic(), s.HandleEvent, ropts)
+
+		sub, err := config.Broker.Subscribe(sb.Topic(), handler, opts...)
 		if err != nil {
+			cancel()
 			return err
 		}
 		if logger.V(logger.InfoLevel, logger.DefaultLogger) {
 			log.Infof("Subscribing to topic: %s", sub.Topic())
 		}
-		s.subscribers[sb] = []broker.Subscriber{sub}
+		s.subscribers[sb] = []broker.Subscriber{&redeliverySubscriber{sub, cancel}}
 	}
 	if cacheService {
 		s.rsvc = service
@@ -715,6 +785,17 @@ func (s *rpcServer) Register() error {
 	return nil
 }
 
+// redeliverySubscriber stops redelivering the messages of the subscriber once it unsubscribes
+type redeliverySubscriber struct {
+	broker.Subscriber
+	cancel context.CancelFunc
+}
+
+func (r *redeliverySubscriber) Unsubscribe() error {
+	r.cancel()
+	return r.Subscriber.Unsubscribe()
+}
+
 func (s *rpcServer) Deregister() error {
 	var err error
 	var advt, host, port string
@@ -840,6 +921,9 @@ func (s *rpcServer) Start() error {
 		log.Infof("Broker [%s] Connected to %s", bname, config.Broker.Address())
 	}
 
+	// check the health before registering
+	s.checkHealth()
+
 	// use RegisterCheck func before register
 	if err = s.opts.RegisterCheck(s.opts.Context); err != nil {
 		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
@@ -893,6 +977,13 @@ func (s *rpcServer) Start() error {
 			t = time.NewTicker(s.opts.RegisterInterval)
 		}
 
+		ht := new(time.Ticker)
+
+		// only check the health if there are checks
+		if len(s.opts.HealthChecks) > 0 && s.opts.HealthCheckInterval > time.Duration(0) {
+			ht = time.NewTicker(s.opts.HealthCheckInterval)
+		}
+
 		// return error chan
 		var ch chan error
 
@@ -926,9 +1017,23 @@ func (s *rpcServer) Start() error {
 						log.Errorf("Server %s-%s register error: %s", config.Name, config.Id, err)
 					}
 				}
+			// check the health on interval
+			case <-ht.C:
+				s.RLock()
+				registered := s.registered
+				s.RUnlock()
+				// register again with the new status
+				if s.checkHealth() && registered {
+					if err := s.Register(); err != nil {
+						if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+							log.Errorf("Server %s-%s register error: %s", config.Name, config.Id, err)
+						}
+					}
+				}
 			// wait for exit
 			case ch = <-s.exit:
 				t.Stop()
+				ht.Stop()
 				close(exit)
 				break Loop
 			}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/broker/memory"
+	"github.com/micro/go-micro/v2/health"
+	rmemory "github.com/micro/go-micro/v2/registry/memory"
+	tmemory "github.com/micro/go-micro/v2/transport/memory"
+)
+
+func TestRPCServerHealthCheck(t *testing.T) {
+	var mtx sync.Mutex
+	var err error
+
+	check := health.Func(func(context.Context) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return err
+	})
+
+	r := rmemory.NewRegistry()
+	s := newRpcServer(
+		Name("foo"),
+		Registry(r),
+		Broker(memory.NewBroker()),
+		Transport(tmemory.NewTransport()),
+		HealthCheck(check),
+		HealthCheckInterval(50*time.Millisecond),
+	)
+
+	status := func() string {
+		services, err := r.GetService("foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(services) != 1 || len(services[0].Nodes) != 1 {
+			t.Fatalf("Expected a single node, got %+v", services)
+		}
+		return services[0].Nodes[0].Metadata[health.StatusKey]
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if st := status(); st != health.Passing {
+		t.Fatalf("Expected %s, got %s", health.Passing, st)
+	}
+
+	// the node is registered again as soon as the check fails
+	mtx.Lock()
+	err = errors.New("unhealthy")
+	mtx.Unlock()
+	time.Sleep(200 * time.Millisecond)
+
+	if st := status(); st != health.Failing {
+		t.Fatalf("Expected %s, got %s", health.Failing, st)
+	}
+
+	mtx.Lock()
+	err = nil
+	mtx.Unlock()
+	time.Sleep(200 * time.Millisecond)
+
+	if st := status(); st != health.Passing {
+		t.Fatalf("Expected %s, got %s", health.Passing, st)
+	}
+}
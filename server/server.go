@@ -137,15 +137,16 @@ type Subscriber interface {
 type Option func(*Options)
 
 var (
-	DefaultAddress                 = ":0"
-	DefaultName                    = "go.micro.server"
-	DefaultVersion                 = "latest"
-	DefaultId                      = uuid.New().String()
-	DefaultServer           Server = newRpcServer()
-	DefaultRouter                  = newRpcRouter()
-	DefaultRegisterCheck           = func(context.Context) error { return nil }
-	DefaultRegisterInterval        = time.Second * 30
-	DefaultRegisterTTL             = time.Second * 90
+	DefaultAddress                    = ":0"
+	DefaultName                       = "go.micro.server"
+	DefaultVersion                    = "latest"
+	DefaultId                         = uuid.New().String()
+	DefaultServer              Server = newRpcServer()
+	DefaultRouter                     = newRpcRouter()
+	DefaultRegisterCheck              = func(context.Context) error { return nil }
+	DefaultRegisterInterval           = time.Second * 30
+	DefaultRegisterTTL                = time.Second * 90
+	DefaultHealthCheckInterval        = time.Second * 10
 
 	// NewServer creates a new server
 	NewServer func(...Option) Server = newRpcServer
@@ -1,6 +1,7 @@
 package micro
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	rtime "runtime"
@@ -10,6 +11,7 @@ import (
 	"github.com/micro/go-micro/v2/auth"
 	"github.com/micro/go-micro/v2/client"
 	"github.com/micro/go-micro/v2/config/cmd"
+	"github.com/micro/go-micro/v2/debug/metrics"
 	"github.com/micro/go-micro/v2/debug/service/handler"
 	"github.com/micro/go-micro/v2/debug/stats"
 	"github.com/micro/go-micro/v2/debug/trace"
@@ -27,6 +29,20 @@ type service struct {
 	once sync.Once
 }
 
+// metricsKey marks the options of a client or server already wrapped to record metrics
+type metricsKey struct{}
+
+func withMetrics(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, metricsKey{}, true)
+}
+
+func hasMetrics(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(metricsKey{}) != nil
+}
+
 func newService(opts ...Option) Service {
 	service := new(service)
 	options := newOptions(opts...)
@@ -43,13 +59,33 @@ func newService(opts ...Option) Service {
 	options.Client = wrapper.TraceCall(serviceName, trace.DefaultTracer, options.Client)
 	options.Client = wrapper.CacheClient(cacheFn, options.Client)
 	options.Client = wrapper.AuthClient(authFn, options.Client)
+	options.Client = wrapper.PublishMetrics(metrics.DefaultRegistry, options.Client)
+
+	// record the requests made to each node, once as the client may be shared by services
+	if !hasMetrics(options.Client.Options().Context) {
+		options.Client.Init(
+			client.WrapCall(wrapper.CallMetrics(metrics.DefaultRegistry)),
+			func(o *client.Options) { o.Context = withMetrics(o.Context) },
+		)
+	}
 
 	// wrap the server to provide handler stats
-	options.Server.Init(
+	serverOpts := []server.Option{
 		server.WrapHandler(wrapper.HandlerStats(stats.DefaultStats)),
+	}
+	// record the requests handled, once as the server may be shared by services
+	if !hasMetrics(options.Server.Options().Context) {
+		serverOpts = append(serverOpts,
+			server.WrapHandler(wrapper.HandlerMetrics(metrics.DefaultRegistry)),
+			server.WrapSubscriber(wrapper.SubscriberMetrics(metrics.DefaultRegistry)),
+			func(o *server.Options) { o.Context = withMetrics(o.Context) },
+		)
+	}
+	serverOpts = append(serverOpts,
 		server.WrapHandler(wrapper.TraceHandler(trace.DefaultTracer)),
 		server.WrapHandler(wrapper.AuthHandler(authFn)),
 	)
+	options.Server.Init(serverOpts...)
 
 	// set opts
 	service.opts = options
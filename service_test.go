@@ -9,6 +9,7 @@ import (
 	"github.com/micro/go-micro/v2/client"
 	proto "github.com/micro/go-micro/v2/debug/service/proto"
 	"github.com/micro/go-micro/v2/registry/memory"
+	"github.com/micro/go-micro/v2/server"
 	"github.com/micro/go-micro/v2/util/test"
 )
 
@@ -173,3 +174,26 @@ func BenchmarkService32(b *testing.B) {
 func BenchmarkService64(b *testing.B) {
 	benchmarkService(b, 64, "test.service.64")
 }
+
+// TestServiceMetricsWrappers tests the client and server shared by services are wrapped to record metrics once
+func TestServiceMetricsWrappers(t *testing.T) {
+	c := client.NewClient()
+	s := server.NewServer()
+
+	NewService(Client(c), Server(s))
+	calls := len(c.Options().CallOptions.CallWrappers)
+	subs := len(s.Options().SubWrappers)
+	handlers := len(s.Options().HdlrWrappers)
+
+	NewService(Client(c), Server(s))
+	if n := len(c.Options().CallOptions.CallWrappers); n != calls {
+		t.Fatalf("Expected %d call wrappers, got %d", calls, n)
+	}
+	if n := len(s.Options().SubWrappers); n != subs {
+		t.Fatalf("Expected %d subscriber wrappers, got %d", subs, n)
+	}
+	// only the handler wrappers other than the metrics one are added again
+	if n := len(s.Options().HdlrWrappers); n != handlers*2-1 {
+		t.Fatalf("Expected %d handler wrappers, got %d", handlers*2-1, n)
+	}
+}
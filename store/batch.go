@@ -0,0 +1,215 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// Batch is a set of operations on the keys of a table which are applied together
+type Batch struct {
+	// Reads are the versions of the records the batch depends on,
+	// a version of 0 means the record must not exist
+	Reads map[string]uint64
+	// Writes are the records to write
+	Writes []*Record
+	// Deletes are the keys to delete
+	Deletes []string
+}
+
+// Tx is a transaction over multiple keys of a table. Reads are made against the
+// store and the versions of the records read are kept as the read set. Writes and
+// deletes are buffered until Commit, which applies them as a Batch.
+type Tx interface {
+	// Read records from the store, a single key reads the writes of the transaction
+	Read(key string, opts ...ReadOption) ([]*Record, error)
+	// Write a record on commit. WriteVersion and WriteIfAbsent are added to the read set.
+	Write(r *Record, opts ...WriteOption) error
+	// Delete a record on commit
+	Delete(key string, opts ...DeleteOption) error
+	// Commit the transaction, ErrConflict is returned if any of the records read have changed
+	Commit() error
+	// Rollback discards the transaction
+	Rollback() error
+}
+
+type tx struct {
+	store   Store
+	options BatchOptions
+
+	sync.Mutex
+	reads   map[string]uint64
+	writes  map[string]*Record
+	deletes map[string]bool
+	// order the keys were written or deleted in
+	keys []string
+	done bool
+}
+
+// NewTx returns a transaction on the store
+func NewTx(s Store, opts ...BatchOption) Tx {
+	var options BatchOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &tx{
+		store:   s,
+		options: options,
+		reads:   make(map[string]uint64),
+		writes:  make(map[string]*Record),
+		deletes: make(map[string]bool),
+	}
+}
+
+func (t *tx) Read(key string, opts ...ReadOption) ([]*Record, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.done {
+		return nil, ErrTxDone
+	}
+
+	var options ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	single := !options.Prefix && !options.Suffix
+
+	// read our own writes
+	if single {
+		if t.deletes[key] {
+			return nil, ErrNotFound
+		}
+		if r, ok := t.writes[key]; ok {
+			return []*Record{copyRecord(r)}, nil
+		}
+	}
+
+	records, err := t.store.Read(key, append(opts, ReadFrom(t.options.Database, t.options.Table))...)
+	if err == ErrNotFound && single {
+		t.read(key, 0)
+	}
+	if err != nil {
+		return records, err
+	}
+
+	for _, r := range records {
+		t.read(r.Key, r.Version)
+	}
+
+	return records, nil
+}
+
+// read adds the version to the read set if the key hasn't been read
+func (t *tx) read(key string, version uint64) {
+	if _, ok := t.reads[key]; !ok {
+		t.reads[key] = version
+	}
+}
+
+func (t *tx) Write(r *Record, opts ...WriteOption) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.done {
+		return ErrTxDone
+	}
+
+	var options WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rec := copyRecord(r)
+	if !options.Expiry.IsZero() {
+		rec.Expiry = time.Until(options.Expiry)
+	}
+	if options.TTL != 0 {
+		rec.Expiry = options.TTL
+	}
+
+	switch {
+	case options.IfAbsent:
+		t.reads[r.Key] = 0
+	case options.Version > 0:
+		t.reads[r.Key] = options.Version
+	}
+
+	delete(t.deletes, r.Key)
+	t.writes[r.Key] = rec
+	t.keys = append(t.keys, r.Key)
+
+	return nil
+}
+
+func (t *tx) Delete(key string, opts ...DeleteOption) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.done {
+		return ErrTxDone
+	}
+
+	delete(t.writes, key)
+	t.deletes[key] = true
+	t.keys = append(t.keys, key)
+
+	return nil
+}
+
+func (t *tx) Commit() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+
+	b := &Batch{Reads: t.reads}
+
+	// the last write or delete of a key wins
+	seen := make(map[string]bool)
+	for _, k := range t.keys {
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		if r, ok := t.writes[k]; ok {
+			b.Writes = append(b.Writes, r)
+		} else if t.deletes[k] {
+			b.Deletes = append(b.Deletes, k)
+		}
+	}
+
+	return t.store.Batch(b, BatchTo(t.options.Database, t.options.Table))
+}
+
+func (t *tx) Rollback() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+
+	return nil
+}
+
+func copyRecord(r *Record) *Record {
+	rec := &Record{
+		Key:      r.Key,
+		Value:    make([]byte, len(r.Value)),
+		Metadata: make(map[string]interface{}),
+		Expiry:   r.Expiry,
+		Version:  r.Version,
+	}
+	copy(rec.Value, r.Value)
+	for k, v := range r.Metadata {
+		rec.Metadata[k] = v
+	}
+	return rec
+}
@@ -0,0 +1,97 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/memory"
+)
+
+func TestTx(t *testing.T) {
+	s := memory.NewStore()
+
+	if err := s.Write(&store.Record{Key: "order", Value: []byte("1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := store.NewTx(s)
+
+	r, err := tx.Read("order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Read("index/1"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+
+	tx.Write(&store.Record{Key: "order", Value: append(r[0].Value, '2')})
+	tx.Write(&store.Record{Key: "index/1", Value: []byte("order")})
+	tx.Delete("stale")
+
+	// reads see the writes of the transaction
+	r, err = tx.Read("order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(r[0].Value) != "12" {
+		t.Fatalf("Expected 12, got %s", r[0].Value)
+	}
+
+	// nothing is written until commit
+	if _, err := s.Read("index/1"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != store.ErrTxDone {
+		t.Fatalf("Expected %v, got %v", store.ErrTxDone, err)
+	}
+
+	for key, value := range map[string]string{"order": "12", "index/1": "order"} {
+		r, err := s.Read(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(r[0].Value) != value {
+			t.Fatalf("Expected %s, got %s", value, r[0].Value)
+		}
+	}
+}
+
+func TestTxConflict(t *testing.T) {
+	s := memory.NewStore()
+
+	if err := s.Write(&store.Record{Key: "order", Value: []byte("1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := store.NewTx(s)
+	if _, err := tx.Read("order"); err != nil {
+		t.Fatal(err)
+	}
+	tx.Write(&store.Record{Key: "index/1", Value: []byte("order")})
+
+	// the record read by the transaction changes
+	if err := s.Write(&store.Record{Key: "order", Value: []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err != store.ErrConflict {
+		t.Fatalf("Expected %v, got %v", store.ErrConflict, err)
+	}
+	if _, err := s.Read("index/1"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+
+	// a rolled back transaction writes nothing
+	tx = store.NewTx(s)
+	tx.Write(&store.Record{Key: "index/1", Value: []byte("order")})
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read("index/1"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+}
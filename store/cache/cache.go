@@ -3,14 +3,34 @@ package cache
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/micro/go-micro/v2/logger"
 	"github.com/micro/go-micro/v2/store"
 	"github.com/micro/go-micro/v2/store/memory"
 	"github.com/pkg/errors"
 )
 
+var (
+	// the time to wait before watching the last store again after an error
+	watchRetryTime = time.Second
+)
+
 type cache struct {
 	stores []store.Store
+
+	// versions of the records in the last store, which is the
+	// source of truth for the version of a record
+	sync.RWMutex
+	versions map[string]uint64
+
+	// watches the last store to invalidate the cached records
+	wmtx    sync.Mutex
+	watcher store.Watcher
+	exit    chan bool
+	// how long to wait before watching again
+	retry time.Duration
 }
 
 // Cache is a cpu register style cache for the store.
@@ -30,13 +50,142 @@ func NewCache(stores ...store.Store) Cache {
 
 	// TODO: build in an in memory cache
 	c := &cache{
-		stores: stores,
+		stores:   stores,
+		versions: make(map[string]uint64),
+		exit:     make(chan bool),
+		retry:    watchRetryTime,
+	}
+
+	if len(stores) > 1 {
+		// watch before returning so no changes are missed
+		c.watch()
+		go c.run()
 	}
 
 	return c
 }
 
+// watch the last store, falling back to expiry if it can't be watched
+func (c *cache) watch() store.Watcher {
+	last := c.stores[len(c.stores)-1]
+
+	w, err := last.Watch()
+	if err != nil {
+		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+			logger.Debugf("store/cache: error watching %s: %v", last.String(), err)
+		}
+		return nil
+	}
+
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+
+	select {
+	case <-c.exit:
+		w.Stop()
+		return nil
+	default:
+	}
+
+	c.watcher = w
+	return w
+}
+
+// run invalidates the cached records as the records of the last store change
+func (c *cache) run() {
+	c.wmtx.Lock()
+	w := c.watcher
+	c.wmtx.Unlock()
+
+	for {
+		if w != nil {
+			err := c.invalidate(w)
+			w.Stop()
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("store/cache: watcher stopped: %v", err)
+			}
+		}
+
+		select {
+		case <-c.exit:
+			return
+		case <-time.After(c.retry):
+		}
+
+		// the changes made while not watching were missed
+		if w = c.watch(); w != nil {
+			c.flush()
+		}
+	}
+}
+
+// flush all the cached records, so they're read from the last store again
+func (c *cache) flush() {
+	for i := len(c.stores) - 2; i >= 0; i-- {
+		keys, err := c.stores[i].List()
+		if err != nil {
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("store/cache: error flushing L%d cache (%s): %v", i, c.stores[i].String(), err)
+			}
+			continue
+		}
+
+		for _, key := range keys {
+			if err := c.stores[i].Delete(key); err != nil {
+				if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+					logger.Debugf("store/cache: error invalidating %s in L%d cache (%s): %v", key, i, c.stores[i].String(), err)
+				}
+			}
+		}
+	}
+
+	c.Lock()
+	c.versions = make(map[string]uint64)
+	c.Unlock()
+}
+
+// invalidate the records in the events of the watcher until it errors
+func (c *cache) invalidate(w store.Watcher) error {
+	for {
+		ev, err := w.Next()
+		if err != nil {
+			return err
+		}
+
+		key := ev.Record.Key
+
+		// the cached record is already at the version
+		if v, ok := c.version(key); ok && ev.Type != store.Delete && v >= ev.Record.Version {
+			continue
+		}
+
+		for i := len(c.stores) - 2; i >= 0; i-- {
+			if err := c.stores[i].Delete(key); err != nil {
+				if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+					logger.Debugf("store/cache: error invalidating %s in L%d cache (%s): %v", key, i, c.stores[i].String(), err)
+				}
+			}
+		}
+
+		c.resetVersion(key)
+	}
+}
+
 func (c *cache) Close() error {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+
+	select {
+	case <-c.exit:
+		return nil
+	default:
+		close(c.exit)
+	}
+
+	if c.watcher != nil {
+		c.watcher.Stop()
+	}
+
 	return nil
 }
 
@@ -69,6 +218,11 @@ func (c *cache) Read(key string, opts ...store.ReadOption) ([]*store.Record, err
 		o(&readOpts)
 	}
 
+	// only the last store has all the records to filter and order
+	if len(readOpts.Filters) > 0 || len(readOpts.Order) > 0 {
+		return c.stores[len(c.stores)-1].Read(key, opts...)
+	}
+
 	if readOpts.Prefix || readOpts.Suffix {
 		// List, then try cached gets for each key
 		var lOpts []store.ListOption
@@ -108,29 +262,83 @@ func (c *cache) Read(key string, opts ...store.ReadOption) ([]*store.Record, err
 }
 
 func (c *cache) readOne(key string, opts ...store.ReadOption) (*store.Record, error) {
+	last := len(c.stores) - 1
+
 	for i, s := range c.stores {
 		// ReadOne ignores all options
 		r, err := s.Read(key)
-		if err == nil {
-			if len(r) > 1 {
-				return nil, errors.Wrapf(err, "read from L%d cache (%s) returned multiple records", i, c.stores[i].String())
+		if err != nil {
+			continue
+		}
+		if len(r) > 1 {
+			return nil, errors.Wrapf(err, "read from L%d cache (%s) returned multiple records", i, c.stores[i].String())
+		}
+
+		rec := r[0]
+
+		// the version of a cached record is only known if it was read
+		// from the last store, otherwise fault through to find it
+		if i < last {
+			v, ok := c.version(key)
+			if !ok {
+				continue
 			}
-			for j := i - 1; j >= 0; j-- {
-				err := c.stores[j].Write(r[0])
-				if err != nil {
-					return nil, errors.Wrapf(err, "could not write to L%d cache (%s)", j, c.stores[j].String())
-				}
+			rec.Version = v
+		} else {
+			c.setVersion(key, rec.Version)
+		}
+
+		for j := i - 1; j >= 0; j-- {
+			err := c.stores[j].Write(rec)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not write to L%d cache (%s)", j, c.stores[j].String())
 			}
-			return r[0], nil
 		}
+		return rec, nil
 	}
 	return nil, store.ErrNotFound
 }
 
+func (c *cache) version(key string) (uint64, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	v, ok := c.versions[key]
+	return v, ok
+}
+
+func (c *cache) setVersion(key string, v uint64) {
+	c.Lock()
+	c.versions[key] = v
+	c.Unlock()
+}
+
+func (c *cache) resetVersion(key string) {
+	c.Lock()
+	delete(c.versions, key)
+	c.Unlock()
+}
+
 func (c *cache) Write(r *store.Record, opts ...store.WriteOption) error {
+	// conditional writes are only checked against the last store,
+	// the versions of the cached records are their own
+	cacheOpts := append(opts[:len(opts):len(opts)], func(w *store.WriteOptions) {
+		w.Version = 0
+		w.IfAbsent = false
+	})
+
+	// the version changes with the write so fault the next read through
+	defer c.resetVersion(r.Key)
+
 	// Write to all layers in reverse
 	for i := len(c.stores) - 1; i >= 0; i-- {
-		if err := c.stores[i].Write(r, opts...); err != nil {
+		o := cacheOpts
+		if i == len(c.stores)-1 {
+			o = opts
+		}
+		err := c.stores[i].Write(r, o...)
+		if err == store.ErrConflict {
+			return err
+		} else if err != nil {
 			return errors.Wrapf(err, "could not write to L%d cache (%s)", i, c.stores[i].String())
 		}
 	}
@@ -138,6 +346,8 @@ func (c *cache) Write(r *store.Record, opts ...store.WriteOption) error {
 }
 
 func (c *cache) Delete(key string, opts ...store.DeleteOption) error {
+	defer c.resetVersion(key)
+
 	for i, s := range c.stores {
 		if err := s.Delete(key, opts...); err != nil {
 			return errors.Wrapf(err, "could not delete from L%d cache (%s)", i, c.stores[i].String())
@@ -146,6 +356,43 @@ func (c *cache) Delete(key string, opts ...store.DeleteOption) error {
 	return nil
 }
 
+func (c *cache) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	last := len(c.stores) - 1
+
+	// the read set is only checked against the last store
+	if err := c.stores[last].Batch(b, opts...); err == store.ErrConflict {
+		return err
+	} else if err != nil {
+		return errors.Wrapf(err, "could not apply batch to L%d cache (%s)", last, c.stores[last].String())
+	}
+
+	defer func() {
+		for _, r := range b.Writes {
+			c.resetVersion(r.Key)
+		}
+		for _, key := range b.Deletes {
+			c.resetVersion(key)
+		}
+	}()
+
+	cached := &store.Batch{
+		Writes:  b.Writes,
+		Deletes: b.Deletes,
+	}
+
+	for i := last - 1; i >= 0; i-- {
+		if err := c.stores[i].Batch(cached, opts...); err != nil {
+			return errors.Wrapf(err, "could not apply batch to L%d cache (%s)", i, c.stores[i].String())
+		}
+	}
+	return nil
+}
+
+// Watch the last store
+func (c *cache) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	return c.stores[len(c.stores)-1].Watch(opts...)
+}
+
 func (c *cache) List(opts ...store.ListOption) ([]string, error) {
 	// List only makes sense from the top level
 	return c.stores[len(c.stores)-1].List(opts...)
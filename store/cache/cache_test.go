@@ -3,9 +3,11 @@ package cache
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/micro/go-micro/v2/store"
 	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/micro/go-micro/v2/store/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,16 +33,19 @@ func TestCache(t *testing.T) {
 		Key:      "aaa",
 		Value:    []byte("bbb"),
 		Metadata: map[string]interface{}{},
+		Version:  1,
 	}
 	r2 := &store.Record{
 		Key:      "aaaa",
 		Value:    []byte("bbbb"),
 		Metadata: map[string]interface{}{},
+		Version:  1,
 	}
 	r3 := &store.Record{
 		Key:      "aaaaa",
 		Value:    []byte("bbbbb"),
 		Metadata: map[string]interface{}{},
+		Version:  1,
 	}
 	// Write 3 records directly to l2
 	l2.Write(r1)
@@ -97,3 +102,101 @@ func TestCache(t *testing.T) {
 	assert.Equal(r1, l2result[0], "Write didn't make it all the way through to l2")
 
 }
+
+func TestCacheVersion(t *testing.T) {
+	l0, l1 := memory.NewStore(), memory.NewStore(store.Table("l1"))
+	_, _ = l0.Init(), l1.Init()
+
+	assert := assert.New(t)
+	cachedStore := NewCache(l0, l1)
+
+	// the version is taken from the last store
+	l1.Write(&store.Record{Key: "a", Value: []byte("a")})
+	l1.Write(&store.Record{Key: "a", Value: []byte("b")})
+	results, err := cachedStore.Read("a")
+	assert.Nil(err)
+	assert.Equal(uint64(2), results[0].Version, "version not read from the last store")
+	results, err = cachedStore.Read("a")
+	assert.Nil(err)
+	assert.Equal(uint64(2), results[0].Version, "cached version doesn't match the last store")
+
+	// conditional writes are checked against the last store
+	assert.Equal(store.ErrConflict, cachedStore.Write(&store.Record{Key: "a", Value: []byte("c")}, store.WriteVersion(1)))
+	assert.Nil(cachedStore.Write(&store.Record{Key: "a", Value: []byte("c")}, store.WriteVersion(2)))
+	assert.Equal(store.ErrConflict, cachedStore.Write(&store.Record{Key: "a", Value: []byte("d")}, store.WriteIfAbsent()))
+
+	results, err = cachedStore.Read("a")
+	assert.Nil(err)
+	assert.Equal("c", string(results[0].Value))
+	assert.Equal(uint64(3), results[0].Version)
+}
+
+func TestCacheQuery(t *testing.T) {
+	test.RunQuery(t, func(indexes ...string) store.Store {
+		return NewCache(memory.NewStore(), memory.NewStore(store.Indexes(indexes...)))
+	})
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	l0, l1 := memory.NewStore(), memory.NewStore(store.Table("l1"))
+	_, _ = l0.Init(), l1.Init()
+
+	assert := assert.New(t)
+	cachedStore := NewCache(l0, l1)
+	defer cachedStore.Close()
+
+	l1.Write(&store.Record{Key: "a", Value: []byte("a")})
+	results, err := cachedStore.Read("a")
+	assert.Nil(err)
+	assert.Equal("a", string(results[0].Value))
+
+	// a write to the last store invalidates the cached record
+	l1.Write(&store.Record{Key: "a", Value: []byte("b")})
+
+	for i := 0; i < 100; i++ {
+		if _, err := l0.Read("a"); err == store.ErrNotFound {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	results, err = cachedStore.Read("a")
+	assert.Nil(err)
+	assert.Equal("b", string(results[0].Value))
+	assert.Equal(uint64(2), results[0].Version)
+}
+
+func TestCacheWatchFailure(t *testing.T) {
+	watchRetryTime = 10 * time.Millisecond
+
+	l0, l1 := memory.NewStore(), memory.NewStore(store.Table("l1"))
+	_, _ = l0.Init(), l1.Init()
+
+	assert := assert.New(t)
+	cachedStore := NewCache(l0, l1)
+	defer cachedStore.Close()
+
+	l1.Write(&store.Record{Key: "a", Value: []byte("a")})
+	results, err := cachedStore.Read("a")
+	assert.Nil(err)
+	assert.Equal("a", string(results[0].Value))
+
+	// the write is missed while the last store isn't watched
+	c := cachedStore.(*cache)
+	c.wmtx.Lock()
+	c.watcher.Stop()
+	c.wmtx.Unlock()
+	l1.Write(&store.Record{Key: "a", Value: []byte("b")})
+
+	// the cached records are flushed once it's watched again
+	for i := 0; i < 100; i++ {
+		if _, err := l0.Read("a"); err == store.ErrNotFound {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	results, err = cachedStore.Read("a")
+	assert.Nil(err)
+	assert.Equal("b", string(results[0].Value))
+}
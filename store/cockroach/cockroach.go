@@ -2,6 +2,7 @@
 package cockroach
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
@@ -26,13 +27,22 @@ var (
 var (
 	re = regexp.MustCompile("[^a-zA-Z0-9]+")
 
+	// escapes the LIKE special characters
+	likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 	statements = map[string]string{
-		"list":       "SELECT key, value, metadata, expiry FROM %s.%s;",
-		"read":       "SELECT key, value, metadata, expiry FROM %s.%s WHERE key = $1;",
-		"readMany":   "SELECT key, value, metadata, expiry FROM %s.%s WHERE key LIKE $1;",
-		"readOffset": "SELECT key, value, metadata, expiry FROM %s.%s WHERE key LIKE $1 ORDER BY key DESC LIMIT $2 OFFSET $3;",
-		"write":      "INSERT INTO %s.%s(key, value, metadata, expiry) VALUES ($1, $2::bytea, $3, $4) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry;",
-		"delete":     "DELETE FROM %s.%s WHERE key = $1;",
+		"list":       "SELECT key FROM %s.%s WHERE key LIKE $1 AND key LIKE $2 AND (expiry IS NULL OR expiry > now()) ORDER BY key OFFSET $3;",
+		"listLimit":  "SELECT key FROM %s.%s WHERE key LIKE $1 AND key LIKE $2 AND (expiry IS NULL OR expiry > now()) ORDER BY key LIMIT $3 OFFSET $4;",
+		"read":       "SELECT key, value, metadata, expiry, version FROM %s.%s WHERE key = $1;",
+		"readMany":   "SELECT key, value, metadata, expiry, version FROM %s.%s WHERE key LIKE $1 AND key LIKE $2 AND (expiry IS NULL OR expiry > now()) ORDER BY key OFFSET $3;",
+		"readOffset": "SELECT key, value, metadata, expiry, version FROM %s.%s WHERE key LIKE $1 AND key LIKE $2 AND (expiry IS NULL OR expiry > now()) ORDER BY key LIMIT $3 OFFSET $4;",
+		// the version of a record which has expired starts again as if it was deleted
+		"write": "INSERT INTO %[1]s.%[2]s(key, value, metadata, expiry, version) VALUES ($1, $2::bytea, $3, $4, 1) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry, version = CASE WHEN %[2]s.expiry IS NOT NULL AND %[2]s.expiry < now() THEN 1 ELSE %[2]s.version + 1 END;",
+		// only overwrites a record which has expired
+		"writeIfAbsent": "INSERT INTO %[1]s.%[2]s(key, value, metadata, expiry, version) VALUES ($1, $2::bytea, $3, $4, 1) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry, version = 1 WHERE %[2]s.expiry IS NOT NULL AND %[2]s.expiry < now();",
+		"writeVersion":  "UPDATE %s.%s SET value = $2::bytea, metadata = $3, expiry = $4, version = version + 1 WHERE key = $1 AND version = $5 AND (expiry IS NULL OR expiry > now());",
+		"delete":        "DELETE FROM %s.%s WHERE key = $1;",
+		"version":       "SELECT version, expiry FROM %s.%s WHERE key = $1 FOR UPDATE;",
 	}
 )
 
@@ -110,12 +120,19 @@ func (s *sqlStore) initDB(database, table string) error {
 		value bytea,
 		metadata JSONB,
 		expiry timestamp with time zone,
+		version INT NOT NULL DEFAULT 1,
 		CONSTRAINT %s_pkey PRIMARY KEY (key)
 	);`, table, table))
 	if err != nil {
 		return errors.Wrap(err, "Couldn't create table")
 	}
 
+	// Add the version to tables created before it existed
+	_, err = s.db.Exec(fmt.Sprintf(`ALTER TABLE %s.%s ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;`, database, table))
+	if err != nil {
+		return errors.Wrap(err, "Couldn't add version column")
+	}
+
 	// Create Index
 	_, err = s.db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%s" ON %s.%s USING btree ("key");`, "key_index_"+table, database, table))
 	if err != nil {
@@ -214,13 +231,20 @@ func (s *sqlStore) List(opts ...store.ListOption) ([]string, error) {
 		return nil, err
 	}
 
-	st, err := s.prepare(options.Database, options.Table, "list")
+	prefix, suffix := patterns(options.Prefix, options.Suffix)
+
+	query, args := "list", []interface{}{prefix, suffix, options.Offset}
+	if options.Limit != 0 {
+		query, args = "listLimit", []interface{}{prefix, suffix, options.Limit, options.Offset}
+	}
+
+	st, err := s.prepare(options.Database, options.Table, query)
 	if err != nil {
 		return nil, err
 	}
 	defer st.Close()
 
-	rows, err := st.Query()
+	rows, err := st.Query(args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -230,31 +254,13 @@ func (s *sqlStore) List(opts ...store.ListOption) ([]string, error) {
 	defer rows.Close()
 
 	var keys []string
-	var timehelper pq.NullTime
 
 	for rows.Next() {
-		record := &store.Record{}
-		metadata := make(Metadata)
-
-		if err := rows.Scan(&record.Key, &record.Value, &metadata, &timehelper); err != nil {
+		var key string
+		if err := rows.Scan(&key); err != nil {
 			return keys, err
 		}
-
-		// set the metadata
-		record.Metadata = toMetadata(&metadata)
-
-		if timehelper.Valid {
-			if timehelper.Time.Before(time.Now()) {
-				// record has expired
-				go s.Delete(record.Key)
-			} else {
-				record.Expiry = time.Until(timehelper.Time)
-				keys = append(keys, record.Key)
-			}
-		} else {
-			keys = append(keys, record.Key)
-		}
-
+		keys = append(keys, key)
 	}
 	rowErr := rows.Close()
 	if rowErr != nil {
@@ -267,6 +273,11 @@ func (s *sqlStore) List(opts ...store.ListOption) ([]string, error) {
 	return keys, nil
 }
 
+// patterns returns the LIKE patterns matching the prefix and suffix
+func patterns(prefix, suffix string) (string, string) {
+	return likeEscaper.Replace(prefix) + "%", "%" + likeEscaper.Replace(suffix)
+}
+
 // Read a single key
 func (s *sqlStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
 	var options store.ReadOptions
@@ -279,6 +290,13 @@ func (s *sqlStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 		return nil, err
 	}
 
+	if len(options.Filters) > 0 || len(options.Order) > 0 {
+		if err := store.CheckIndexes(s.options.Indexes, options); err != nil {
+			return nil, err
+		}
+		return s.query(key, options)
+	}
+
 	if options.Prefix || options.Suffix {
 		return s.read(key, options)
 	}
@@ -296,7 +314,7 @@ func (s *sqlStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 	record := &store.Record{}
 	metadata := make(Metadata)
 
-	if err := row.Scan(&record.Key, &record.Value, &metadata, &timehelper); err != nil {
+	if err := row.Scan(&record.Key, &record.Value, &metadata, &timehelper, &record.Version); err != nil {
 		if err == sql.ErrNoRows {
 			return records, store.ErrNotFound
 		}
@@ -309,7 +327,7 @@ func (s *sqlStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 	if timehelper.Valid {
 		if timehelper.Time.Before(time.Now()) {
 			// record has expired
-			go s.Delete(key)
+			go s.Delete(key, store.DeleteFrom(options.Database, options.Table))
 			return records, store.ErrNotFound
 		}
 		record.Expiry = time.Until(timehelper.Time)
@@ -323,34 +341,27 @@ func (s *sqlStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 
 // Read Many records
 func (s *sqlStore) read(key string, options store.ReadOptions) ([]*store.Record, error) {
-	pattern := "%"
+	var prefix, suffix string
 	if options.Prefix {
-		pattern = key + pattern
+		prefix = key
 	}
 	if options.Suffix {
-		pattern = pattern + key
+		suffix = key
 	}
+	prefix, suffix = patterns(prefix, suffix)
 
-	var rows *sql.Rows
-	var err error
-
+	query, args := "readMany", []interface{}{prefix, suffix, options.Offset}
 	if options.Limit != 0 {
-		st, err := s.prepare(options.Database, options.Table, "readOffset")
-		if err != nil {
-			return nil, err
-		}
-		defer st.Close()
-
-		rows, err = st.Query(pattern, options.Limit, options.Offset)
-	} else {
-		st, err := s.prepare(options.Database, options.Table, "readMany")
-		if err != nil {
-			return nil, err
-		}
-		defer st.Close()
+		query, args = "readOffset", []interface{}{prefix, suffix, options.Limit, options.Offset}
+	}
 
-		rows, err = st.Query(pattern)
+	st, err := s.prepare(options.Database, options.Table, query)
+	if err != nil {
+		return nil, err
 	}
+	defer st.Close()
+
+	rows, err := st.Query(args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return []*store.Record{}, nil
@@ -358,6 +369,11 @@ func (s *sqlStore) read(key string, options store.ReadOptions) ([]*store.Record,
 		return []*store.Record{}, errors.Wrap(err, "sqlStore.read failed")
 	}
 
+	return s.scan(rows, options)
+}
+
+// scan the records from the rows, deleting those which have expired
+func (s *sqlStore) scan(rows *sql.Rows, options store.ReadOptions) ([]*store.Record, error) {
 	defer rows.Close()
 
 	var records []*store.Record
@@ -367,7 +383,7 @@ func (s *sqlStore) read(key string, options store.ReadOptions) ([]*store.Record,
 		record := &store.Record{}
 		metadata := make(Metadata)
 
-		if err := rows.Scan(&record.Key, &record.Value, &metadata, &timehelper); err != nil {
+		if err := rows.Scan(&record.Key, &record.Value, &metadata, &timehelper, &record.Version); err != nil {
 			return records, err
 		}
 
@@ -377,7 +393,7 @@ func (s *sqlStore) read(key string, options store.ReadOptions) ([]*store.Record,
 		if timehelper.Valid {
 			if timehelper.Time.Before(time.Now()) {
 				// record has expired
-				go s.Delete(record.Key)
+				go s.Delete(record.Key, store.DeleteFrom(options.Database, options.Table))
 			} else {
 				record.Expiry = time.Until(timehelper.Time)
 				records = append(records, record)
@@ -410,7 +426,15 @@ func (s *sqlStore) Write(r *store.Record, opts ...store.WriteOption) error {
 		return err
 	}
 
-	st, err := s.prepare(options.Database, options.Table, "write")
+	query := "write"
+	switch {
+	case options.IfAbsent:
+		query = "writeIfAbsent"
+	case options.Version > 0:
+		query = "writeVersion"
+	}
+
+	st, err := s.prepare(options.Database, options.Table, query)
 	if err != nil {
 		return err
 	}
@@ -421,16 +445,30 @@ func (s *sqlStore) Write(r *store.Record, opts ...store.WriteOption) error {
 		metadata[k] = v
 	}
 
+	args := []interface{}{r.Key, r.Value, metadata, nil}
 	if r.Expiry != 0 {
-		_, err = st.Exec(r.Key, r.Value, metadata, time.Now().Add(r.Expiry))
-	} else {
-		_, err = st.Exec(r.Key, r.Value, metadata, nil)
+		args[3] = time.Now().Add(r.Expiry)
+	}
+	if query == "writeVersion" {
+		args = append(args, options.Version)
 	}
 
+	result, err := st.Exec(args...)
 	if err != nil {
 		return errors.Wrap(err, "Couldn't insert record "+r.Key)
 	}
 
+	// a conditional write which didn't match any row
+	if query != "write" {
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return store.ErrConflict
+		}
+	}
+
 	return nil
 }
 
@@ -465,6 +503,131 @@ func (s *sqlStore) Delete(key string, opts ...store.DeleteOption) error {
 	return nil
 }
 
+// Batch applies the batch in a transaction
+func (s *sqlStore) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	var options store.BatchOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// create the db if not exists
+	if err := s.createDB(options.Database, options.Table); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	// a no-op once committed
+	defer tx.Rollback()
+
+	if len(b.Reads) > 0 {
+		st, err := s.prepare(options.Database, options.Table, "version")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		for key, want := range b.Reads {
+			var version uint64
+			var timehelper pq.NullTime
+
+			err := tx.Stmt(st).QueryRow(key).Scan(&version, &timehelper)
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			// an expired record doesn't exist
+			if timehelper.Valid && timehelper.Time.Before(time.Now()) {
+				version = 0
+			}
+			if version != want {
+				return store.ErrConflict
+			}
+		}
+	}
+
+	if len(b.Writes) > 0 {
+		st, err := s.prepare(options.Database, options.Table, "write")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		for _, r := range b.Writes {
+			metadata := make(Metadata)
+			for k, v := range r.Metadata {
+				metadata[k] = v
+			}
+
+			var expiry interface{}
+			if r.Expiry != 0 {
+				expiry = time.Now().Add(r.Expiry)
+			}
+
+			if _, err := tx.Stmt(st).Exec(r.Key, r.Value, metadata, expiry); err != nil {
+				return errors.Wrap(err, "Couldn't insert record "+r.Key)
+			}
+		}
+	}
+
+	if len(b.Deletes) > 0 {
+		st, err := s.prepare(options.Database, options.Table, "delete")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		for _, key := range b.Deletes {
+			if _, err := tx.Stmt(st).Exec(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Watch the changes to a table using a changefeed, which requires
+// the kv.rangefeed.enabled cluster setting
+func (s *sqlStore) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	var options store.WatchOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// create the db if not exists
+	if err := s.createDB(options.Database, options.Table); err != nil {
+		return nil, err
+	}
+
+	database, table := s.getDB(options.Database, options.Table)
+
+	// only emit the changes from now on
+	var cursor string
+	if err := s.db.QueryRow("SELECT cluster_logical_timestamp()::STRING;").Scan(&cursor); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("EXPERIMENTAL CHANGEFEED FOR TABLE %s.%s WITH cursor = '%s';", database, table, cursor))
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "Couldn't create changefeed")
+	}
+
+	return &watcher{
+		store:    s,
+		database: options.Database,
+		table:    options.Table,
+		wo:       options,
+		ctx:      ctx,
+		cancel:   cancel,
+		rows:     rows,
+	}, nil
+}
+
 func (s *sqlStore) Options() store.Options {
 	return s.options
 }
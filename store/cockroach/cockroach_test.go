@@ -7,11 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kr/pretty"
 	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/test"
 )
 
-func TestSQL(t *testing.T) {
+// connection returns the connection string of the test database or skips the test
+func connection(t *testing.T) string {
 	if len(os.Getenv("IN_TRAVIS_CI")) != 0 {
 		t.Skip()
 	}
@@ -32,6 +35,37 @@ func TestSQL(t *testing.T) {
 	}
 	db.Close()
 
+	return connection
+}
+
+func TestSQLConformance(t *testing.T) {
+	connection := connection(t)
+
+	test.Run(t, func() store.Store {
+		return NewStore(
+			store.Database("conformance"),
+			store.Table("conformance-"+uuid.New().String()),
+			store.Nodes(connection),
+		)
+	})
+}
+
+func TestSQLQuery(t *testing.T) {
+	connection := connection(t)
+
+	test.RunQuery(t, func(indexes ...string) store.Store {
+		return NewStore(
+			store.Database("query"),
+			store.Table("query-"+uuid.New().String()),
+			store.Nodes(connection),
+			store.Indexes(indexes...),
+		)
+	})
+}
+
+func TestSQL(t *testing.T) {
+	connection := connection(t)
+
 	sqlStore := NewStore(
 		store.Database("testsql"),
 		store.Nodes(connection),
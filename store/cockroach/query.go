@@ -0,0 +1,123 @@
+package cockroach
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/micro/go-micro/v2/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	// the JSONB types of the metadata values which can be compared by rank
+	jsonTypes = map[int]string{1: "string", 2: "number", 3: "boolean"}
+	// the SQL types the JSONB values of each rank are compared as
+	sqlTypes = map[int]string{1: "STRING", 2: "FLOAT", 3: "BOOL"}
+)
+
+// query builds the statement filtering and ordering the records by metadata.
+// Equality filters use the inverted index on the metadata, the range filters
+// and the order are evaluated on the records which match the key.
+func (s *sqlStore) query(key string, options store.ReadOptions) ([]*store.Record, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// a key without wildcards only matches itself
+	prefix, suffix := likeEscaper.Replace(key), "%"
+	if options.Prefix || options.Suffix {
+		prefix, suffix = "%", "%"
+		if options.Prefix {
+			prefix = likeEscaper.Replace(key) + "%"
+		}
+		if options.Suffix {
+			suffix = "%" + likeEscaper.Replace(key)
+		}
+	}
+
+	where := []string{
+		"key LIKE " + arg(prefix),
+		"key LIKE " + arg(suffix),
+		"(expiry IS NULL OR expiry > now())",
+	}
+
+	// the value of the field and the value of the field as a type, which is NULL for other types
+	field := func(name string) string {
+		return "metadata->" + arg(name) + "::STRING"
+	}
+	typed := func(value string, typ int) string {
+		return fmt.Sprintf("(CASE WHEN jsonb_typeof(%s) = '%s' THEN (%s)::%s END)",
+			value, jsonTypes[typ], strings.Replace(value, "->", "->>", 1), sqlTypes[typ])
+	}
+
+	for _, f := range options.Filters {
+		typ := store.ValueType(f.Value)
+		if typ == 0 {
+			// values of other types never match
+			where = append(where, "FALSE")
+			continue
+		}
+
+		if f.Op == store.Equal {
+			b, err := json.Marshal(map[string]interface{}{f.Field: f.Value})
+			if err != nil {
+				return nil, err
+			}
+			where = append(where, "metadata @> "+arg(string(b))+"::JSONB")
+			continue
+		}
+
+		value := f.Value
+		if typ == 2 {
+			value, _ = store.Number(f.Value)
+		}
+		where = append(where, fmt.Sprintf("%s %s %s::%s", typed(field(f.Field), typ), f.Op, arg(value), sqlTypes[typ]))
+	}
+
+	var order []string
+	if len(options.Order) > 0 {
+		value := field(options.Order)
+		dir := "ASC"
+		if options.Descending {
+			dir = "DESC"
+		}
+		rank := fmt.Sprintf("(CASE jsonb_typeof(%s) WHEN 'string' THEN 1 WHEN 'number' THEN 2 WHEN 'boolean' THEN 3 ELSE 0 END)", value)
+
+		// records without a value of those types are last in either direction
+		order = append(order, rank+" = 0 ASC", rank+" "+dir)
+		for typ := 1; typ <= 3; typ++ {
+			order = append(order, typed(value, typ)+" "+dir)
+		}
+	}
+	order = append(order, "key ASC")
+
+	database, table := s.getDB(options.Database, options.Table)
+	q := fmt.Sprintf("SELECT key, value, metadata, expiry, version FROM %s.%s WHERE %s ORDER BY %s",
+		database, table, strings.Join(where, " AND "), strings.Join(order, ", "))
+	if options.Limit != 0 {
+		q += " LIMIT " + arg(options.Limit)
+	}
+	q += " OFFSET " + arg(options.Offset) + ";"
+
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return []*store.Record{}, nil
+		}
+		return []*store.Record{}, errors.Wrap(err, "sqlStore.query failed")
+	}
+
+	records, err := s.scan(rows, options)
+	if err != nil {
+		return records, err
+	}
+	if len(records) == 0 && !options.Prefix && !options.Suffix {
+		return records, store.ErrNotFound
+	}
+
+	return records, nil
+}
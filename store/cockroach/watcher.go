@@ -0,0 +1,112 @@
+package cockroach
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v2/store"
+)
+
+// change is the value of a row emitted by a changefeed
+type change struct {
+	After *struct {
+		Key     string `json:"key"`
+		Version uint64 `json:"version"`
+	} `json:"after"`
+}
+
+type watcher struct {
+	store    *sqlStore
+	database string
+	table    string
+	wo       store.WatchOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	rows   *sql.Rows
+}
+
+func (w *watcher) Next() (*store.Event, error) {
+	for w.rows.Next() {
+		var table sql.NullString
+		var key, value []byte
+
+		if err := w.rows.Scan(&table, &key, &value); err != nil {
+			return nil, err
+		}
+
+		// resolved timestamps have no key
+		if len(key) == 0 {
+			continue
+		}
+
+		ev, err := w.event(key, value)
+		if err != nil {
+			return nil, err
+		}
+		if ev == nil {
+			continue
+		}
+		return ev, nil
+	}
+
+	if w.ctx.Err() != nil {
+		return nil, store.ErrWatcherStopped
+	}
+	if err := w.rows.Err(); err != nil {
+		return nil, err
+	}
+	return nil, store.ErrWatcherStopped
+}
+
+// event returns the event for a change or nil if it isn't watched
+func (w *watcher) event(key, value []byte) (*store.Event, error) {
+	// the key is the primary key of the row as a json array
+	var pk []string
+	if err := json.Unmarshal(key, &pk); err != nil {
+		return nil, err
+	}
+	if len(pk) == 0 || !strings.HasPrefix(pk[0], w.wo.Prefix) {
+		return nil, nil
+	}
+
+	var c change
+	if err := json.Unmarshal(value, &c); err != nil {
+		return nil, err
+	}
+
+	if c.After == nil {
+		return &store.Event{
+			Type:      store.Delete,
+			Record:    &store.Record{Key: pk[0]},
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	// read the record rather than decode the columns of the row,
+	// if it has since been deleted the delete is still to come
+	recs, err := w.store.Read(pk[0], store.ReadFrom(w.database, w.table))
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ev := &store.Event{
+		Type:      store.Update,
+		Record:    recs[0],
+		Timestamp: time.Now(),
+	}
+	if c.After.Version == 1 {
+		ev.Type = store.Create
+	}
+
+	return ev, nil
+}
+
+func (w *watcher) Stop() {
+	w.cancel()
+}
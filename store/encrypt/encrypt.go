@@ -0,0 +1,272 @@
+// Package encrypt implements a store which encrypts the records at rest in another store
+package encrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/micro/go-micro/v2/config/secrets"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// KeyMetadata is the metadata field holding the id of the key a record is encrypted with
+	KeyMetadata = "Micro-Encryption-Key"
+	// EncryptedMetadata is the metadata field holding the encrypted metadata of a record
+	EncryptedMetadata = "Micro-Encrypted-Metadata"
+)
+
+var (
+	// ErrKeyNotFound is returned when a record is encrypted with an unknown key
+	ErrKeyNotFound = errors.New("encryption key not found")
+)
+
+// Store encrypts the values, and optionally the metadata, of the records
+// written to the underlying store and decrypts them on the way out.
+// Records which aren't encrypted are read as they are.
+type Store interface {
+	// Implements the store interface
+	store.Store
+	// Reencrypt the records which aren't encrypted with the current key,
+	// returning the number of records which were re-encrypted
+	Reencrypt(opts ...store.ListOption) (int, error)
+}
+
+type encryptStore struct {
+	store   store.Store
+	options Options
+}
+
+// NewStore returns a store which encrypts the records of the underlying store
+func NewStore(s store.Store, opts ...Option) Store {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &encryptStore{
+		store:   s,
+		options: options,
+	}
+}
+
+// encryptOpts are passed to the secrets so asymmetric ones encrypt to themselves
+func encryptOpts(s secrets.Secrets) []secrets.EncryptOption {
+	return []secrets.EncryptOption{secrets.RecipientPublicKey(s.Options().PublicKey)}
+}
+
+func decryptOpts(s secrets.Secrets) []secrets.DecryptOption {
+	return []secrets.DecryptOption{secrets.SenderPublicKey(s.Options().PublicKey)}
+}
+
+// encrypt returns a copy of the record encrypted with the current key
+func (e *encryptStore) encrypt(r *store.Record) (*store.Record, error) {
+	s, ok := e.options.Keys[e.options.Current]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	value, err := s.Encrypt(r.Value, encryptOpts(s)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypt value")
+	}
+
+	rec := &store.Record{
+		Key:      r.Key,
+		Value:    value,
+		Metadata: make(map[string]interface{}),
+		Expiry:   r.Expiry,
+	}
+
+	if e.options.Metadata {
+		b, err := json.Marshal(r.Metadata)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal metadata")
+		}
+		md, err := s.Encrypt(b, encryptOpts(s)...)
+		if err != nil {
+			return nil, errors.Wrap(err, "encrypt metadata")
+		}
+		rec.Metadata[EncryptedMetadata] = base64.StdEncoding.EncodeToString(md)
+	} else {
+		for k, v := range r.Metadata {
+			rec.Metadata[k] = v
+		}
+	}
+
+	rec.Metadata[KeyMetadata] = e.options.Current
+
+	return rec, nil
+}
+
+// decrypt the record in place, returning the id of the key it was encrypted with
+func (e *encryptStore) decrypt(r *store.Record) (string, error) {
+	id, ok := r.Metadata[KeyMetadata].(string)
+	if !ok {
+		// the record isn't encrypted
+		return "", nil
+	}
+
+	s, ok := e.options.Keys[id]
+	if !ok {
+		return id, ErrKeyNotFound
+	}
+
+	// the nonce is prepended to the encrypted value
+	if len(r.Value) < 24 {
+		return id, errors.Errorf("decrypt value of %s: too short", r.Key)
+	}
+	value, err := s.Decrypt(r.Value, decryptOpts(s)...)
+	if err != nil {
+		return id, errors.Wrapf(err, "decrypt value of %s", r.Key)
+	}
+	r.Value = value
+
+	delete(r.Metadata, KeyMetadata)
+
+	enc, ok := r.Metadata[EncryptedMetadata].(string)
+	if !ok {
+		return id, nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil || len(b) < 24 {
+		return id, errors.Errorf("decrypt metadata of %s: invalid", r.Key)
+	}
+	b, err = s.Decrypt(b, decryptOpts(s)...)
+	if err != nil {
+		return id, errors.Wrapf(err, "decrypt metadata of %s", r.Key)
+	}
+
+	md := make(map[string]interface{})
+	if err := json.Unmarshal(b, &md); err != nil {
+		return id, errors.Wrapf(err, "unmarshal metadata of %s", r.Key)
+	}
+	r.Metadata = md
+
+	return id, nil
+}
+
+func (e *encryptStore) Init(opts ...store.Option) error {
+	return e.store.Init(opts...)
+}
+
+func (e *encryptStore) Options() store.Options {
+	return e.store.Options()
+}
+
+func (e *encryptStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	records, err := e.store.Read(key, opts...)
+	if err != nil {
+		return records, err
+	}
+
+	for _, r := range records {
+		if _, err := e.decrypt(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+func (e *encryptStore) Write(r *store.Record, opts ...store.WriteOption) error {
+	rec, err := e.encrypt(r)
+	if err != nil {
+		return err
+	}
+	return e.store.Write(rec, opts...)
+}
+
+func (e *encryptStore) Delete(key string, opts ...store.DeleteOption) error {
+	return e.store.Delete(key, opts...)
+}
+
+func (e *encryptStore) List(opts ...store.ListOption) ([]string, error) {
+	return e.store.List(opts...)
+}
+
+func (e *encryptStore) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	eb := &store.Batch{
+		Reads:   b.Reads,
+		Deletes: b.Deletes,
+	}
+
+	for _, r := range b.Writes {
+		rec, err := e.encrypt(r)
+		if err != nil {
+			return err
+		}
+		eb.Writes = append(eb.Writes, rec)
+	}
+
+	return e.store.Batch(eb, opts...)
+}
+
+func (e *encryptStore) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	w, err := e.store.Watch(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &watcher{w: w, e: e}, nil
+}
+
+func (e *encryptStore) Reencrypt(opts ...store.ListOption) (int, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	keys, err := e.store.List(opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	for _, key := range keys {
+		records, err := e.store.Read(key, store.ReadFrom(options.Database, options.Table))
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return count, err
+		}
+
+		r := records[0]
+		id, err := e.decrypt(r)
+		if err != nil {
+			return count, err
+		}
+		if id == e.options.Current {
+			continue
+		}
+
+		rec, err := e.encrypt(r)
+		if err != nil {
+			return count, err
+		}
+
+		// skip records which have changed since they were read,
+		// they were written with the current key
+		err = e.store.Write(rec, store.WriteTo(options.Database, options.Table), store.WriteVersion(r.Version))
+		if err == store.ErrConflict {
+			continue
+		} else if err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+func (e *encryptStore) Close() error {
+	return e.store.Close()
+}
+
+func (e *encryptStore) String() string {
+	return fmt.Sprintf("encrypt %s", e.store.String())
+}
@@ -0,0 +1,207 @@
+package encrypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/micro/go-micro/v2/config/secrets"
+	"github.com/micro/go-micro/v2/config/secrets/box"
+	"github.com/micro/go-micro/v2/config/secrets/secretbox"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/cache"
+	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/micro/go-micro/v2/store/test"
+	naclbox "golang.org/x/crypto/nacl/box"
+)
+
+func newSecretbox(t *testing.T) secrets.Secrets {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	s := secretbox.NewSecrets(secrets.Key(key))
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestEncryptConformance(t *testing.T) {
+	key := newSecretbox(t)
+
+	test.Run(t, func() store.Store {
+		return NewStore(memory.NewStore(), Key("1", key))
+	})
+}
+
+func TestEncryptMetadataConformance(t *testing.T) {
+	key := newSecretbox(t)
+
+	test.Run(t, func() store.Store {
+		return NewStore(memory.NewStore(), Key("1", key), EncryptMetadata())
+	})
+}
+
+func TestEncryptCache(t *testing.T) {
+	key := newSecretbox(t)
+	l0, l1 := memory.NewStore(), memory.NewStore()
+
+	stores := []store.Store{
+		// only the last store is encrypted
+		cache.NewCache(l0, NewStore(l1, Key("1", key))),
+		// all the stores are encrypted
+		NewStore(cache.NewCache(l0, l1), Key("1", key)),
+	}
+
+	for _, s := range stores {
+		if err := s.Write(&store.Record{Key: "foo", Value: []byte("secret")}); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := l1.Read("foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(r[0].Value, []byte("secret")) {
+			t.Fatalf("Expected the value to be encrypted, got %s", r[0].Value)
+		}
+
+		r, err = s.Read("foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(r[0].Value) != "secret" {
+			t.Fatalf("Expected secret, got %s", r[0].Value)
+		}
+
+		s.Close()
+	}
+}
+
+func TestEncryptAtRest(t *testing.T) {
+	m := memory.NewStore()
+	s := NewStore(m, Key("1", newSecretbox(t)), EncryptMetadata())
+
+	if err := s.Write(&store.Record{
+		Key:      "foo",
+		Value:    []byte("secret"),
+		Metadata: map[string]interface{}{"email": "foo@example.com"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the underlying store only has the encrypted record
+	r, err := m.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(r[0].Value, []byte("secret")) {
+		t.Fatalf("Expected the value to be encrypted, got %s", r[0].Value)
+	}
+	if r[0].Metadata[KeyMetadata] != "1" {
+		t.Fatalf("Expected key 1, got %v", r[0].Metadata[KeyMetadata])
+	}
+	if _, ok := r[0].Metadata["email"]; ok {
+		t.Fatalf("Expected the metadata to be encrypted, got %v", r[0].Metadata)
+	}
+
+	r, err = s.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(r[0].Value) != "secret" || r[0].Metadata["email"] != "foo@example.com" {
+		t.Fatalf("Expected the decrypted record, got %s %v", r[0].Value, r[0].Metadata)
+	}
+	if _, ok := r[0].Metadata[KeyMetadata]; ok {
+		t.Fatalf("Expected the key not to be in the metadata, got %v", r[0].Metadata)
+	}
+}
+
+func TestEncryptBox(t *testing.T) {
+	pub, priv, err := naclbox.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := box.NewSecrets(secrets.PublicKey(pub[:]), secrets.PrivateKey(priv[:]))
+	if err := b.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(memory.NewStore(), Key("box", b))
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("secret")}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(r[0].Value) != "secret" {
+		t.Fatalf("Expected secret, got %s", r[0].Value)
+	}
+}
+
+func TestEncryptRotation(t *testing.T) {
+	m := memory.NewStore()
+	k1, k2 := newSecretbox(t), newSecretbox(t)
+
+	// records written before encryption and with the old key
+	m.Write(&store.Record{Key: "plain", Value: []byte("plain")})
+	old := NewStore(m, Key("1", k1))
+	old.Write(&store.Record{Key: "old", Value: []byte("old")})
+
+	s := NewStore(m, Key("2", k2), Key("1", k1))
+	s.Write(&store.Record{Key: "new", Value: []byte("new")})
+
+	// all of the records can be read
+	for _, key := range []string{"plain", "old", "new"} {
+		r, err := s.Read(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(r[0].Value) != key {
+			t.Fatalf("Expected %s, got %s", key, r[0].Value)
+		}
+	}
+
+	// the new record can't be read with the old key
+	if _, err := old.Read("new"); err != ErrKeyNotFound {
+		t.Fatalf("Expected %v, got %v", ErrKeyNotFound, err)
+	}
+
+	n, err := s.Reencrypt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("Expected 2 records to be re-encrypted, got %d", n)
+	}
+
+	// the records are all encrypted with the current key
+	for _, key := range []string{"plain", "old", "new"} {
+		r, err := m.Read(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r[0].Metadata[KeyMetadata] != "2" {
+			t.Fatalf("Expected %s to be encrypted with key 2, got %v", key, r[0].Metadata[KeyMetadata])
+		}
+	}
+
+	// so the old key is no longer needed
+	s = NewStore(m, Key("2", k2))
+	for _, key := range []string{"plain", "old", "new"} {
+		r, err := s.Read(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(r[0].Value) != key {
+			t.Fatalf("Expected %s, got %s", key, r[0].Value)
+		}
+	}
+
+	if n, err := s.Reencrypt(); err != nil || n != 0 {
+		t.Fatalf("Expected nothing to re-encrypt, got %d %v", n, err)
+	}
+}
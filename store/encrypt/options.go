@@ -0,0 +1,48 @@
+package encrypt
+
+import (
+	"github.com/micro/go-micro/v2/config/secrets"
+)
+
+// Options of the encrypted store
+type Options struct {
+	// Keys to decrypt the records with by id
+	Keys map[string]secrets.Secrets
+	// Current is the id of the key records are encrypted with
+	Current string
+	// Metadata is encrypted as well as the value of the records
+	Metadata bool
+}
+
+// Option sets options
+type Option func(o *Options)
+
+// Key adds a key to decrypt records with, which must be already Init()ialised.
+// The first key added is the current key unless Current is set.
+func Key(id string, s secrets.Secrets) Option {
+	return func(o *Options) {
+		if o.Keys == nil {
+			o.Keys = make(map[string]secrets.Secrets)
+		}
+		if len(o.Current) == 0 {
+			o.Current = id
+		}
+		o.Keys[id] = s
+	}
+}
+
+// Current sets the id of the key to encrypt records with. Records encrypted
+// with other keys can still be read until they are re-encrypted.
+func Current(id string) Option {
+	return func(o *Options) {
+		o.Current = id
+	}
+}
+
+// EncryptMetadata encrypts the metadata of the records as well as their value.
+// The metadata can't be queried by the underlying store once encrypted.
+func EncryptMetadata() Option {
+	return func(o *Options) {
+		o.Metadata = true
+	}
+}
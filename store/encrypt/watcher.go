@@ -0,0 +1,33 @@
+package encrypt
+
+import (
+	"github.com/micro/go-micro/v2/store"
+)
+
+// watcher decrypts the records of the events of the underlying store
+type watcher struct {
+	w store.Watcher
+	e *encryptStore
+}
+
+func (w *watcher) Next() (*store.Event, error) {
+	ev, err := w.w.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	// only the key of a deleted record is known
+	if ev.Type == store.Delete || ev.Record == nil {
+		return ev, nil
+	}
+
+	if _, err := w.e.decrypt(ev.Record); err != nil {
+		return nil, err
+	}
+
+	return ev, nil
+}
+
+func (w *watcher) Stop() {
+	w.w.Stop()
+}
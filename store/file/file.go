@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/micro/go-micro/v2/store"
 	bolt "go.etcd.io/bbolt"
 )
@@ -30,7 +31,8 @@ var (
 // NewStore returns a memory store
 func NewStore(opts ...store.Option) store.Store {
 	s := &fileStore{
-		handles: make(map[string]*fileHandle),
+		handles:  make(map[string]*fileHandle),
+		watchers: make(map[string]*watcher),
 	}
 	s.init(opts...)
 	return s
@@ -43,11 +45,17 @@ type fileStore struct {
 	// the database handle
 	sync.RWMutex
 	handles map[string]*fileHandle
+
+	wmtx     sync.RWMutex
+	watchers map[string]*watcher
 }
 
 type fileHandle struct {
 	key string
 	db  *bolt.DB
+
+	// serialises writes so events are sent in order
+	sync.Mutex
 }
 
 // record stored by us
@@ -56,6 +64,7 @@ type record struct {
 	Value     []byte
 	Metadata  map[string]interface{}
 	ExpiresAt time.Time
+	Version   uint64
 }
 
 func key(database, table string) string {
@@ -63,13 +72,33 @@ func key(database, table string) string {
 }
 
 func (m *fileStore) delete(fd *fileHandle, key string) error {
-	return fd.db.Update(func(tx *bolt.Tx) error {
+	fd.Lock()
+	defer fd.Unlock()
+
+	var exists bool
+
+	err := fd.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(dataBucket))
 		if b == nil {
 			return nil
 		}
-		return b.Delete([]byte(key))
+		var err error
+		exists, err = remove(tx, b, m.options.Indexes, key)
+		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		m.sendEvent(fd, &store.Event{
+			Type:      store.Delete,
+			Record:    &store.Record{Key: key},
+			Timestamp: time.Now(),
+		})
+	}
+
+	return nil
 }
 
 func (m *fileStore) init(opts ...store.Option) error {
@@ -140,13 +169,20 @@ func (f *fileStore) getDB(database, table string) (*fileHandle, error) {
 		key: k,
 		db:  db,
 	}
+
+	if err := syncIndexes(fd, f.options.Indexes); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	f.handles[k] = fd
 
 	return fd, nil
 }
 
-func (m *fileStore) list(fd *fileHandle, limit, offset uint) []string {
-	var allItems []string
+// list the keys of the table matching the options, ordered by key
+func (m *fileStore) list(fd *fileHandle, opts store.ListOptions) []string {
+	var keys []string
 
 	fd.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(dataBucket))
@@ -155,8 +191,14 @@ func (m *fileStore) list(fd *fileHandle, limit, offset uint) []string {
 			return nil
 		}
 
+		// bolt iterates over the keys in order
 		// @todo very inefficient
 		if err := b.ForEach(func(k, v []byte) error {
+			key := string(k)
+			if !strings.HasPrefix(key, opts.Prefix) || !strings.HasSuffix(key, opts.Suffix) {
+				return nil
+			}
+
 			storedRecord := &record{}
 
 			if err := json.Unmarshal(v, storedRecord); err != nil {
@@ -169,7 +211,7 @@ func (m *fileStore) list(fd *fileHandle, limit, offset uint) []string {
 				}
 			}
 
-			allItems = append(allItems, string(k))
+			keys = append(keys, key)
 
 			return nil
 		}); err != nil {
@@ -179,24 +221,16 @@ func (m *fileStore) list(fd *fileHandle, limit, offset uint) []string {
 		return nil
 	})
 
-	allKeys := make([]string, len(allItems))
-
-	for i, k := range allItems {
-		allKeys[i] = k
+	if opts.Offset >= uint(len(keys)) {
+		return []string{}
 	}
+	keys = keys[opts.Offset:]
 
-	if limit != 0 || offset != 0 {
-		sort.Slice(allKeys, func(i, j int) bool { return allKeys[i] < allKeys[j] })
-		min := func(i, j uint) uint {
-			if i < j {
-				return i
-			}
-			return j
-		}
-		return allKeys[offset:min(limit, uint(len(allKeys)))]
+	if opts.Limit > 0 && opts.Limit < uint(len(keys)) {
+		keys = keys[:opts.Limit]
 	}
 
-	return allKeys
+	return keys
 }
 
 func (m *fileStore) get(fd *fileHandle, k string) (*store.Record, error) {
@@ -239,10 +273,65 @@ func (m *fileStore) get(fd *fileHandle, k string) (*store.Record, error) {
 		newRecord.Expiry = time.Until(storedRecord.ExpiresAt)
 	}
 
+	newRecord.Version = storedRecord.Version
+
 	return newRecord, nil
 }
 
-func (m *fileStore) set(fd *fileHandle, r *store.Record) error {
+// decode the stored record, returning an empty record if it doesn't exist
+func decode(value []byte) (*record, error) {
+	storedRecord := &record{}
+	if value == nil {
+		return storedRecord, nil
+	}
+	if err := json.Unmarshal(value, storedRecord); err != nil {
+		return nil, err
+	}
+	return storedRecord, nil
+}
+
+// version returns the version of the stored record or 0 if it doesn't exist or has expired
+func (r *record) version() uint64 {
+	if !r.ExpiresAt.IsZero() && r.ExpiresAt.Before(time.Now()) {
+		return 0
+	}
+	return r.Version
+}
+
+// version returns the version of the stored record or 0 if it doesn't exist or has expired
+func version(value []byte) (uint64, error) {
+	storedRecord, err := decode(value)
+	if err != nil {
+		return 0, err
+	}
+	return storedRecord.version(), nil
+}
+
+func (m *fileStore) set(fd *fileHandle, r *store.Record, opts store.WriteOptions) error {
+	fd.Lock()
+	defer fd.Unlock()
+
+	var ev *store.Event
+
+	err := fd.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(dataBucket))
+		if err != nil {
+			return err
+		}
+		ev, err = put(tx, b, m.options.Indexes, r, opts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	m.sendEvent(fd, ev)
+	return nil
+}
+
+// put writes the record to the bucket if it meets the conditions of the options,
+// updates the indexes and returns the event for the change
+func put(tx *bolt.Tx, b *bolt.Bucket, indexes []string, r *store.Record, opts store.WriteOptions) (*store.Event, error) {
 	// copy the incoming record and then
 	// convert the expiry in to a hard timestamp
 	item := &record{}
@@ -258,20 +347,146 @@ func (m *fileStore) set(fd *fileHandle, r *store.Record) error {
 		item.Metadata[k] = v
 	}
 
+	// compare the version within the transaction
+	old, err := decode(b.Get([]byte(r.Key)))
+	if err != nil {
+		return nil, err
+	}
+	v := old.version()
+	if opts.Conflicts(v) {
+		return nil, store.ErrConflict
+	}
+	item.Version = v + 1
+
 	// marshal the data
 	data, _ := json.Marshal(item)
 
-	return fd.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(dataBucket))
-		if b == nil {
-			var err error
-			b, err = tx.CreateBucketIfNotExists([]byte(dataBucket))
+	if err := b.Put([]byte(r.Key), data); err != nil {
+		return nil, err
+	}
+
+	if err := updateIndexes(tx, indexes, r.Key, old.Metadata, item.Metadata); err != nil {
+		return nil, err
+	}
+
+	ev := &store.Event{
+		Type: store.Update,
+		Record: &store.Record{
+			Key:      item.Key,
+			Value:    item.Value,
+			Metadata: item.Metadata,
+			Expiry:   r.Expiry,
+			Version:  item.Version,
+		},
+		Timestamp: time.Now(),
+	}
+	if v == 0 {
+		ev.Type = store.Create
+	}
+
+	return ev, nil
+}
+
+// remove the record from the bucket and the indexes,
+// returning whether it existed and hadn't expired
+func remove(tx *bolt.Tx, b *bolt.Bucket, indexes []string, key string) (bool, error) {
+	old, err := decode(b.Get([]byte(key)))
+	if err != nil {
+		return false, err
+	}
+	if err := b.Delete([]byte(key)); err != nil {
+		return false, err
+	}
+	if err := updateIndexes(tx, indexes, key, old.Metadata, nil); err != nil {
+		return false, err
+	}
+	return old.version() > 0, nil
+}
+
+// batch applies the batch in a single transaction
+func (m *fileStore) batch(fd *fileHandle, batch *store.Batch) error {
+	fd.Lock()
+	defer fd.Unlock()
+
+	var events []*store.Event
+
+	err := fd.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(dataBucket))
+		if err != nil {
+			return err
+		}
+
+		for key, want := range batch.Reads {
+			v, err := version(b.Get([]byte(key)))
+			if err != nil {
+				return err
+			}
+			if v != want {
+				return store.ErrConflict
+			}
+		}
+
+		for _, r := range batch.Writes {
+			ev, err := put(tx, b, m.options.Indexes, r, store.WriteOptions{})
 			if err != nil {
 				return err
 			}
+			events = append(events, ev)
+		}
+
+		for _, key := range batch.Deletes {
+			exists, err := remove(tx, b, m.options.Indexes, key)
+			if err != nil {
+				return err
+			}
+			if exists {
+				events = append(events, &store.Event{
+					Type:      store.Delete,
+					Record:    &store.Record{Key: key},
+					Timestamp: time.Now(),
+				})
+			}
 		}
-		return b.Put([]byte(r.Key), data)
+
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	for _, ev := range events {
+		m.sendEvent(fd, ev)
+	}
+
+	return nil
+}
+
+// sendEvent to the watchers of the file
+func (m *fileStore) sendEvent(fd *fileHandle, ev *store.Event) {
+	m.wmtx.RLock()
+	watchers := make([]*watcher, 0, len(m.watchers))
+	for _, w := range m.watchers {
+		if w.key == fd.key && strings.HasPrefix(ev.Record.Key, w.wo.Prefix) {
+			watchers = append(watchers, w)
+		}
+	}
+	m.wmtx.RUnlock()
+
+	for _, w := range watchers {
+		select {
+		case <-w.exit:
+			continue
+		default:
+		}
+
+		select {
+		case w.res <- ev:
+		default:
+			// the watcher fell behind, so rather than dropping the event
+			// it's stopped and knows the records must be read again
+			w.stop(store.ErrWatcherOverflow)
+		}
+	}
 }
 
 func (f *fileStore) Close() error {
@@ -285,7 +500,19 @@ func (f *fileStore) Close() error {
 }
 
 func (f *fileStore) Init(opts ...store.Option) error {
-	return f.init(opts...)
+	if err := f.init(opts...); err != nil {
+		return err
+	}
+
+	// the indexes may have changed
+	f.RLock()
+	defer f.RUnlock()
+	for _, fd := range f.handles {
+		if err := syncIndexes(fd, f.options.Indexes); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *fileStore) Delete(key string, opts ...store.DeleteOption) error {
@@ -313,25 +540,49 @@ func (m *fileStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 		return nil, err
 	}
 
+	// filter and order by metadata once the records are read
+	query := len(readOpts.Filters) > 0 || len(readOpts.Order) > 0
+	if query {
+		if err := store.CheckIndexes(m.options.Indexes, readOpts); err != nil {
+			return nil, err
+		}
+	}
+
 	var keys []string
 
 	// Handle Prefix / suffix
 	// TODO: do range scan here rather than listing all keys
-	if readOpts.Prefix || readOpts.Suffix {
-		// list the keys
-		k := m.list(fd, readOpts.Limit, readOpts.Offset)
-
-		// check for prefix and suffix
-		for _, v := range k {
-			if readOpts.Prefix && !strings.HasPrefix(v, key) {
+	switch {
+	case query && len(readOpts.Filters) > 0 && (readOpts.Prefix || readOpts.Suffix):
+		// only read the records in the index range of the first filter
+		indexed, err := scanIndex(fd, readOpts.Filters[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range indexed {
+			if readOpts.Prefix && !strings.HasPrefix(k, key) {
 				continue
 			}
-			if readOpts.Suffix && !strings.HasSuffix(v, key) {
+			if readOpts.Suffix && !strings.HasSuffix(k, key) {
 				continue
 			}
-			keys = append(keys, v)
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	case readOpts.Prefix || readOpts.Suffix:
+		var listOpts store.ListOptions
+		if !query {
+			listOpts.Limit = readOpts.Limit
+			listOpts.Offset = readOpts.Offset
+		}
+		if readOpts.Prefix {
+			listOpts.Prefix = key
 		}
-	} else {
+		if readOpts.Suffix {
+			listOpts.Suffix = key
+		}
+		keys = m.list(fd, listOpts)
+	default:
 		keys = []string{key}
 	}
 
@@ -339,12 +590,22 @@ func (m *fileStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 
 	for _, k := range keys {
 		r, err := m.get(fd, k)
-		if err != nil {
+		if err == store.ErrNotFound && (readOpts.Prefix || readOpts.Suffix) {
+			// expired since it was listed
+			continue
+		} else if err != nil {
 			return results, err
 		}
 		results = append(results, r)
 	}
 
+	if query {
+		results = store.Query(results, readOpts)
+		if len(results) == 0 && !readOpts.Prefix && !readOpts.Suffix {
+			return nil, store.ErrNotFound
+		}
+	}
+
 	return results, nil
 }
 
@@ -378,10 +639,56 @@ func (m *fileStore) Write(r *store.Record, opts ...store.WriteOption) error {
 			newRecord.Metadata[k] = v
 		}
 
-		return m.set(fd, &newRecord)
+		return m.set(fd, &newRecord, writeOpts)
 	}
 
-	return m.set(fd, r)
+	return m.set(fd, r, writeOpts)
+}
+
+func (m *fileStore) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	var batchOpts store.BatchOptions
+	for _, o := range opts {
+		o(&batchOpts)
+	}
+
+	fd, err := m.getDB(batchOpts.Database, batchOpts.Table)
+	if err != nil {
+		return err
+	}
+
+	return m.batch(fd, b)
+}
+
+func (m *fileStore) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	var wo store.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+
+	fd, err := m.getDB(wo.Database, wo.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		id:   uuid.New().String(),
+		key:  fd.key,
+		wo:   wo,
+		res:  make(chan *store.Event, 64),
+		exit: make(chan bool),
+	}
+
+	w.unwatch = func() {
+		m.wmtx.Lock()
+		delete(m.watchers, w.id)
+		m.wmtx.Unlock()
+	}
+
+	m.wmtx.Lock()
+	m.watchers[w.id] = w
+	m.wmtx.Unlock()
+
+	return w, nil
 }
 
 func (m *fileStore) Options() store.Options {
@@ -401,29 +708,7 @@ func (m *fileStore) List(opts ...store.ListOption) ([]string, error) {
 	}
 
 	// TODO apply prefix/suffix in range query
-	allKeys := m.list(fd, listOptions.Limit, listOptions.Offset)
-
-	if len(listOptions.Prefix) > 0 {
-		var prefixKeys []string
-		for _, k := range allKeys {
-			if strings.HasPrefix(k, listOptions.Prefix) {
-				prefixKeys = append(prefixKeys, k)
-			}
-		}
-		allKeys = prefixKeys
-	}
-
-	if len(listOptions.Suffix) > 0 {
-		var suffixKeys []string
-		for _, k := range allKeys {
-			if strings.HasSuffix(k, listOptions.Suffix) {
-				suffixKeys = append(suffixKeys, k)
-			}
-		}
-		allKeys = suffixKeys
-	}
-
-	return allKeys, nil
+	return m.list(fd, listOptions), nil
 }
 
 func (m *fileStore) String() string {
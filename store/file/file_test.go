@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/google/uuid"
 	"github.com/kr/pretty"
 	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/test"
 )
 
 func cleanup(db string, s store.Store) {
@@ -52,6 +54,94 @@ func TestFileStoreDatabaseTable(t *testing.T) {
 	fileTest(s, t)
 }
 
+func TestFileStoreConformance(t *testing.T) {
+	defer os.RemoveAll(filepath.Join(DefaultDir, "conformance"))
+
+	test.Run(t, func() store.Store {
+		return NewStore(store.Database("conformance"), store.Table(uuid.New().String()))
+	})
+}
+
+func TestFileStoreWatchOverflow(t *testing.T) {
+	defer os.RemoveAll(filepath.Join(DefaultDir, "overflow"))
+
+	test.RunWatchOverflow(t, func() store.Store {
+		return NewStore(store.Database("overflow"), store.Table(uuid.New().String()))
+	})
+}
+
+func TestFileStoreWatchStop(t *testing.T) {
+	s := NewStore(store.Database("watchstop"), store.Table(uuid.New().String())).(*fileStore)
+	defer cleanup("watchstop", s)
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Watch(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a stopped watcher is removed without waiting for an event
+	w.Stop()
+	s.wmtx.RLock()
+	n := len(s.watchers)
+	s.wmtx.RUnlock()
+	if n != 1 {
+		t.Fatalf("Expected 1 watcher left, got %d", n)
+	}
+}
+
+func TestFileStoreQuery(t *testing.T) {
+	defer os.RemoveAll(filepath.Join(DefaultDir, "query"))
+
+	test.RunQuery(t, func(indexes ...string) store.Store {
+		return NewStore(store.Database("query"), store.Table(uuid.New().String()), store.Indexes(indexes...))
+	})
+}
+
+func TestFileStoreIndexes(t *testing.T) {
+	table := uuid.New().String()
+	s := NewStore(store.Database("indexes"), store.Table(table))
+	defer cleanup("indexes", s)
+
+	for i, name := range []string{"alice", "bob", "carol"} {
+		if err := s.Write(&store.Record{Key: name, Metadata: map[string]interface{}{"age": 30 + i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := s.Read("", store.ReadPrefix(), store.ReadFilter("age", store.Greater, 30)); err != store.ErrNotIndexed {
+		t.Fatalf("Expected %v, got %v", store.ErrNotIndexed, err)
+	}
+
+	// the index is built from the existing records
+	if err := s.Init(store.Indexes("age")); err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.Read("", store.ReadPrefix(), store.ReadFilter("age", store.Greater, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r) != 2 || r[0].Key != "bob" || r[1].Key != "carol" {
+		t.Fatalf("Expected bob and carol, got %v", r)
+	}
+
+	// and is kept up to date when the store is reopened
+	s.Close()
+	s = NewStore(store.Database("indexes"), store.Table(table), store.Indexes("age"))
+	if err := s.Delete("bob"); err != nil {
+		t.Fatal(err)
+	}
+	r, err = s.Read("", store.ReadPrefix(), store.ReadFilter("age", store.Greater, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r) != 1 || r[0].Key != "carol" {
+		t.Fatalf("Expected carol, got %v", r)
+	}
+}
+
 func fileTest(s store.Store, t *testing.T) {
 	if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
 		t.Logf("Options %s %v\n", s.String(), s.Options())
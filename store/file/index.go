@@ -0,0 +1,197 @@
+package file
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"github.com/micro/go-micro/v2/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// prefix of the buckets used for the indexes of metadata fields
+	indexBucket = "index:"
+)
+
+// encodeValue encodes a metadata value so the encoded values sort in the order of
+// store.CompareValues. Encoded values aren't a prefix of each other, so the key
+// of the record can be appended.
+func encodeValue(v interface{}) ([]byte, bool) {
+	typ := store.ValueType(v)
+
+	switch typ {
+	case 1:
+		s := v.(string)
+		b := make([]byte, 0, len(s)+3)
+		b = append(b, byte(typ))
+		// escape the terminator
+		for i := 0; i < len(s); i++ {
+			b = append(b, s[i])
+			if s[i] == 0x00 {
+				b = append(b, 0xff)
+			}
+		}
+		return append(b, 0x00, 0x01), true
+	case 2:
+		f, _ := store.Number(v)
+		bits := math.Float64bits(f)
+		if f < 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 63
+		}
+		b := make([]byte, 9)
+		b[0] = byte(typ)
+		binary.BigEndian.PutUint64(b[1:], bits)
+		return b, true
+	case 3:
+		if v.(bool) {
+			return []byte{byte(typ), 0x01}, true
+		}
+		return []byte{byte(typ), 0x00}, true
+	}
+
+	return nil, false
+}
+
+// updateIndexes replaces the entries of the record with the key in the indexes
+// from those of the old metadata to those of the new metadata
+func updateIndexes(tx *bolt.Tx, indexes []string, key string, old, new map[string]interface{}) error {
+	for _, field := range indexes {
+		b, err := tx.CreateBucketIfNotExists([]byte(indexBucket + field))
+		if err != nil {
+			return err
+		}
+		if v, ok := encodeValue(old[field]); ok {
+			if err := b.Delete(append(v, key...)); err != nil {
+				return err
+			}
+		}
+		if v, ok := encodeValue(new[field]); ok {
+			if err := b.Put(append(v, key...), []byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncIndexes builds the indexes which don't exist from the records and
+// deletes those which are no longer declared, so they can't become stale
+func syncIndexes(fd *fileHandle, indexes []string) error {
+	return fd.db.Update(func(tx *bolt.Tx) error {
+		declared := make(map[string]bool)
+		for _, field := range indexes {
+			declared[indexBucket+field] = true
+		}
+
+		var stale, missing [][]byte
+		if err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if strings.HasPrefix(string(name), indexBucket) && !declared[string(name)] {
+				stale = append(stale, append([]byte{}, name...))
+			}
+			delete(declared, string(name))
+			return nil
+		}); err != nil {
+			return err
+		}
+		for name := range declared {
+			missing = append(missing, []byte(name))
+		}
+
+		for _, name := range stale {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+
+		data := tx.Bucket([]byte(dataBucket))
+
+		for _, name := range missing {
+			b, err := tx.CreateBucket(name)
+			if err != nil {
+				return err
+			}
+			if data == nil {
+				continue
+			}
+
+			field := strings.TrimPrefix(string(name), indexBucket)
+			if err := data.ForEach(func(k, v []byte) error {
+				r, err := decode(v)
+				if err != nil {
+					return err
+				}
+				if ev, ok := encodeValue(r.Metadata[field]); ok {
+					return b.Put(append(ev, k...), append([]byte{}, k...))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// scanIndex returns the keys of the records which may match the filter.
+// The records still have to be matched as the index includes expired records.
+func scanIndex(fd *fileHandle, f store.Filter) ([]string, error) {
+	value, ok := encodeValue(f.Value)
+	if !ok {
+		return nil, nil
+	}
+	typ := value[:1]
+
+	var keys []string
+
+	err := fd.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(indexBucket + f.Field))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+
+		var k, v []byte
+		switch f.Op {
+		case store.Less, store.LessOrEqual:
+			// from the first value of the type
+			k, v = c.Seek(typ)
+		default:
+			k, v = c.Seek(value)
+		}
+
+		for ; k != nil && bytes.HasPrefix(k, typ); k, v = c.Next() {
+			equal := bytes.HasPrefix(k, value)
+
+			switch f.Op {
+			case store.Equal:
+				if !equal {
+					return nil
+				}
+			case store.Greater:
+				if equal {
+					continue
+				}
+			case store.Less:
+				if equal || bytes.Compare(k, value) > 0 {
+					return nil
+				}
+			case store.LessOrEqual:
+				if !equal && bytes.Compare(k, value) > 0 {
+					return nil
+				}
+			}
+
+			keys = append(keys, string(v))
+		}
+
+		return nil
+	})
+
+	return keys, err
+}
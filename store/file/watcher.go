@@ -0,0 +1,52 @@
+package file
+
+import (
+	"sync"
+
+	"github.com/micro/go-micro/v2/store"
+)
+
+type watcher struct {
+	id string
+	// key of the database and table
+	key  string
+	wo   store.WatchOptions
+	res  chan *store.Event
+	exit chan bool
+	// removes the watcher from the store
+	unwatch func()
+
+	once sync.Once
+	// the error returned once stopped
+	err error
+}
+
+func (w *watcher) Next() (*store.Event, error) {
+	// the events left aren't returned once stopped
+	select {
+	case <-w.exit:
+		return nil, w.err
+	default:
+	}
+
+	select {
+	case ev := <-w.res:
+		return ev, nil
+	case <-w.exit:
+		return nil, w.err
+	}
+}
+
+// stop the watcher, returning the error from Next, and
+// remove it from the store so it isn't kept until the next event
+func (w *watcher) stop(err error) {
+	w.once.Do(func() {
+		w.err = err
+		close(w.exit)
+		w.unwatch()
+	})
+}
+
+func (w *watcher) Stop() {
+	w.stop(store.ErrWatcherStopped)
+}
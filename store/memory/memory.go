@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/micro/go-micro/v2/store"
 	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
@@ -19,7 +21,8 @@ func NewStore(opts ...store.Option) store.Store {
 			Database: "micro",
 			Table:    "micro",
 		},
-		store: cache.New(cache.NoExpiration, 5*time.Minute),
+		store:    cache.New(cache.NoExpiration, 5*time.Minute),
+		watchers: make(map[string]*watcher),
 	}
 	for _, o := range opts {
 		o(&s.options)
@@ -30,7 +33,12 @@ func NewStore(opts ...store.Option) store.Store {
 type memoryStore struct {
 	options store.Options
 
+	// serialises writes so versions can be compared and set atomically
+	sync.Mutex
 	store *cache.Cache
+
+	wmtx     sync.RWMutex
+	watchers map[string]*watcher
 }
 
 type storeRecord struct {
@@ -38,6 +46,7 @@ type storeRecord struct {
 	value     []byte
 	metadata  map[string]interface{}
 	expiresAt time.Time
+	version   uint64
 }
 
 func (m *memoryStore) key(prefix, key string) string {
@@ -82,6 +91,8 @@ func (m *memoryStore) get(prefix, key string) (*store.Record, error) {
 		newRecord.Expiry = time.Until(storedRecord.expiresAt)
 	}
 
+	newRecord.Version = storedRecord.version
+
 	// copy in the metadata
 	for k, v := range storedRecord.metadata {
 		newRecord.Metadata[k] = v
@@ -90,9 +101,28 @@ func (m *memoryStore) get(prefix, key string) (*store.Record, error) {
 	return newRecord, nil
 }
 
-func (m *memoryStore) set(prefix string, r *store.Record) {
+// version returns the version of the record or 0 if it doesn't exist
+func (m *memoryStore) version(prefix, key string) uint64 {
+	r, found := m.store.Get(m.key(prefix, key))
+	if !found {
+		return 0
+	}
+	if sr, ok := r.(*storeRecord); ok {
+		return sr.version
+	}
+	return 0
+}
+
+// set writes the record if it meets the conditions of the options.
+// Must be called with the lock held.
+func (m *memoryStore) set(prefix string, r *store.Record, opts store.WriteOptions) error {
 	key := m.key(prefix, r.Key)
 
+	version := m.version(prefix, r.Key)
+	if opts.Conflicts(version) {
+		return store.ErrConflict
+	}
+
 	// copy the incoming record and then
 	// convert the expiry in to a hard timestamp
 	i := &storeRecord{}
@@ -113,39 +143,93 @@ func (m *memoryStore) set(prefix string, r *store.Record) {
 		i.metadata[k] = v
 	}
 
+	i.version = version + 1
+
 	m.store.Set(key, i, r.Expiry)
+
+	ev := &store.Event{Type: store.Update, Timestamp: time.Now()}
+	if version == 0 {
+		ev.Type = store.Create
+	}
+	ev.Record, _ = m.get(prefix, r.Key)
+	if ev.Record != nil {
+		m.sendEvent(prefix, ev)
+	}
+
+	return nil
 }
 
+// delete removes the record. Must be called with the lock held.
 func (m *memoryStore) delete(prefix, key string) {
-	key = m.key(prefix, key)
-	m.store.Delete(key)
+	exists := m.version(prefix, key) > 0
+
+	m.store.Delete(m.key(prefix, key))
+
+	if exists {
+		m.sendEvent(prefix, &store.Event{
+			Type:      store.Delete,
+			Record:    &store.Record{Key: key},
+			Timestamp: time.Now(),
+		})
+	}
+}
+
+// sendEvent to the watchers of the prefix.
+// Must be called with the lock held so events are sent in order.
+func (m *memoryStore) sendEvent(prefix string, ev *store.Event) {
+	m.wmtx.RLock()
+	watchers := make([]*watcher, 0, len(m.watchers))
+	for _, w := range m.watchers {
+		if w.prefix == prefix && strings.HasPrefix(ev.Record.Key, w.wo.Prefix) {
+			watchers = append(watchers, w)
+		}
+	}
+	m.wmtx.RUnlock()
+
+	for _, w := range watchers {
+		select {
+		case <-w.exit:
+			continue
+		default:
+		}
+
+		select {
+		case w.res <- ev:
+		default:
+			// the watcher fell behind, so rather than dropping the event
+			// it's stopped and knows the records must be read again
+			w.stop(store.ErrWatcherOverflow)
+		}
+	}
 }
 
-func (m *memoryStore) list(prefix string, limit, offset uint) []string {
-	allItems := m.store.Items()
-	allKeys := make([]string, len(allItems))
-	i := 0
+// list the keys of the table matching the options, ordered by key
+func (m *memoryStore) list(prefix string, opts store.ListOptions) []string {
+	var keys []string
 
-	for k := range allItems {
+	for k := range m.store.Items() {
 		if !strings.HasPrefix(k, prefix+"/") {
 			continue
 		}
-		allKeys[i] = strings.TrimPrefix(k, prefix+"/")
-		i++
+		k = strings.TrimPrefix(k, prefix+"/")
+		if !strings.HasPrefix(k, opts.Prefix) || !strings.HasSuffix(k, opts.Suffix) {
+			continue
+		}
+		keys = append(keys, k)
 	}
 
-	if limit != 0 || offset != 0 {
-		sort.Slice(allKeys, func(i, j int) bool { return allKeys[i] < allKeys[j] })
-		min := func(i, j uint) uint {
-			if i < j {
-				return i
-			}
-			return j
-		}
-		return allKeys[offset:min(limit, uint(len(allKeys)))]
+	sort.Strings(keys)
+
+	if opts.Offset >= uint(len(keys)) {
+		return []string{}
 	}
+	keys = keys[opts.Offset:]
 
-	return allKeys
+	if opts.Limit > 0 && opts.Limit < uint(len(keys)) {
+		keys = keys[:opts.Limit]
+	}
+
+	return keys
 }
 
 func (m *memoryStore) Close() error {
@@ -172,23 +256,30 @@ func (m *memoryStore) Read(key string, opts ...store.ReadOption) ([]*store.Recor
 
 	prefix := m.prefix(readOpts.Database, readOpts.Table)
 
+	// filter and order by metadata once the records are read
+	query := len(readOpts.Filters) > 0 || len(readOpts.Order) > 0
+	if query {
+		if err := store.CheckIndexes(m.options.Indexes, readOpts); err != nil {
+			return nil, err
+		}
+	}
+
 	var keys []string
 
 	// Handle Prefix / suffix
 	if readOpts.Prefix || readOpts.Suffix {
-		k := m.list(prefix, readOpts.Limit, readOpts.Offset)
-
-		for _, kk := range k {
-			if readOpts.Prefix && !strings.HasPrefix(kk, key) {
-				continue
-			}
-
-			if readOpts.Suffix && !strings.HasSuffix(kk, key) {
-				continue
-			}
-
-			keys = append(keys, kk)
+		var listOpts store.ListOptions
+		if !query {
+			listOpts.Limit = readOpts.Limit
+			listOpts.Offset = readOpts.Offset
 		}
+		if readOpts.Prefix {
+			listOpts.Prefix = key
+		}
+		if readOpts.Suffix {
+			listOpts.Suffix = key
+		}
+		keys = m.list(prefix, listOpts)
 	} else {
 		keys = []string{key}
 	}
@@ -197,12 +288,22 @@ func (m *memoryStore) Read(key string, opts ...store.ReadOption) ([]*store.Recor
 
 	for _, k := range keys {
 		r, err := m.get(prefix, k)
-		if err != nil {
+		if err == store.ErrNotFound && (readOpts.Prefix || readOpts.Suffix) {
+			// expired since it was listed
+			continue
+		} else if err != nil {
 			return results, err
 		}
 		results = append(results, r)
 	}
 
+	if query {
+		results = store.Query(results, readOpts)
+		if len(results) == 0 && !readOpts.Prefix && !readOpts.Suffix {
+			return nil, store.ErrNotFound
+		}
+	}
+
 	return results, nil
 }
 
@@ -214,6 +315,9 @@ func (m *memoryStore) Write(r *store.Record, opts ...store.WriteOption) error {
 
 	prefix := m.prefix(writeOpts.Database, writeOpts.Table)
 
+	m.Lock()
+	defer m.Unlock()
+
 	if len(opts) > 0 {
 		// Copy the record before applying options, or the incoming record will be mutated
 		newRecord := store.Record{}
@@ -234,14 +338,11 @@ func (m *memoryStore) Write(r *store.Record, opts ...store.WriteOption) error {
 			newRecord.Metadata[k] = v
 		}
 
-		m.set(prefix, &newRecord)
-		return nil
+		return m.set(prefix, &newRecord, writeOpts)
 	}
 
 	// set
-	m.set(prefix, r)
-
-	return nil
+	return m.set(prefix, r, writeOpts)
 }
 
 func (m *memoryStore) Delete(key string, opts ...store.DeleteOption) error {
@@ -251,10 +352,72 @@ func (m *memoryStore) Delete(key string, opts ...store.DeleteOption) error {
 	}
 
 	prefix := m.prefix(deleteOptions.Database, deleteOptions.Table)
+
+	m.Lock()
+	defer m.Unlock()
+
 	m.delete(prefix, key)
 	return nil
 }
 
+func (m *memoryStore) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	batchOptions := store.BatchOptions{}
+	for _, o := range opts {
+		o(&batchOptions)
+	}
+
+	prefix := m.prefix(batchOptions.Database, batchOptions.Table)
+
+	m.Lock()
+	defer m.Unlock()
+
+	// check the read set before making any changes
+	for key, version := range b.Reads {
+		if m.version(prefix, key) != version {
+			return store.ErrConflict
+		}
+	}
+
+	for _, r := range b.Writes {
+		if err := m.set(prefix, r, store.WriteOptions{}); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range b.Deletes {
+		m.delete(prefix, key)
+	}
+
+	return nil
+}
+
+func (m *memoryStore) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	var wo store.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+
+	w := &watcher{
+		id:     uuid.New().String(),
+		prefix: m.prefix(wo.Database, wo.Table),
+		wo:     wo,
+		res:    make(chan *store.Event, 64),
+		exit:   make(chan bool),
+	}
+
+	w.unwatch = func() {
+		m.wmtx.Lock()
+		delete(m.watchers, w.id)
+		m.wmtx.Unlock()
+	}
+
+	m.wmtx.Lock()
+	m.watchers[w.id] = w
+	m.wmtx.Unlock()
+
+	return w, nil
+}
+
 func (m *memoryStore) Options() store.Options {
 	return m.options
 }
@@ -267,27 +430,6 @@ func (m *memoryStore) List(opts ...store.ListOption) ([]string, error) {
 	}
 
 	prefix := m.prefix(listOptions.Database, listOptions.Table)
-	keys := m.list(prefix, listOptions.Limit, listOptions.Offset)
-
-	if len(listOptions.Prefix) > 0 {
-		var prefixKeys []string
-		for _, k := range keys {
-			if strings.HasPrefix(k, listOptions.Prefix) {
-				prefixKeys = append(prefixKeys, k)
-			}
-		}
-		keys = prefixKeys
-	}
-
-	if len(listOptions.Suffix) > 0 {
-		var suffixKeys []string
-		for _, k := range keys {
-			if strings.HasSuffix(k, listOptions.Suffix) {
-				suffixKeys = append(suffixKeys, k)
-			}
-		}
-		keys = suffixKeys
-	}
 
-	return keys, nil
+	return m.list(prefix, listOptions), nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/kr/pretty"
 	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/test"
 )
 
 func TestMemoryReInit(t *testing.T) {
@@ -42,6 +43,46 @@ func TestMemoryNamespacePrefix(t *testing.T) {
 	basictest(s, t)
 }
 
+func TestMemoryConformance(t *testing.T) {
+	test.Run(t, func() store.Store {
+		return NewStore()
+	})
+}
+
+func TestMemoryWatchOverflow(t *testing.T) {
+	test.RunWatchOverflow(t, func() store.Store {
+		return NewStore()
+	})
+}
+
+func TestMemoryWatchStop(t *testing.T) {
+	s := NewStore().(*memoryStore)
+	defer s.Close()
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Watch(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a stopped watcher is removed without waiting for an event
+	w.Stop()
+	s.wmtx.RLock()
+	n := len(s.watchers)
+	s.wmtx.RUnlock()
+	if n != 1 {
+		t.Fatalf("Expected 1 watcher left, got %d", n)
+	}
+}
+
+func TestMemoryQuery(t *testing.T) {
+	test.RunQuery(t, func(indexes ...string) store.Store {
+		return NewStore(store.Indexes(indexes...))
+	})
+}
+
 func basictest(s store.Store, t *testing.T) {
 	if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
 		t.Logf("Testing store %s, with options %# v\n", s.String(), pretty.Formatter(s.Options()))
@@ -26,6 +26,14 @@ func (n *noopStore) Delete(key string, opts ...DeleteOption) error {
 	return nil
 }
 
+func (n *noopStore) Batch(b *Batch, opts ...BatchOption) error {
+	return nil
+}
+
+func (n *noopStore) Watch(opts ...WatchOption) (Watcher, error) {
+	return &noopWatcher{exit: make(chan bool)}, nil
+}
+
 func (n *noopStore) List(opts ...ListOption) ([]string, error) {
 	return []string{}, nil
 }
@@ -33,3 +41,20 @@ func (n *noopStore) List(opts ...ListOption) ([]string, error) {
 func (n *noopStore) Close() error {
 	return nil
 }
+
+type noopWatcher struct {
+	exit chan bool
+}
+
+func (n *noopWatcher) Next() (*Event, error) {
+	<-n.exit
+	return nil, ErrWatcherStopped
+}
+
+func (n *noopWatcher) Stop() {
+	select {
+	case <-n.exit:
+	default:
+		close(n.exit)
+	}
+}
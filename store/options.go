@@ -21,6 +21,8 @@ type Options struct {
 	Context context.Context
 	// Client to use for RPC
 	Client client.Client
+	// Indexes are the metadata fields records can be filtered and ordered by
+	Indexes []string
 }
 
 // Option sets values in Options
@@ -63,6 +65,13 @@ func WithClient(c client.Client) Option {
 	}
 }
 
+// Indexes declares the metadata fields records can be filtered and ordered by
+func Indexes(fields ...string) Option {
+	return func(o *Options) {
+		o.Indexes = fields
+	}
+}
+
 // ReadOptions configures an individual Read operation
 type ReadOptions struct {
 	Database, Table string
@@ -74,6 +83,12 @@ type ReadOptions struct {
 	Limit uint
 	// Offset when combined with Limit supports pagination
 	Offset uint
+	// Filters the records must match, on indexed metadata fields
+	Filters []Filter
+	// Order the records by an indexed metadata field rather than by key
+	Order string
+	// Descending reverses the order of the records
+	Descending bool
 }
 
 // ReadOption sets values in ReadOptions
@@ -115,6 +130,27 @@ func ReadOffset(o uint) ReadOption {
 	}
 }
 
+// ReadFilter only returns records with an indexed metadata field which compares to the value
+func ReadFilter(field string, op Operator, value interface{}) ReadOption {
+	return func(r *ReadOptions) {
+		r.Filters = append(r.Filters, Filter{Field: field, Op: op, Value: value})
+	}
+}
+
+// ReadOrder orders the records by an indexed metadata field
+func ReadOrder(field string) ReadOption {
+	return func(r *ReadOptions) {
+		r.Order = field
+	}
+}
+
+// ReadDescending orders the records by the field from the highest value
+func ReadDescending() ReadOption {
+	return func(r *ReadOptions) {
+		r.Descending = true
+	}
+}
+
 // WriteOptions configures an individual Write operation
 // If Expiry and TTL are set TTL takes precedence
 type WriteOptions struct {
@@ -123,6 +159,10 @@ type WriteOptions struct {
 	Expiry time.Time
 	// TTL is the time until the record expires
 	TTL time.Duration
+	// Version the record must be at for the write to succeed
+	Version uint64
+	// IfAbsent only writes the record if it doesn't exist
+	IfAbsent bool
 }
 
 // WriteOption sets values in WriteOptions
@@ -150,6 +190,33 @@ func WriteTTL(d time.Duration) WriteOption {
 	}
 }
 
+// WriteVersion only writes the record if it is still at the version it was
+// read at, otherwise ErrConflict is returned. A version of 0 is the same as
+// WriteIfAbsent.
+func WriteVersion(v uint64) WriteOption {
+	return func(w *WriteOptions) {
+		w.Version = v
+		w.IfAbsent = v == 0
+	}
+}
+
+// WriteIfAbsent only writes the record if it doesn't exist,
+// otherwise ErrConflict is returned
+func WriteIfAbsent() WriteOption {
+	return func(w *WriteOptions) {
+		w.IfAbsent = true
+	}
+}
+
+// Conflicts reports whether a record at the version, which is 0 if it
+// doesn't exist, fails the conditions of the write options
+func (w WriteOptions) Conflicts(version uint64) bool {
+	if w.IfAbsent {
+		return version != 0
+	}
+	return w.Version != 0 && w.Version != version
+}
+
 // DeleteOptions configures an individual Delete operation
 type DeleteOptions struct {
 	Database, Table string
@@ -166,6 +233,47 @@ func DeleteFrom(database, table string) DeleteOption {
 	}
 }
 
+// BatchOptions configures a Batch operation
+type BatchOptions struct {
+	Database, Table string
+}
+
+// BatchOption sets values in BatchOptions
+type BatchOption func(b *BatchOptions)
+
+// BatchTo the database and table
+func BatchTo(database, table string) BatchOption {
+	return func(b *BatchOptions) {
+		b.Database = database
+		b.Table = table
+	}
+}
+
+// WatchOptions configures a Watch operation
+type WatchOptions struct {
+	Database, Table string
+	// Prefix only watches the keys with the prefix
+	Prefix string
+}
+
+// WatchOption sets values in WatchOptions
+type WatchOption func(w *WatchOptions)
+
+// WatchFrom the database and table
+func WatchFrom(database, table string) WatchOption {
+	return func(w *WatchOptions) {
+		w.Database = database
+		w.Table = table
+	}
+}
+
+// WatchPrefix only watches the keys with the prefix
+func WatchPrefix(p string) WatchOption {
+	return func(w *WatchOptions) {
+		w.Prefix = p
+	}
+}
+
 // ListOptions configures an individual List operation
 type ListOptions struct {
 	// List from the following
@@ -0,0 +1,245 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+var (
+	// ErrNotIndexed is returned when records are filtered or ordered by a field which isn't indexed
+	ErrNotIndexed = errors.New("field not indexed")
+)
+
+// Operator compares the value of a metadata field
+type Operator int
+
+const (
+	// Equal matches values equal to the value of the filter
+	Equal Operator = iota
+	// Less matches values less than the value of the filter
+	Less
+	// LessOrEqual matches values less than or equal to the value of the filter
+	LessOrEqual
+	// Greater matches values greater than the value of the filter
+	Greater
+	// GreaterOrEqual matches values greater than or equal to the value of the filter
+	GreaterOrEqual
+)
+
+// String returns human readable operator
+func (o Operator) String() string {
+	switch o {
+	case Equal:
+		return "="
+	case Less:
+		return "<"
+	case LessOrEqual:
+		return "<="
+	case Greater:
+		return ">"
+	case GreaterOrEqual:
+		return ">="
+	default:
+		return "unknown"
+	}
+}
+
+// Filter matches the records with a metadata field which compares to a value.
+// Only values of the same type match, which are strings, numbers or booleans.
+type Filter struct {
+	Field string
+	Op    Operator
+	Value interface{}
+}
+
+// Match returns true if the metadata field of the record matches the filter
+func (f Filter) Match(r *Record) bool {
+	v, ok := r.Metadata[f.Field]
+	if !ok {
+		return false
+	}
+
+	// only values of the same type can be compared
+	if ValueType(v) != ValueType(f.Value) || ValueType(v) == 0 {
+		return false
+	}
+
+	c := CompareValues(v, f.Value)
+
+	switch f.Op {
+	case Equal:
+		return c == 0
+	case Less:
+		return c < 0
+	case LessOrEqual:
+		return c <= 0
+	case Greater:
+		return c > 0
+	case GreaterOrEqual:
+		return c >= 0
+	}
+
+	return false
+}
+
+// CheckIndexes returns ErrNotIndexed if the options filter or order by a field which isn't indexed
+func CheckIndexes(indexes []string, opts ReadOptions) error {
+	indexed := func(field string) bool {
+		for _, i := range indexes {
+			if i == field {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, f := range opts.Filters {
+		if !indexed(f.Field) {
+			return ErrNotIndexed
+		}
+	}
+	if len(opts.Order) > 0 && !indexed(opts.Order) {
+		return ErrNotIndexed
+	}
+
+	return nil
+}
+
+// ValueType returns the rank of the type of a metadata value in the order of values:
+// strings are 1, numbers 2 and booleans 3. Other types are 0 and can't be compared.
+func ValueType(v interface{}) int {
+	switch v.(type) {
+	case string:
+		return 1
+	case bool:
+		return 3
+	}
+	if _, ok := Number(v); ok {
+		return 2
+	}
+	return 0
+}
+
+// Number converts a numeric metadata value to a float64
+func Number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// CompareValues returns -1, 0 or 1 if a is less than, equal to or greater than b.
+// Values of different types are ordered by ValueType.
+func CompareValues(a, b interface{}) int {
+	ta, tb := ValueType(a), ValueType(b)
+	switch {
+	case ta < tb:
+		return -1
+	case ta > tb:
+		return 1
+	}
+
+	switch ta {
+	case 1:
+		sa, sb := a.(string), b.(string)
+		switch {
+		case sa < sb:
+			return -1
+		case sa > sb:
+			return 1
+		}
+	case 2:
+		na, _ := Number(a)
+		nb, _ := Number(b)
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+	case 3:
+		ba, bb := a.(bool), b.(bool)
+		switch {
+		case !ba && bb:
+			return -1
+		case ba && !bb:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// Query filters, orders and paginates the records by the read options.
+// The records must be ordered by key, and are filtered by key as well
+// as any other options by the caller.
+func Query(records []*Record, opts ReadOptions) []*Record {
+	var matched []*Record
+
+	for _, r := range records {
+		match := true
+		for _, f := range opts.Filters {
+			if !f.Match(r) {
+				match = false
+				break
+			}
+		}
+		if match {
+			matched = append(matched, r)
+		}
+	}
+
+	if len(opts.Order) > 0 {
+		// records without a value for the field are last, then by key
+		sort.SliceStable(matched, func(i, j int) bool {
+			vi, vj := matched[i].Metadata[opts.Order], matched[j].Metadata[opts.Order]
+			iok, jok := ValueType(vi) > 0, ValueType(vj) > 0
+			if !iok || !jok {
+				return iok && !jok
+			}
+			c := CompareValues(vi, vj)
+			if opts.Descending {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	if opts.Offset >= uint(len(matched)) {
+		return []*Record{}
+	}
+	matched = matched[opts.Offset:]
+
+	if opts.Limit > 0 && opts.Limit < uint(len(matched)) {
+		matched = matched[:opts.Limit]
+	}
+
+	return matched
+}
@@ -45,9 +45,11 @@ type StoreService interface {
 	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
 	Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...client.CallOption) (*DeleteResponse, error)
+	Batch(ctx context.Context, in *BatchRequest, opts ...client.CallOption) (*BatchResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (Store_ListService, error)
 	Databases(ctx context.Context, in *DatabasesRequest, opts ...client.CallOption) (*DatabasesResponse, error)
 	Tables(ctx context.Context, in *TablesRequest, opts ...client.CallOption) (*TablesResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Store_WatchService, error)
 }
 
 type storeService struct {
@@ -92,6 +94,16 @@ func (c *storeService) Delete(ctx context.Context, in *DeleteRequest, opts ...cl
 	return out, nil
 }
 
+func (c *storeService) Batch(ctx context.Context, in *BatchRequest, opts ...client.CallOption) (*BatchResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.Batch", in)
+	out := new(BatchResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *storeService) List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (Store_ListService, error) {
 	req := c.c.NewRequest(c.name, "Store.List", &ListRequest{})
 	stream, err := c.c.Stream(ctx, req, opts...)
@@ -161,15 +173,66 @@ func (c *storeService) Tables(ctx context.Context, in *TablesRequest, opts ...cl
 	return out, nil
 }
 
+func (c *storeService) Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Store_WatchService, error) {
+	req := c.c.NewRequest(c.name, "Store.Watch", &WatchRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &storeServiceWatch{stream}, nil
+}
+
+type Store_WatchService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*WatchResponse, error)
+}
+
+type storeServiceWatch struct {
+	stream client.Stream
+}
+
+func (x *storeServiceWatch) Close() error {
+	return x.stream.Close()
+}
+
+func (x *storeServiceWatch) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *storeServiceWatch) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *storeServiceWatch) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *storeServiceWatch) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Store service
 
 type StoreHandler interface {
 	Read(context.Context, *ReadRequest, *ReadResponse) error
 	Write(context.Context, *WriteRequest, *WriteResponse) error
 	Delete(context.Context, *DeleteRequest, *DeleteResponse) error
+	Batch(context.Context, *BatchRequest, *BatchResponse) error
 	List(context.Context, *ListRequest, Store_ListStream) error
 	Databases(context.Context, *DatabasesRequest, *DatabasesResponse) error
 	Tables(context.Context, *TablesRequest, *TablesResponse) error
+	Watch(context.Context, *WatchRequest, Store_WatchStream) error
 }
 
 func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.HandlerOption) error {
@@ -177,9 +240,11 @@ func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.Han
 		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
 		Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error
 		Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error
+		Batch(ctx context.Context, in *BatchRequest, out *BatchResponse) error
 		List(ctx context.Context, stream server.Stream) error
 		Databases(ctx context.Context, in *DatabasesRequest, out *DatabasesResponse) error
 		Tables(ctx context.Context, in *TablesRequest, out *TablesResponse) error
+		Watch(ctx context.Context, stream server.Stream) error
 	}
 	type Store struct {
 		store
@@ -204,6 +269,10 @@ func (h *storeHandler) Delete(ctx context.Context, in *DeleteRequest, out *Delet
 	return h.StoreHandler.Delete(ctx, in, out)
 }
 
+func (h *storeHandler) Batch(ctx context.Context, in *BatchRequest, out *BatchResponse) error {
+	return h.StoreHandler.Batch(ctx, in, out)
+}
+
 func (h *storeHandler) List(ctx context.Context, stream server.Stream) error {
 	m := new(ListRequest)
 	if err := stream.Recv(m); err != nil {
@@ -251,3 +320,43 @@ func (h *storeHandler) Databases(ctx context.Context, in *DatabasesRequest, out
 func (h *storeHandler) Tables(ctx context.Context, in *TablesRequest, out *TablesResponse) error {
 	return h.StoreHandler.Tables(ctx, in, out)
 }
+
+func (h *storeHandler) Watch(ctx context.Context, stream server.Stream) error {
+	m := new(WatchRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.StoreHandler.Watch(ctx, m, &storeWatchStream{stream})
+}
+
+type Store_WatchStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*WatchResponse) error
+}
+
+type storeWatchStream struct {
+	stream server.Stream
+}
+
+func (x *storeWatchStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *storeWatchStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *storeWatchStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *storeWatchStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *storeWatchStream) Send(m *WatchResponse) error {
+	return x.stream.Send(m)
+}
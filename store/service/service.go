@@ -117,6 +117,11 @@ func (s *serviceStore) Read(key string, opts ...store.ReadOption) ([]*store.Reco
 		o(&options)
 	}
 
+	// the store service can't filter or order by metadata
+	if len(options.Filters) > 0 || len(options.Order) > 0 {
+		return nil, store.ErrNotIndexed
+	}
+
 	readOpts := &pb.ReadOptions{
 		Database: options.Database,
 		Table:    options.Table,
@@ -139,25 +144,30 @@ func (s *serviceStore) Read(key string, opts ...store.ReadOption) ([]*store.Reco
 	records := make([]*store.Record, 0, len(rsp.Records))
 
 	for _, val := range rsp.Records {
-		metadata := make(map[string]interface{})
+		records = append(records, toRecord(val))
+	}
 
-		for k, v := range val.Metadata {
-			switch v.Type {
-			// TODO: parse all types
-			default:
-				metadata[k] = v
-			}
-		}
+	return records, nil
+}
 
-		records = append(records, &store.Record{
-			Key:      val.Key,
-			Value:    val.Value,
-			Expiry:   time.Duration(val.Expiry) * time.Second,
-			Metadata: metadata,
-		})
+func toRecord(val *pb.Record) *store.Record {
+	metadata := make(map[string]interface{})
+
+	for k, v := range val.Metadata {
+		switch v.Type {
+		// TODO: parse all types
+		default:
+			metadata[k] = v
+		}
 	}
 
-	return records, nil
+	return &store.Record{
+		Key:      val.Key,
+		Value:    val.Value,
+		Expiry:   time.Duration(val.Expiry) * time.Second,
+		Metadata: metadata,
+		Version:  val.Version,
+	}
 }
 
 // Write a record
@@ -174,6 +184,8 @@ func (s *serviceStore) Write(record *store.Record, opts ...store.WriteOption) er
 	writeOpts := &pb.WriteOptions{
 		Database: options.Database,
 		Table:    options.Table,
+		Version:  options.Version,
+		IfAbsent: options.IfAbsent,
 	}
 
 	metadata := make(map[string]*pb.Field)
@@ -195,6 +207,8 @@ func (s *serviceStore) Write(record *store.Record, opts ...store.WriteOption) er
 		Options: writeOpts}, client.WithAddress(s.Nodes...))
 	if err != nil && errors.Equal(err, errors.NotFound("", "")) {
 		return store.ErrNotFound
+	} else if err != nil && errors.Equal(err, errors.Conflict("", "")) {
+		return store.ErrConflict
 	}
 
 	return err
@@ -227,6 +241,80 @@ func (s *serviceStore) Delete(key string, opts ...store.DeleteOption) error {
 	return err
 }
 
+// Batch applies a batch of writes and deletes
+func (s *serviceStore) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	options := store.BatchOptions{
+		Database: s.Database,
+		Table:    s.Table,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	batchOpts := &pb.BatchOptions{
+		Database: options.Database,
+		Table:    options.Table,
+	}
+
+	writes := make([]*pb.Record, 0, len(b.Writes))
+
+	for _, record := range b.Writes {
+		metadata := make(map[string]*pb.Field)
+
+		for k, v := range record.Metadata {
+			metadata[k] = &pb.Field{
+				Type:  reflect.TypeOf(v).String(),
+				Value: fmt.Sprintf("%v", v),
+			}
+		}
+
+		writes = append(writes, &pb.Record{
+			Key:      record.Key,
+			Value:    record.Value,
+			Expiry:   int64(record.Expiry.Seconds()),
+			Metadata: metadata,
+		})
+	}
+
+	_, err := s.Client.Batch(s.Context(), &pb.BatchRequest{
+		Reads:   b.Reads,
+		Writes:  writes,
+		Deletes: b.Deletes,
+		Options: batchOpts,
+	}, client.WithAddress(s.Nodes...))
+	if err != nil && errors.Equal(err, errors.Conflict("", "")) {
+		return store.ErrConflict
+	}
+
+	return err
+}
+
+// Watch the changes to the records of a table
+func (s *serviceStore) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	options := store.WatchOptions{
+		Database: s.Database,
+		Table:    s.Table,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	stream, err := s.Client.Watch(s.Context(), &pb.WatchRequest{
+		Options: &pb.WatchOptions{
+			Database: options.Database,
+			Table:    options.Table,
+			Prefix:   options.Prefix,
+		},
+	}, client.WithAddress(s.Nodes...))
+	if err != nil {
+		return nil, err
+	}
+
+	return newWatcher(stream), nil
+}
+
 func (s *serviceStore) String() string {
 	return "service"
 }
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"time"
+
+	"github.com/micro/go-micro/v2/store"
+	pb "github.com/micro/go-micro/v2/store/service/proto"
+)
+
+type serviceWatcher struct {
+	stream pb.Store_WatchService
+	closed chan bool
+}
+
+func (s *serviceWatcher) Next() (*store.Event, error) {
+	// check if closed
+	select {
+	case <-s.closed:
+		return nil, store.ErrWatcherStopped
+	default:
+	}
+
+	r, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &store.Event{
+		Record:    &store.Record{},
+		Timestamp: time.Unix(r.Timestamp, 0),
+	}
+	if r.Record != nil {
+		ev.Record = toRecord(r.Record)
+	}
+
+	switch r.Type {
+	case store.Create.String():
+		ev.Type = store.Create
+	case store.Delete.String():
+		ev.Type = store.Delete
+	default:
+		ev.Type = store.Update
+	}
+
+	return ev, nil
+}
+
+func (s *serviceWatcher) Stop() {
+	select {
+	case <-s.closed:
+		return
+	default:
+		close(s.closed)
+		s.stream.Close()
+	}
+}
+
+func newWatcher(stream pb.Store_WatchService) store.Watcher {
+	return &serviceWatcher{
+		stream: stream,
+		closed: make(chan bool),
+	}
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/go-micro/v2/store"
+)
+
+type reapIntervalKey struct{}
+
+// ReapInterval sets how often expired records are deleted from the database
+func ReapInterval(d time.Duration) store.Option {
+	return func(o *store.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, reapIntervalKey{}, d)
+	}
+}
@@ -0,0 +1,711 @@
+// Package sqlite implements the store on embedded SQLite databases. It uses a
+// pure Go driver, so binaries using it build without cgo.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/store"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	// DefaultDatabase is the database used if none is provided
+	DefaultDatabase = "micro"
+	// DefaultTable is the table used if none is provided
+	DefaultTable = "micro"
+	// DefaultDir is the default directory for the database files,
+	// it can be overridden with store.Nodes
+	DefaultDir = filepath.Join(os.TempDir(), "micro", "store", "sqlite")
+	// DefaultReapInterval is how often expired records are deleted
+	DefaultReapInterval = time.Minute
+
+	// escapes the glob special characters
+	globEscaper = strings.NewReplacer("[", "[[]", "*", "[*]", "?", "[?]")
+)
+
+// NewStore returns a store backed by a SQLite database file per database
+// in the directory of the first node, which defaults to DefaultDir
+func NewStore(opts ...store.Option) store.Store {
+	s := &sqliteStore{
+		handles:  make(map[string]*dbHandle),
+		watchers: make(map[string]*watcher),
+	}
+	s.init(opts...)
+	return s
+}
+
+type sqliteStore struct {
+	options      store.Options
+	dir          string
+	reapInterval time.Duration
+
+	// the database handles
+	sync.RWMutex
+	handles map[string]*dbHandle
+
+	wmtx     sync.RWMutex
+	watchers map[string]*watcher
+}
+
+type dbHandle struct {
+	db *sql.DB
+
+	// serialises writes so versions can be compared
+	// and events are sent in order
+	sync.Mutex
+	// the tables which have been created
+	tables map[string]bool
+	exit   chan bool
+}
+
+// record stored by us
+type record struct {
+	key       string
+	value     []byte
+	metadata  sql.NullString
+	expiresAt sql.NullInt64
+	version   uint64
+}
+
+func (r *record) toRecord() *store.Record {
+	rec := &store.Record{
+		Key:      r.key,
+		Value:    r.value,
+		Metadata: make(map[string]interface{}),
+		Version:  r.version,
+	}
+	if rec.Value == nil {
+		rec.Value = []byte{}
+	}
+	if r.metadata.Valid {
+		json.Unmarshal([]byte(r.metadata.String), &rec.Metadata)
+	}
+	if r.expiresAt.Valid {
+		rec.Expiry = time.Until(time.Unix(0, r.expiresAt.Int64))
+	}
+	return rec
+}
+
+// querier is implemented by sql.DB and sql.Tx
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (s *sqliteStore) init(opts ...store.Option) error {
+	for _, o := range opts {
+		o(&s.options)
+	}
+
+	if len(s.options.Database) == 0 {
+		s.options.Database = DefaultDatabase
+	}
+	if len(s.options.Table) == 0 {
+		s.options.Table = DefaultTable
+	}
+
+	s.dir = DefaultDir
+	if len(s.options.Nodes) > 0 {
+		s.dir = s.options.Nodes[0]
+	}
+
+	s.reapInterval = DefaultReapInterval
+	if s.options.Context != nil {
+		if d, ok := s.options.Context.Value(reapIntervalKey{}).(time.Duration); ok && d > 0 {
+			s.reapInterval = d
+		}
+	}
+
+	return os.MkdirAll(s.dir, 0700)
+}
+
+// names returns the database and table names to use
+func (s *sqliteStore) names(database, table string) (string, string) {
+	if len(database) == 0 {
+		database = s.options.Database
+	}
+	if len(table) == 0 {
+		table = s.options.Table
+	}
+
+	return escape(database), escape(table)
+}
+
+// escape returns a name safe for files and tables, which is distinct for every
+// name. Bytes other than letters, numbers and dashes are escaped as _XX in hex.
+func escape(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02x", c)
+		}
+	}
+	return b.String()
+}
+
+// getDB returns the handle of the database, creating the table if it doesn't exist
+func (s *sqliteStore) getDB(database, table string) (*dbHandle, string, error) {
+	database, table = s.names(database, table)
+
+	s.RLock()
+	h, ok := s.handles[database]
+	s.RUnlock()
+
+	if !ok {
+		var err error
+		if h, err = s.open(database); err != nil {
+			return nil, "", err
+		}
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	if h.tables[table] {
+		return h, table, nil
+	}
+
+	if _, err := h.db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (
+		key TEXT NOT NULL PRIMARY KEY,
+		value BLOB,
+		metadata TEXT,
+		expiry INTEGER,
+		version INTEGER NOT NULL DEFAULT 1
+	);`, table)); err != nil {
+		return nil, "", err
+	}
+
+	if _, err := h.db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%s_expiry" ON "%s" (expiry);`, table, table)); err != nil {
+		return nil, "", err
+	}
+
+	h.tables[table] = true
+
+	return h, table, nil
+}
+
+func (s *sqliteStore) open(database string) (*dbHandle, error) {
+	// double check locking
+	s.Lock()
+	defer s.Unlock()
+
+	if h, ok := s.handles[database]; ok {
+		return h, nil
+	}
+
+	os.MkdirAll(s.dir, 0700)
+
+	path := filepath.Join(s.dir, database+".db")
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate", path)
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	h := &dbHandle{
+		db:     db,
+		tables: make(map[string]bool),
+		exit:   make(chan bool),
+	}
+	s.handles[database] = h
+
+	go s.reap(h)
+
+	return h, nil
+}
+
+// reap deletes the expired records of the tables of the database
+func (s *sqliteStore) reap(h *dbHandle) {
+	t := time.NewTicker(s.reapInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-h.exit:
+			return
+		case <-t.C:
+		}
+
+		h.Lock()
+		for table := range h.tables {
+			_, err := h.db.Exec(fmt.Sprintf(`DELETE FROM "%s" WHERE expiry IS NOT NULL AND expiry <= ?;`, table), time.Now().UnixNano())
+			if err != nil && logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("Error reaping expired records from %s: %v", table, err)
+			}
+		}
+		h.Unlock()
+	}
+}
+
+// version returns the version of the record or 0 if it doesn't exist or has expired
+func version(q querier, table, key string) (uint64, error) {
+	var v uint64
+	err := q.QueryRow(fmt.Sprintf(`SELECT version FROM "%s" WHERE key = ? AND (expiry IS NULL OR expiry > ?);`, table), key, time.Now().UnixNano()).Scan(&v)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return v, err
+}
+
+// put writes the record if it meets the conditions of the options
+// and returns the event for the change
+func put(q querier, table string, r *store.Record, opts store.WriteOptions) (*store.Event, error) {
+	v, err := version(q, table, r.Key)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Conflicts(v) {
+		return nil, store.ErrConflict
+	}
+
+	var metadata sql.NullString
+	if len(r.Metadata) > 0 {
+		b, err := json.Marshal(r.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		metadata = sql.NullString{String: string(b), Valid: true}
+	}
+
+	var expiry sql.NullInt64
+	if r.Expiry != 0 {
+		expiry = sql.NullInt64{Int64: time.Now().Add(r.Expiry).UnixNano(), Valid: true}
+	}
+
+	_, err = q.Exec(fmt.Sprintf(`INSERT OR REPLACE INTO "%s" (key, value, metadata, expiry, version) VALUES (?, ?, ?, ?, ?);`, table),
+		r.Key, r.Value, metadata, expiry, v+1)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &store.Event{
+		Type: store.Update,
+		Record: &store.Record{
+			Key:      r.Key,
+			Value:    r.Value,
+			Metadata: r.Metadata,
+			Expiry:   r.Expiry,
+			Version:  v + 1,
+		},
+		Timestamp: time.Now(),
+	}
+	if v == 0 {
+		ev.Type = store.Create
+	}
+
+	return ev, nil
+}
+
+// remove deletes the record and returns the event if it existed
+func remove(q querier, table, key string) (*store.Event, error) {
+	v, err := version(q, table, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := q.Exec(fmt.Sprintf(`DELETE FROM "%s" WHERE key = ?;`, table), key); err != nil {
+		return nil, err
+	}
+
+	if v == 0 {
+		return nil, nil
+	}
+
+	return &store.Event{
+		Type:      store.Delete,
+		Record:    &store.Record{Key: key},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// update runs fn in a transaction and sends the events once it is committed
+func (s *sqliteStore) update(h *dbHandle, key string, fn func(tx *sql.Tx) ([]*store.Event, error)) error {
+	h.Lock()
+	defer h.Unlock()
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	events, err := fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, ev := range events {
+		if ev != nil {
+			s.sendEvent(key, ev)
+		}
+	}
+
+	return nil
+}
+
+// sendEvent to the watchers of the database and table
+func (s *sqliteStore) sendEvent(key string, ev *store.Event) {
+	s.wmtx.RLock()
+	watchers := make([]*watcher, 0, len(s.watchers))
+	for _, w := range s.watchers {
+		if w.key == key && strings.HasPrefix(ev.Record.Key, w.wo.Prefix) {
+			watchers = append(watchers, w)
+		}
+	}
+	s.wmtx.RUnlock()
+
+	for _, w := range watchers {
+		select {
+		case <-w.exit:
+			continue
+		default:
+		}
+
+		select {
+		case w.res <- ev:
+		default:
+			// the watcher fell behind, so rather than dropping the event
+			// it's stopped and knows the records must be read again
+			w.stop(store.ErrWatcherOverflow)
+		}
+	}
+}
+
+// query returns the records matching the key, for a prefix or suffix
+// they are ordered by key
+func (s *sqliteStore) query(h *dbHandle, table, key string, options store.ReadOptions) ([]*store.Record, error) {
+	where := []string{"(expiry IS NULL OR expiry > ?)"}
+	args := []interface{}{time.Now().UnixNano()}
+
+	switch {
+	case options.Prefix || options.Suffix:
+		if options.Prefix {
+			where = append(where, "key GLOB ?")
+			args = append(args, globEscaper.Replace(key)+"*")
+		}
+		if options.Suffix {
+			where = append(where, "key GLOB ?")
+			args = append(args, "*"+globEscaper.Replace(key))
+		}
+	default:
+		where = append(where, "key = ?")
+		args = append(args, key)
+	}
+
+	q := fmt.Sprintf(`SELECT key, value, metadata, expiry, version FROM "%s" WHERE %s ORDER BY key`, table, strings.Join(where, " AND "))
+
+	if options.Limit > 0 || options.Offset > 0 {
+		limit := int64(-1)
+		if options.Limit > 0 {
+			limit = int64(options.Limit)
+		}
+		q += " LIMIT ? OFFSET ?"
+		args = append(args, limit, options.Offset)
+	}
+
+	rows, err := h.db.Query(q+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*store.Record
+
+	for rows.Next() {
+		var r record
+		if err := rows.Scan(&r.key, &r.value, &r.metadata, &r.expiresAt, &r.version); err != nil {
+			return records, err
+		}
+		records = append(records, r.toRecord())
+	}
+
+	return records, rows.Err()
+}
+
+func (s *sqliteStore) Init(opts ...store.Option) error {
+	return s.init(opts...)
+}
+
+func (s *sqliteStore) Options() store.Options {
+	return s.options
+}
+
+func (s *sqliteStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var readOpts store.ReadOptions
+	for _, o := range opts {
+		o(&readOpts)
+	}
+
+	// filter and order by metadata once the records are read
+	query := len(readOpts.Filters) > 0 || len(readOpts.Order) > 0
+	if query {
+		if err := store.CheckIndexes(s.options.Indexes, readOpts); err != nil {
+			return nil, err
+		}
+	}
+
+	h, table, err := s.getDB(readOpts.Database, readOpts.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	queryOpts := readOpts
+	if query {
+		// paginated after filtering
+		queryOpts.Limit = 0
+		queryOpts.Offset = 0
+	}
+
+	records, err := s.query(h, table, key, queryOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if query {
+		records = store.Query(records, readOpts)
+	}
+
+	if !readOpts.Prefix && !readOpts.Suffix && len(records) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return records, nil
+}
+
+func (s *sqliteStore) Write(r *store.Record, opts ...store.WriteOption) error {
+	var writeOpts store.WriteOptions
+	for _, o := range opts {
+		o(&writeOpts)
+	}
+
+	h, table, err := s.getDB(writeOpts.Database, writeOpts.Table)
+	if err != nil {
+		return err
+	}
+
+	if len(opts) > 0 {
+		// Copy the record before applying options, or the incoming record will be mutated
+		newRecord := store.Record{}
+		newRecord.Key = r.Key
+		newRecord.Value = r.Value
+		newRecord.Metadata = make(map[string]interface{})
+		newRecord.Expiry = r.Expiry
+
+		if !writeOpts.Expiry.IsZero() {
+			newRecord.Expiry = time.Until(writeOpts.Expiry)
+		}
+		if writeOpts.TTL != 0 {
+			newRecord.Expiry = writeOpts.TTL
+		}
+
+		for k, v := range r.Metadata {
+			newRecord.Metadata[k] = v
+		}
+
+		r = &newRecord
+	}
+
+	database, _ := s.names(writeOpts.Database, writeOpts.Table)
+
+	return s.update(h, database+":"+table, func(tx *sql.Tx) ([]*store.Event, error) {
+		ev, err := put(tx, table, r, writeOpts)
+		return []*store.Event{ev}, err
+	})
+}
+
+func (s *sqliteStore) Delete(key string, opts ...store.DeleteOption) error {
+	var deleteOpts store.DeleteOptions
+	for _, o := range opts {
+		o(&deleteOpts)
+	}
+
+	h, table, err := s.getDB(deleteOpts.Database, deleteOpts.Table)
+	if err != nil {
+		return err
+	}
+
+	database, _ := s.names(deleteOpts.Database, deleteOpts.Table)
+
+	return s.update(h, database+":"+table, func(tx *sql.Tx) ([]*store.Event, error) {
+		ev, err := remove(tx, table, key)
+		return []*store.Event{ev}, err
+	})
+}
+
+func (s *sqliteStore) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	var batchOpts store.BatchOptions
+	for _, o := range opts {
+		o(&batchOpts)
+	}
+
+	h, table, err := s.getDB(batchOpts.Database, batchOpts.Table)
+	if err != nil {
+		return err
+	}
+
+	database, _ := s.names(batchOpts.Database, batchOpts.Table)
+
+	return s.update(h, database+":"+table, func(tx *sql.Tx) ([]*store.Event, error) {
+		for key, want := range b.Reads {
+			v, err := version(tx, table, key)
+			if err != nil {
+				return nil, err
+			}
+			if v != want {
+				return nil, store.ErrConflict
+			}
+		}
+
+		var events []*store.Event
+
+		for _, r := range b.Writes {
+			ev, err := put(tx, table, r, store.WriteOptions{})
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, ev)
+		}
+
+		for _, key := range b.Deletes {
+			ev, err := remove(tx, table, key)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, ev)
+		}
+
+		return events, nil
+	})
+}
+
+func (s *sqliteStore) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	var wo store.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+
+	if _, _, err := s.getDB(wo.Database, wo.Table); err != nil {
+		return nil, err
+	}
+
+	database, table := s.names(wo.Database, wo.Table)
+
+	w := &watcher{
+		id:   uuid.New().String(),
+		key:  database + ":" + table,
+		wo:   wo,
+		res:  make(chan *store.Event, 64),
+		exit: make(chan bool),
+	}
+
+	w.unwatch = func() {
+		s.wmtx.Lock()
+		delete(s.watchers, w.id)
+		s.wmtx.Unlock()
+	}
+
+	s.wmtx.Lock()
+	s.watchers[w.id] = w
+	s.wmtx.Unlock()
+
+	return w, nil
+}
+
+func (s *sqliteStore) List(opts ...store.ListOption) ([]string, error) {
+	var listOpts store.ListOptions
+	for _, o := range opts {
+		o(&listOpts)
+	}
+
+	h, table, err := s.getDB(listOpts.Database, listOpts.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"(expiry IS NULL OR expiry > ?)"}
+	args := []interface{}{time.Now().UnixNano()}
+
+	if len(listOpts.Prefix) > 0 {
+		where = append(where, "key GLOB ?")
+		args = append(args, globEscaper.Replace(listOpts.Prefix)+"*")
+	}
+	if len(listOpts.Suffix) > 0 {
+		where = append(where, "key GLOB ?")
+		args = append(args, "*"+globEscaper.Replace(listOpts.Suffix))
+	}
+
+	q := fmt.Sprintf(`SELECT key FROM "%s" WHERE %s ORDER BY key`, table, strings.Join(where, " AND "))
+
+	if listOpts.Limit > 0 || listOpts.Offset > 0 {
+		limit := int64(-1)
+		if listOpts.Limit > 0 {
+			limit = int64(listOpts.Limit)
+		}
+		q += " LIMIT ? OFFSET ?"
+		args = append(args, limit, listOpts.Offset)
+	}
+
+	rows, err := h.db.Query(q+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Close the databases, they are opened again if the store is used
+func (s *sqliteStore) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	var err error
+
+	for k, h := range s.handles {
+		close(h.exit)
+		if cerr := h.db.Close(); cerr != nil {
+			err = cerr
+		}
+		delete(s.handles, k)
+	}
+
+	return err
+}
+
+func (s *sqliteStore) String() string {
+	return "sqlite"
+}
@@ -0,0 +1,410 @@
+package sqlite
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kr/pretty"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/test"
+)
+
+func newStore(t *testing.T, opts ...store.Option) (store.Store, func()) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewStore(append([]store.Option{store.Nodes(dir)}, opts...)...)
+	return s, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSQLiteBasic(t *testing.T) {
+	s, cleanup := newStore(t)
+	defer cleanup()
+	basictest(s, t)
+}
+
+func TestSQLiteTable(t *testing.T) {
+	s, cleanup := newStore(t, store.Table("some-table"))
+	defer cleanup()
+	basictest(s, t)
+}
+
+func TestSQLiteDatabaseTable(t *testing.T) {
+	s, cleanup := newStore(t, store.Table("some-table"), store.Database("some-database"))
+	defer cleanup()
+	basictest(s, t)
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	test.Run(t, func() store.Store {
+		return NewStore(store.Nodes(dir), store.Database(uuid.New().String()))
+	})
+}
+
+func TestSQLiteQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	test.RunQuery(t, func(indexes ...string) store.Store {
+		return NewStore(store.Nodes(dir), store.Database(uuid.New().String()), store.Indexes(indexes...))
+	})
+}
+
+func TestSQLiteNames(t *testing.T) {
+	s, cleanup := newStore(t)
+	defer cleanup()
+
+	// names which only differ in the characters escaped are kept apart
+	names := []string{"a-b", "a.b", "a_b", "a b", "a_2eb"}
+	for _, name := range names {
+		if err := s.Write(&store.Record{Key: "key", Value: []byte(name)}, store.WriteTo(name, name)); err != nil {
+			t.Fatalf("Write to %s: %v", name, err)
+		}
+		if err := s.Write(&store.Record{Key: "key", Value: []byte(name)}, store.WriteTo("", name)); err != nil {
+			t.Fatalf("Write to table %s: %v", name, err)
+		}
+	}
+
+	for _, name := range names {
+		for _, database := range []string{name, ""} {
+			r, err := s.Read("key", store.ReadFrom(database, name))
+			if err != nil {
+				t.Fatalf("Read from %s/%s: %v", database, name, err)
+			}
+			if string(r[0].Value) != name {
+				t.Fatalf("Expected %s in %s/%s, got %s", name, database, name, r[0].Value)
+			}
+		}
+	}
+}
+
+func TestSQLiteWatchOverflow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	test.RunWatchOverflow(t, func() store.Store {
+		return NewStore(store.Nodes(dir), store.Database(uuid.New().String()))
+	})
+}
+
+func TestSQLiteWatchStop(t *testing.T) {
+	st, cleanup := newStore(t)
+	defer cleanup()
+	s := st.(*sqliteStore)
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Watch(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a stopped watcher is removed without waiting for an event
+	w.Stop()
+	s.wmtx.RLock()
+	n := len(s.watchers)
+	s.wmtx.RUnlock()
+	if n != 1 {
+		t.Fatalf("Expected 1 watcher left, got %d", n)
+	}
+}
+
+func TestSQLiteReap(t *testing.T) {
+	s, cleanup := newStore(t, ReapInterval(10*time.Millisecond))
+	defer cleanup()
+
+	s.Write(&store.Record{Key: "a", Value: []byte("a"), Expiry: time.Millisecond})
+	time.Sleep(50 * time.Millisecond)
+
+	h, table, err := s.(*sqliteStore).getDB("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	if err := h.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM "%s";`, table)).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("Expected the expired record to be reaped, %d records left", n)
+	}
+}
+
+func TestSQLiteKeys(t *testing.T) {
+	s, cleanup := newStore(t)
+	defer cleanup()
+
+	// keys are matched exactly
+	for _, k := range []string{"Foo", "foo", "f*o", "f?o", "f[o]"} {
+		s.Write(&store.Record{Key: k, Value: []byte(k)})
+	}
+
+	for k, n := range map[string]int{"foo": 1, "Foo": 1, "f*": 1, "f?": 1, "f[": 1, "f": 4} {
+		keys, err := s.List(store.ListPrefix(k))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != n {
+			t.Fatalf("Expected %d keys with prefix %s, got %v", n, k, keys)
+		}
+	}
+}
+
+func basictest(s store.Store, t *testing.T) {
+	if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
+		t.Logf("Testing store %s, with options %# v\n", s.String(), pretty.Formatter(s.Options()))
+	}
+	// Read and Write an expiring Record
+	if err := s.Write(&store.Record{
+		Key:    "Hello",
+		Value:  []byte("World"),
+		Expiry: time.Millisecond * 100,
+	}); err != nil {
+		t.Error(err)
+	}
+	if r, err := s.Read("Hello"); err != nil {
+		t.Error(err)
+	} else {
+		if len(r) != 1 {
+			t.Error("Read returned multiple records")
+		}
+		if r[0].Key != "Hello" {
+			t.Errorf("Expected %s, got %s", "Hello", r[0].Key)
+		}
+		if string(r[0].Value) != "World" {
+			t.Errorf("Expected %s, got %s", "World", r[0].Value)
+		}
+	}
+	time.Sleep(time.Millisecond * 200)
+	if _, err := s.Read("Hello"); err != store.ErrNotFound {
+		t.Errorf("Expected %# v, got %# v", store.ErrNotFound, err)
+	}
+
+	// Write 3 records with various expiry and get with prefix
+	records := []*store.Record{
+		&store.Record{
+			Key:   "foo",
+			Value: []byte("foofoo"),
+		},
+		&store.Record{
+			Key:    "foobar",
+			Value:  []byte("foobarfoobar"),
+			Expiry: time.Millisecond * 100,
+		},
+		&store.Record{
+			Key:    "foobarbaz",
+			Value:  []byte("foobarbazfoobarbaz"),
+			Expiry: 2 * time.Millisecond * 100,
+		},
+	}
+	for _, r := range records {
+		if err := s.Write(r); err != nil {
+			t.Errorf("Couldn't write k: %s, v: %# v (%s)", r.Key, pretty.Formatter(r.Value), err)
+		}
+	}
+	if results, err := s.Read("foo", store.ReadPrefix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 3 {
+			t.Errorf("Expected 3 items, got %d", len(results))
+		}
+		if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
+			t.Logf("Prefix test: %v\n", pretty.Formatter(results))
+		}
+	}
+	time.Sleep(time.Millisecond * 100)
+	if results, err := s.Read("foo", store.ReadPrefix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 2 {
+			t.Errorf("Expected 2 items, got %d", len(results))
+		}
+		if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
+			t.Logf("Prefix test: %v\n", pretty.Formatter(results))
+		}
+	}
+	time.Sleep(time.Millisecond * 100)
+	if results, err := s.Read("foo", store.ReadPrefix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 1 {
+			t.Errorf("Expected 1 item, got %d", len(results))
+		}
+		if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
+			t.Logf("Prefix test: %# v\n", pretty.Formatter(results))
+		}
+	}
+	if err := s.Delete("foo", func(d *store.DeleteOptions) {}); err != nil {
+		t.Errorf("Delete failed (%v)", err)
+	}
+	if results, err := s.Read("foo", store.ReadPrefix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 0 {
+			t.Errorf("Expected 0 items, got %d (%# v)", len(results), pretty.Formatter(results))
+		}
+	}
+
+	// Write 3 records with various expiry and get with Suffix
+	records = []*store.Record{
+		&store.Record{
+			Key:   "foo",
+			Value: []byte("foofoo"),
+		},
+		&store.Record{
+			Key:    "barfoo",
+			Value:  []byte("barfoobarfoo"),
+			Expiry: time.Millisecond * 100,
+		},
+		&store.Record{
+			Key:    "bazbarfoo",
+			Value:  []byte("bazbarfoobazbarfoo"),
+			Expiry: 2 * time.Millisecond * 100,
+		},
+	}
+	for _, r := range records {
+		if err := s.Write(r); err != nil {
+			t.Errorf("Couldn't write k: %s, v: %# v (%s)", r.Key, pretty.Formatter(r.Value), err)
+		}
+	}
+	if results, err := s.Read("foo", store.ReadSuffix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 3 {
+			t.Errorf("Expected 3 items, got %d", len(results))
+		}
+		if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
+			t.Logf("Prefix test: %v\n", pretty.Formatter(results))
+		}
+	}
+	time.Sleep(time.Millisecond * 100)
+	if results, err := s.Read("foo", store.ReadSuffix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 2 {
+			t.Errorf("Expected 2 items, got %d", len(results))
+		}
+		if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
+			t.Logf("Prefix test: %v\n", pretty.Formatter(results))
+		}
+	}
+	time.Sleep(time.Millisecond * 100)
+	if results, err := s.Read("foo", store.ReadSuffix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 1 {
+			t.Errorf("Expected 1 item, got %d", len(results))
+		}
+		if len(os.Getenv("IN_TRAVIS_CI")) == 0 {
+			t.Logf("Prefix test: %# v\n", pretty.Formatter(results))
+		}
+	}
+	if err := s.Delete("foo"); err != nil {
+		t.Errorf("Delete failed (%v)", err)
+	}
+	if results, err := s.Read("foo", store.ReadSuffix()); err != nil {
+		t.Errorf("Couldn't read all \"foo\" keys, got %# v (%s)", pretty.Formatter(results), err)
+	} else {
+		if len(results) != 0 {
+			t.Errorf("Expected 0 items, got %d (%# v)", len(results), pretty.Formatter(results))
+		}
+	}
+
+	// Test Prefix, Suffix and WriteOptions
+	if err := s.Write(&store.Record{
+		Key:   "foofoobarbar",
+		Value: []byte("something"),
+	}, store.WriteTTL(time.Millisecond*100)); err != nil {
+		t.Error(err)
+	}
+	if err := s.Write(&store.Record{
+		Key:   "foofoo",
+		Value: []byte("something"),
+	}, store.WriteExpiry(time.Now().Add(time.Millisecond*100))); err != nil {
+		t.Error(err)
+	}
+	if err := s.Write(&store.Record{
+		Key:   "barbar",
+		Value: []byte("something"),
+		// TTL has higher precedence than expiry
+	}, store.WriteExpiry(time.Now().Add(time.Hour)), store.WriteTTL(time.Millisecond*100)); err != nil {
+		t.Error(err)
+	}
+	if results, err := s.Read("foo", store.ReadPrefix(), store.ReadSuffix()); err != nil {
+		t.Error(err)
+	} else {
+		if len(results) != 1 {
+			t.Errorf("Expected 1 results, got %d: %# v", len(results), pretty.Formatter(results))
+		}
+	}
+	time.Sleep(time.Millisecond * 100)
+	if results, err := s.List(); err != nil {
+		t.Errorf("List failed: %s", err)
+	} else {
+		if len(results) != 0 {
+			t.Error("Expiry options were not effective")
+		}
+	}
+	s.Write(&store.Record{Key: "a", Value: []byte("a")})
+	s.Write(&store.Record{Key: "aa", Value: []byte("aa")})
+	s.Write(&store.Record{Key: "aaa", Value: []byte("aaa")})
+	if results, err := s.Read("b", store.ReadPrefix()); err != nil {
+		t.Error(err)
+	} else {
+		if len(results) != 0 {
+			t.Errorf("Expected 0 results, got %d", len(results))
+		}
+	}
+
+	// remove the records which would be read before a0
+	for _, k := range []string{"a", "aa", "aaa"} {
+		s.Delete(k)
+	}
+	for i := 0; i < 10; i++ {
+		s.Write(&store.Record{
+			Key:   fmt.Sprintf("a%d", i),
+			Value: []byte{},
+		})
+	}
+	if results, err := s.Read("a", store.ReadLimit(5), store.ReadPrefix()); err != nil {
+		t.Error(err)
+	} else {
+		if len(results) != 5 {
+			t.Fatal("Expected 5 results, got ", len(results))
+		}
+		if results[0].Key != "a0" {
+			t.Fatalf("Expected a0, got %s", results[0].Key)
+		}
+		if results[4].Key != "a4" {
+			t.Fatalf("Expected a4, got %s", results[4].Key)
+		}
+	}
+	if results, err := s.Read("a", store.ReadLimit(30), store.ReadOffset(5), store.ReadPrefix()); err != nil {
+		t.Error(err)
+	} else {
+		if len(results) != 5 {
+			t.Error("Expected 5 results, got ", len(results))
+		}
+	}
+}
@@ -10,6 +10,11 @@ import (
 var (
 	// ErrNotFound is returned when a key doesn't exist
 	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a conditional write fails because
+	// the record was modified or already exists
+	ErrConflict = errors.New("conflict")
+	// ErrTxDone is returned when a transaction is used after it was committed or rolled back
+	ErrTxDone = errors.New("transaction done")
 	// DefaultStore is the memory store.
 	DefaultStore Store = new(noopStore)
 )
@@ -28,6 +33,10 @@ type Store interface {
 	Delete(key string, opts ...DeleteOption) error
 	// List returns any keys that match, or an empty list with no error if none matched.
 	List(opts ...ListOption) ([]string, error)
+	// Batch applies the writes and deletes of the batch atomically if none of the records it read have changed, otherwise it returns ErrConflict.
+	Batch(b *Batch, opts ...BatchOption) error
+	// Watch returns a watcher of the changes to the records of a table. Records which expire may not be notified.
+	Watch(opts ...WatchOption) (Watcher, error)
 	// Close the store
 	Close() error
 	// String returns the name of the implementation.
@@ -44,4 +53,7 @@ type Record struct {
 	Metadata map[string]interface{} `json:"metadata"`
 	// Time to expire a record: TODO: change to timestamp
 	Expiry time.Duration `json:"expiry,omitempty"`
+	// Version of the record, set by the store on read. It starts at 1
+	// and is incremented by every write, it is ignored on write.
+	Version uint64 `json:"version,omitempty"`
 }
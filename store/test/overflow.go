@@ -0,0 +1,61 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/store"
+)
+
+// RunWatchOverflow runs the tests of the watchers falling behind the changes against
+// the store returned by newStore, for the stores which buffer the events of watchers.
+func RunWatchOverflow(t *testing.T, newStore func() store.Store) {
+	s := newStore()
+	defer s.Close()
+
+	w, err := s.Watch()
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer w.Stop()
+
+	// more changes than a watcher buffers, without reading them
+	changes := 200
+	for i := 0; i < changes; i++ {
+		write(t, s, &store.Record{Key: fmt.Sprintf("key-%d", i), Value: []byte("a")})
+	}
+
+	// the watcher is stopped with an error rather than losing events
+	errs := make(chan error, 1)
+	go func() {
+		for i := 0; i <= changes; i++ {
+			if _, err := w.Next(); err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+
+	select {
+	case err := <-errs:
+		if err != store.ErrWatcherOverflow {
+			t.Fatalf("Expected %v, got %v", store.ErrWatcherOverflow, err)
+		}
+	case <-time.After(watchTimeout):
+		t.Fatalf("Next: no error after %v", watchTimeout)
+	}
+
+	// the changes are watched again with a new watcher
+	w, err = s.Watch()
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer w.Stop()
+
+	write(t, s, &store.Record{Key: "key-0", Value: []byte("b")})
+	if ev := next(t, w); ev.Type != store.Update || ev.Record.Key != "key-0" {
+		t.Fatalf("Expected update of key-0, got %s of %+v", ev.Type, ev.Record)
+	}
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/store"
+)
+
+// RunQuery runs the tests filtering and ordering records by metadata against the
+// stores returned by newStore, which must index the metadata fields passed to it.
+func RunQuery(t *testing.T, newStore func(indexes ...string) store.Store) {
+	tests := []struct {
+		name string
+		test func(*testing.T, store.Store)
+	}{
+		{"Filter", testFilter},
+		{"Order", testOrder},
+		{"NotIndexed", testNotIndexed},
+		{"Update", testQueryUpdate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStore("age", "name", "active")
+			defer s.Close()
+			writePeople(t, s)
+			tt.test(t, s)
+		})
+	}
+}
+
+func writePeople(t *testing.T, s store.Store) {
+	people := []*store.Record{
+		{Key: "a", Metadata: map[string]interface{}{"age": 30, "name": "alice", "active": true}},
+		{Key: "b", Metadata: map[string]interface{}{"age": 25, "name": "bob", "active": false}},
+		{Key: "c", Metadata: map[string]interface{}{"age": 35.5, "name": "carol", "active": true}},
+		{Key: "d", Metadata: map[string]interface{}{"age": "unknown", "name": "dave"}},
+		{Key: "e", Metadata: map[string]interface{}{"name": "eve"}},
+	}
+	for _, r := range people {
+		r.Value = []byte(r.Key)
+		write(t, s, r)
+	}
+}
+
+func query(t *testing.T, s store.Store, opts ...store.ReadOption) []string {
+	t.Helper()
+	r, err := s.Read("", append([]store.ReadOption{store.ReadPrefix()}, opts...)...)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	return keys(r)
+}
+
+func testFilter(t *testing.T, s store.Store) {
+	equal(t, "age = 30", []string{"a"}, query(t, s, store.ReadFilter("age", store.Equal, 30)))
+	equal(t, "age = 30.0", []string{"a"}, query(t, s, store.ReadFilter("age", store.Equal, 30.0)))
+	equal(t, "age > 25", []string{"a", "c"}, query(t, s, store.ReadFilter("age", store.Greater, 25)))
+	equal(t, "age >= 25", []string{"a", "b", "c"}, query(t, s, store.ReadFilter("age", store.GreaterOrEqual, 25)))
+	equal(t, "age < 35.5", []string{"a", "b"}, query(t, s, store.ReadFilter("age", store.Less, 35.5)))
+	equal(t, "age <= 30", []string{"a", "b"}, query(t, s, store.ReadFilter("age", store.LessOrEqual, 30)))
+	equal(t, "age = unknown", []string{"d"}, query(t, s, store.ReadFilter("age", store.Equal, "unknown")))
+	equal(t, "name > bob", []string{"c", "d", "e"}, query(t, s, store.ReadFilter("name", store.Greater, "bob")))
+	equal(t, "active = true", []string{"a", "c"}, query(t, s, store.ReadFilter("active", store.Equal, true)))
+	equal(t, "active = false", []string{"b"}, query(t, s, store.ReadFilter("active", store.Equal, false)))
+
+	// all of the filters must match
+	equal(t, "25 < age < 35", []string{"a"}, query(t, s,
+		store.ReadFilter("age", store.Greater, 25),
+		store.ReadFilter("age", store.Less, 35),
+	))
+	equal(t, "active = true, age >= 31", []string{"c"}, query(t, s,
+		store.ReadFilter("active", store.Equal, true),
+		store.ReadFilter("age", store.GreaterOrEqual, 31),
+	))
+
+	// filters are combined with the key
+	write(t, s, &store.Record{Key: "ab", Metadata: map[string]interface{}{"age": 30}})
+	write(t, s, &store.Record{Key: "bb", Metadata: map[string]interface{}{"age": 30}})
+	r, err := s.Read("a", store.ReadPrefix(), store.ReadFilter("age", store.Equal, 30))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	equal(t, "prefix a, age = 30", []string{"a", "ab"}, keys(r))
+	r, err = s.Read("b", store.ReadSuffix(), store.ReadFilter("age", store.Equal, 30))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	equal(t, "suffix b, age = 30", []string{"ab", "bb"}, keys(r))
+
+	// a single record is only found if it matches
+	if r := readOne(t, s, "a", store.ReadFilter("age", store.Equal, 30)); string(r.Value) != "a" {
+		t.Fatalf("Expected value a, got %s", r.Value)
+	}
+	notFound(t, s, "b", store.ReadFilter("age", store.Equal, 30))
+}
+
+func testOrder(t *testing.T, s store.Store) {
+	// strings are before numbers, records without a value are last
+	equal(t, "order by age", []string{"d", "b", "a", "c", "e"}, query(t, s, store.ReadOrder("age")))
+	equal(t, "order by age descending", []string{"c", "a", "b", "d", "e"}, query(t, s, store.ReadOrder("age"), store.ReadDescending()))
+	equal(t, "order by name descending", []string{"e", "d", "c", "b", "a"}, query(t, s, store.ReadOrder("name"), store.ReadDescending()))
+
+	// records with the same value are ordered by key
+	equal(t, "order by active", []string{"b", "a", "c", "d", "e"}, query(t, s, store.ReadOrder("active")))
+
+	// the records are paginated once ordered
+	equal(t, "order by age, limit 2, offset 1", []string{"b", "a"}, query(t, s,
+		store.ReadOrder("age"), store.ReadLimit(2), store.ReadOffset(1)))
+	equal(t, "order by age, offset 5", []string{}, query(t, s, store.ReadOrder("age"), store.ReadOffset(5)))
+
+	equal(t, "active = true, order by age descending", []string{"c", "a"}, query(t, s,
+		store.ReadFilter("active", store.Equal, true), store.ReadOrder("age"), store.ReadDescending()))
+}
+
+func testNotIndexed(t *testing.T, s store.Store) {
+	if _, err := s.Read("", store.ReadPrefix(), store.ReadFilter("email", store.Equal, "foo")); err != store.ErrNotIndexed {
+		t.Fatalf("Filter: expected %v, got %v", store.ErrNotIndexed, err)
+	}
+	if _, err := s.Read("", store.ReadPrefix(), store.ReadOrder("email")); err != store.ErrNotIndexed {
+		t.Fatalf("Order: expected %v, got %v", store.ErrNotIndexed, err)
+	}
+}
+
+func testQueryUpdate(t *testing.T, s store.Store) {
+	write(t, s, &store.Record{Key: "a", Metadata: map[string]interface{}{"age": 40}})
+	equal(t, "age = 30", []string{}, query(t, s, store.ReadFilter("age", store.Equal, 30)))
+	equal(t, "age > 35.5", []string{"a"}, query(t, s, store.ReadFilter("age", store.Greater, 35.5)))
+	equal(t, "name = alice", []string{}, query(t, s, store.ReadFilter("name", store.Equal, "alice")))
+
+	if err := s.Delete("c"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	equal(t, "age > 25", []string{"a"}, query(t, s, store.ReadFilter("age", store.Greater, 25)))
+
+	// expired records don't match
+	ttl := 200 * time.Millisecond
+	write(t, s, &store.Record{Key: "f", Metadata: map[string]interface{}{"age": 50}}, store.WriteTTL(ttl))
+	equal(t, "age > 40", []string{"f"}, query(t, s, store.ReadFilter("age", store.Greater, 40)))
+	time.Sleep(ttl + 100*time.Millisecond)
+	equal(t, "age > 25", []string{"a"}, query(t, s, store.ReadFilter("age", store.Greater, 25)))
+}
@@ -0,0 +1,591 @@
+// Package test provides a conformance test suite for store implementations
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/store"
+)
+
+var (
+	// the time to wait for a watcher to return an event
+	watchTimeout = 5 * time.Second
+)
+
+// Run the conformance tests against the stores returned by newStore. Each call
+// to newStore must return a new, initialised store with an empty default table.
+// The store is closed at the end of each test.
+func Run(t *testing.T, newStore func() store.Store) {
+	tests := []struct {
+		name string
+		test func(*testing.T, store.Store)
+	}{
+		{"ReadWrite", testReadWrite},
+		{"Delete", testDelete},
+		{"Expiry", testExpiry},
+		{"PrefixSuffix", testPrefixSuffix},
+		{"LimitOffset", testLimitOffset},
+		{"List", testList},
+		{"Tables", testTables},
+		{"Version", testVersion},
+		{"Batch", testBatch},
+		{"Tx", testTx},
+		{"Watch", testWatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStore()
+			defer s.Close()
+			tt.test(t, s)
+		})
+	}
+}
+
+func write(t *testing.T, s store.Store, r *store.Record, opts ...store.WriteOption) {
+	t.Helper()
+	if err := s.Write(r, opts...); err != nil {
+		t.Fatalf("Write %s: %v", r.Key, err)
+	}
+}
+
+func readOne(t *testing.T, s store.Store, key string, opts ...store.ReadOption) *store.Record {
+	t.Helper()
+	r, err := s.Read(key, opts...)
+	if err != nil {
+		t.Fatalf("Read %s: %v", key, err)
+	}
+	if len(r) != 1 {
+		t.Fatalf("Read %s: expected 1 record, got %d", key, len(r))
+	}
+	if r[0].Key != key {
+		t.Fatalf("Read %s: got key %s", key, r[0].Key)
+	}
+	return r[0]
+}
+
+func notFound(t *testing.T, s store.Store, key string, opts ...store.ReadOption) {
+	t.Helper()
+	if _, err := s.Read(key, opts...); err != store.ErrNotFound {
+		t.Fatalf("Read %s: expected %v, got %v", key, store.ErrNotFound, err)
+	}
+}
+
+// keys returns the keys of the records in order
+func keys(records []*store.Record) []string {
+	k := make([]string, len(records))
+	for i, r := range records {
+		k[i] = r.Key
+	}
+	return k
+}
+
+func equal(t *testing.T, what string, expected, got []string) {
+	t.Helper()
+	if len(expected) == 0 && len(got) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("%s: expected %v, got %v", what, expected, got)
+	}
+}
+
+func testReadWrite(t *testing.T, s store.Store) {
+	notFound(t, s, "foo")
+
+	write(t, s, &store.Record{
+		Key:      "foo",
+		Value:    []byte("bar"),
+		Metadata: map[string]interface{}{"foo": "bar"},
+	})
+
+	r := readOne(t, s, "foo")
+	if string(r.Value) != "bar" {
+		t.Fatalf("Expected value bar, got %s", r.Value)
+	}
+	if r.Metadata["foo"] != "bar" {
+		t.Fatalf("Expected metadata foo=bar, got %v", r.Metadata)
+	}
+	if r.Expiry != 0 {
+		t.Fatalf("Expected no expiry, got %v", r.Expiry)
+	}
+
+	// overwrite the record
+	write(t, s, &store.Record{Key: "foo", Value: []byte("baz")})
+
+	if r := readOne(t, s, "foo"); string(r.Value) != "baz" {
+		t.Fatalf("Expected value baz, got %s", r.Value)
+	}
+
+	// modifying a record read doesn't change the store
+	r = readOne(t, s, "foo")
+	r.Value[0] = 'x'
+	if r := readOne(t, s, "foo"); string(r.Value) != "baz" {
+		t.Fatalf("Expected value baz, got %s", r.Value)
+	}
+}
+
+func testDelete(t *testing.T, s store.Store) {
+	// deleting a record which doesn't exist is not an error
+	if err := s.Delete("foo"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	write(t, s, &store.Record{Key: "foo", Value: []byte("bar")})
+	write(t, s, &store.Record{Key: "foobar", Value: []byte("bar")})
+
+	if err := s.Delete("foo"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	notFound(t, s, "foo")
+	readOne(t, s, "foobar")
+
+	k, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	equal(t, "List", []string{"foobar"}, k)
+}
+
+func testExpiry(t *testing.T, s store.Store) {
+	ttl := 200 * time.Millisecond
+
+	write(t, s, &store.Record{Key: "expiry", Value: []byte("a"), Expiry: ttl})
+	write(t, s, &store.Record{Key: "ttl", Value: []byte("a")}, store.WriteTTL(ttl))
+	write(t, s, &store.Record{Key: "time", Value: []byte("a")}, store.WriteExpiry(time.Now().Add(ttl)))
+	// the ttl takes precedence over the expiry time
+	write(t, s, &store.Record{Key: "both", Value: []byte("a")}, store.WriteExpiry(time.Now().Add(time.Hour)), store.WriteTTL(ttl))
+	write(t, s, &store.Record{Key: "forever", Value: []byte("a")})
+
+	for _, key := range []string{"expiry", "ttl", "time", "both"} {
+		r := readOne(t, s, key)
+		if r.Expiry <= 0 || r.Expiry > ttl {
+			t.Fatalf("Expected %s to expire within %v, got %v", key, ttl, r.Expiry)
+		}
+	}
+
+	time.Sleep(ttl + 100*time.Millisecond)
+
+	for _, key := range []string{"expiry", "ttl", "time", "both"} {
+		notFound(t, s, key)
+	}
+
+	// expired records are not listed
+	r, err := s.Read("", store.ReadPrefix())
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	equal(t, "Read prefix", []string{"forever"}, keys(r))
+
+	k, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	equal(t, "List", []string{"forever"}, k)
+}
+
+func testPrefixSuffix(t *testing.T, s store.Store) {
+	for _, key := range []string{"foo", "foobar", "barfoo", "foobarfoo", "bar", "fo"} {
+		write(t, s, &store.Record{Key: key, Value: []byte(key)})
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		opts     []store.ReadOption
+		expected []string
+	}{
+		{"prefix", "foo", []store.ReadOption{store.ReadPrefix()}, []string{"foo", "foobar", "foobarfoo"}},
+		{"suffix", "foo", []store.ReadOption{store.ReadSuffix()}, []string{"barfoo", "foo", "foobarfoo"}},
+		{"prefix and suffix", "foo", []store.ReadOption{store.ReadPrefix(), store.ReadSuffix()}, []string{"foo", "foobarfoo"}},
+		{"no match", "baz", []store.ReadOption{store.ReadPrefix()}, nil},
+		{"empty prefix", "", []store.ReadOption{store.ReadPrefix()}, []string{"bar", "barfoo", "fo", "foo", "foobar", "foobarfoo"}},
+	}
+
+	for _, tt := range tests {
+		r, err := s.Read(tt.key, tt.opts...)
+		if err != nil {
+			t.Fatalf("Read %s: %v", tt.name, err)
+		}
+		equal(t, "Read "+tt.name, tt.expected, keys(r))
+		for _, rec := range r {
+			if string(rec.Value) != rec.Key {
+				t.Fatalf("Read %s: expected value %s, got %s", tt.name, rec.Key, rec.Value)
+			}
+		}
+	}
+}
+
+func testLimitOffset(t *testing.T, s store.Store) {
+	var all []string
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("a%d", i)
+		all = append(all, key)
+		write(t, s, &store.Record{Key: key, Value: []byte(key)})
+	}
+	write(t, s, &store.Record{Key: "b0", Value: []byte("b0")})
+
+	tests := []struct {
+		limit    uint
+		offset   uint
+		expected []string
+	}{
+		{5, 0, all[:5]},
+		{5, 5, all[5:]},
+		{5, 8, all[8:]},
+		{0, 3, all[3:]},
+		{30, 0, all},
+		{5, 10, nil},
+	}
+
+	for _, tt := range tests {
+		r, err := s.Read("a", store.ReadPrefix(), store.ReadLimit(tt.limit), store.ReadOffset(tt.offset))
+		if err != nil {
+			t.Fatalf("Read limit %d offset %d: %v", tt.limit, tt.offset, err)
+		}
+		equal(t, "Read", tt.expected, keys(r))
+
+		k, err := s.List(store.ListPrefix("a"), store.ListLimit(tt.limit), store.ListOffset(tt.offset))
+		if err != nil {
+			t.Fatalf("List limit %d offset %d: %v", tt.limit, tt.offset, err)
+		}
+		equal(t, "List", tt.expected, k)
+	}
+}
+
+func testList(t *testing.T, s store.Store) {
+	k, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	equal(t, "List", nil, k)
+
+	for _, key := range []string{"foo", "foobar", "barfoo", "bar"} {
+		write(t, s, &store.Record{Key: key, Value: []byte(key)})
+	}
+
+	tests := []struct {
+		name     string
+		opts     []store.ListOption
+		expected []string
+	}{
+		{"all", nil, []string{"bar", "barfoo", "foo", "foobar"}},
+		{"prefix", []store.ListOption{store.ListPrefix("foo")}, []string{"foo", "foobar"}},
+		{"suffix", []store.ListOption{store.ListSuffix("foo")}, []string{"barfoo", "foo"}},
+		{"prefix and suffix", []store.ListOption{store.ListPrefix("bar"), store.ListSuffix("foo")}, []string{"barfoo"}},
+		{"no match", []store.ListOption{store.ListPrefix("baz")}, nil},
+	}
+
+	for _, tt := range tests {
+		k, err := s.List(tt.opts...)
+		if err != nil {
+			t.Fatalf("List %s: %v", tt.name, err)
+		}
+		equal(t, "List "+tt.name, tt.expected, k)
+	}
+}
+
+func testTables(t *testing.T, s store.Store) {
+	db, table := s.Options().Database, "conformance-other"
+
+	write(t, s, &store.Record{Key: "foo", Value: []byte("default")})
+	write(t, s, &store.Record{Key: "foo", Value: []byte("other")}, store.WriteTo(db, table))
+	write(t, s, &store.Record{Key: "bar", Value: []byte("other")}, store.WriteTo(db, table))
+
+	if r := readOne(t, s, "foo"); string(r.Value) != "default" {
+		t.Fatalf("Expected value default, got %s", r.Value)
+	}
+	if r := readOne(t, s, "foo", store.ReadFrom(db, table)); string(r.Value) != "other" {
+		t.Fatalf("Expected value other, got %s", r.Value)
+	}
+	notFound(t, s, "bar")
+
+	k, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	equal(t, "List", []string{"foo"}, k)
+
+	k, err = s.List(store.ListFrom(db, table))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	equal(t, "List other table", []string{"bar", "foo"}, k)
+
+	if err := s.Delete("foo", store.DeleteFrom(db, table)); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	notFound(t, s, "foo", store.ReadFrom(db, table))
+	readOne(t, s, "foo")
+
+	// clean up the other table
+	if err := s.Delete("bar", store.DeleteFrom(db, table)); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func testVersion(t *testing.T, s store.Store) {
+	// only write if absent
+	write(t, s, &store.Record{Key: "foo", Value: []byte("a")}, store.WriteIfAbsent())
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("b")}, store.WriteIfAbsent()); err != store.ErrConflict {
+		t.Fatalf("Expected %v, got %v", store.ErrConflict, err)
+	}
+	if r := readOne(t, s, "foo"); string(r.Value) != "a" || r.Version != 1 {
+		t.Fatalf("Expected a at version 1, got %s at version %d", r.Value, r.Version)
+	}
+
+	// write at the current version
+	write(t, s, &store.Record{Key: "foo", Value: []byte("c")}, store.WriteVersion(1))
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("d")}, store.WriteVersion(1)); err != store.ErrConflict {
+		t.Fatalf("Expected %v, got %v", store.ErrConflict, err)
+	}
+	// a version can't be written if the record doesn't exist
+	if err := s.Write(&store.Record{Key: "bar", Value: []byte("a")}, store.WriteVersion(1)); err != store.ErrConflict {
+		t.Fatalf("Expected %v, got %v", store.ErrConflict, err)
+	}
+	notFound(t, s, "bar")
+
+	if r := readOne(t, s, "foo"); string(r.Value) != "c" || r.Version != 2 {
+		t.Fatalf("Expected c at version 2, got %s at version %d", r.Value, r.Version)
+	}
+
+	// unconditional writes always succeed and increment the version
+	write(t, s, &store.Record{Key: "foo", Value: []byte("e")})
+	if r := readOne(t, s, "foo"); r.Version != 3 {
+		t.Fatalf("Expected version 3, got %d", r.Version)
+	}
+
+	// the version starts again once deleted
+	if err := s.Delete("foo"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	write(t, s, &store.Record{Key: "foo", Value: []byte("f")}, store.WriteIfAbsent())
+	if r := readOne(t, s, "foo"); r.Version != 1 {
+		t.Fatalf("Expected version 1, got %d", r.Version)
+	}
+
+	// as it does once expired, whether written unconditionally or only if absent
+	for _, opts := range [][]store.WriteOption{nil, {store.WriteIfAbsent()}} {
+		write(t, s, &store.Record{Key: "expiring", Value: []byte("a")})
+		write(t, s, &store.Record{Key: "expiring", Value: []byte("b"), Expiry: 10 * time.Millisecond})
+		time.Sleep(50 * time.Millisecond)
+		write(t, s, &store.Record{Key: "expiring", Value: []byte("c")}, opts...)
+		if r := readOne(t, s, "expiring"); r.Version != 1 {
+			t.Fatalf("Expected version 1 once expired, got %d", r.Version)
+		}
+		if err := s.Delete("expiring"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+
+	// an expired record is absent
+	write(t, s, &store.Record{Key: "expired", Value: []byte("a"), Expiry: 10 * time.Millisecond})
+	time.Sleep(50 * time.Millisecond)
+	write(t, s, &store.Record{Key: "expired", Value: []byte("b")}, store.WriteIfAbsent())
+}
+
+func testBatch(t *testing.T, s store.Store) {
+	// an empty batch does nothing
+	if err := s.Batch(&store.Batch{}); err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+
+	err := s.Batch(&store.Batch{
+		Writes: []*store.Record{
+			{Key: "foo", Value: []byte("a")},
+			{Key: "bar", Value: []byte("a")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	for _, key := range []string{"foo", "bar"} {
+		if r := readOne(t, s, key); string(r.Value) != "a" || r.Version != 1 {
+			t.Fatalf("Expected %s to be a at version 1, got %s at version %d", key, r.Value, r.Version)
+		}
+	}
+
+	// the reads match
+	err = s.Batch(&store.Batch{
+		Reads:   map[string]uint64{"foo": 1, "baz": 0},
+		Writes:  []*store.Record{{Key: "foo", Value: []byte("b")}},
+		Deletes: []string{"bar"},
+	})
+	if err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	if r := readOne(t, s, "foo"); string(r.Value) != "b" || r.Version != 2 {
+		t.Fatalf("Expected b at version 2, got %s at version %d", r.Value, r.Version)
+	}
+	notFound(t, s, "bar")
+
+	// none of the batch is applied if a read has changed
+	for _, reads := range []map[string]uint64{{"foo": 1}, {"foo": 0}, {"bar": 1}} {
+		err = s.Batch(&store.Batch{
+			Reads:   reads,
+			Writes:  []*store.Record{{Key: "baz", Value: []byte("a")}},
+			Deletes: []string{"foo"},
+		})
+		if err != store.ErrConflict {
+			t.Fatalf("Expected %v for reads %v, got %v", store.ErrConflict, reads, err)
+		}
+		notFound(t, s, "baz")
+		readOne(t, s, "foo")
+	}
+}
+
+func testTx(t *testing.T, s store.Store) {
+	write(t, s, &store.Record{Key: "foo", Value: []byte("a")})
+
+	tx := store.NewTx(s)
+	r, err := tx.Read("foo")
+	if err != nil {
+		t.Fatalf("Tx Read: %v", err)
+	}
+	if err := tx.Write(&store.Record{Key: "bar", Value: r[0].Value}); err != nil {
+		t.Fatalf("Tx Write: %v", err)
+	}
+	if err := tx.Delete("foo"); err != nil {
+		t.Fatalf("Tx Delete: %v", err)
+	}
+
+	// the transaction reads its own writes
+	if _, err := tx.Read("foo"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+	if r, err := tx.Read("bar"); err != nil || string(r[0].Value) != "a" {
+		t.Fatalf("Expected bar to be a, got %v %v", r, err)
+	}
+
+	// nothing is written until commit
+	readOne(t, s, "foo")
+	notFound(t, s, "bar")
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := tx.Commit(); err != store.ErrTxDone {
+		t.Fatalf("Expected %v, got %v", store.ErrTxDone, err)
+	}
+	notFound(t, s, "foo")
+	readOne(t, s, "bar")
+
+	// the transaction conflicts if a record read changes
+	tx = store.NewTx(s)
+	if _, err := tx.Read("bar"); err != nil {
+		t.Fatalf("Tx Read: %v", err)
+	}
+	if _, err := tx.Read("foo"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+	if err := tx.Write(&store.Record{Key: "baz", Value: []byte("a")}); err != nil {
+		t.Fatalf("Tx Write: %v", err)
+	}
+	write(t, s, &store.Record{Key: "foo", Value: []byte("b")})
+
+	if err := tx.Commit(); err != store.ErrConflict {
+		t.Fatalf("Expected %v, got %v", store.ErrConflict, err)
+	}
+	notFound(t, s, "baz")
+
+	// a rolled back transaction writes nothing
+	tx = store.NewTx(s)
+	if err := tx.Write(&store.Record{Key: "baz", Value: []byte("a")}); err != nil {
+		t.Fatalf("Tx Write: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if err := tx.Commit(); err != store.ErrTxDone {
+		t.Fatalf("Expected %v, got %v", store.ErrTxDone, err)
+	}
+	notFound(t, s, "baz")
+}
+
+// next returns the next event of the watcher or fails the test after the watch timeout
+func next(t *testing.T, w store.Watcher) *store.Event {
+	t.Helper()
+
+	type result struct {
+		ev  *store.Event
+		err error
+	}
+
+	ch := make(chan result, 1)
+	go func() {
+		ev, err := w.Next()
+		ch <- result{ev, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("Next: %v", r.err)
+		}
+		return r.ev
+	case <-time.After(watchTimeout):
+		t.Fatalf("Next: no event after %v", watchTimeout)
+	}
+
+	return nil
+}
+
+func testWatch(t *testing.T, s store.Store) {
+	db, table := s.Options().Database, "conformance-other"
+
+	w, err := s.Watch(store.WatchPrefix("foo"))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer w.Stop()
+
+	// changes to other tables and prefixes aren't watched
+	write(t, s, &store.Record{Key: "foo", Value: []byte("other")}, store.WriteTo(db, table))
+	write(t, s, &store.Record{Key: "bar", Value: []byte("a")})
+
+	write(t, s, &store.Record{Key: "foo", Value: []byte("a")})
+	write(t, s, &store.Record{Key: "foobar", Value: []byte("a")})
+	write(t, s, &store.Record{Key: "foo", Value: []byte("b")})
+	if err := s.Delete("foo"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete("foo", store.DeleteFrom(db, table)); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	expected := []struct {
+		typ     store.EventType
+		key     string
+		value   string
+		version uint64
+	}{
+		{store.Create, "foo", "a", 1},
+		{store.Create, "foobar", "a", 1},
+		{store.Update, "foo", "b", 2},
+		{store.Delete, "foo", "", 0},
+	}
+
+	// events are returned in the order of the changes
+	for _, e := range expected {
+		ev := next(t, w)
+		if ev.Type != e.typ || ev.Record == nil || ev.Record.Key != e.key {
+			t.Fatalf("Expected %s of %s, got %s of %+v", e.typ, e.key, ev.Type, ev.Record)
+		}
+		if e.typ == store.Delete {
+			continue
+		}
+		if string(ev.Record.Value) != e.value || ev.Record.Version != e.version {
+			t.Fatalf("Expected %s of %s to be %s at version %d, got %s at version %d",
+				e.typ, e.key, e.value, e.version, ev.Record.Value, ev.Record.Version)
+		}
+	}
+
+	w.Stop()
+	if _, err := w.Next(); err == nil {
+		t.Fatal("Expected an error from a stopped watcher")
+	}
+}
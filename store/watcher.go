@@ -0,0 +1,58 @@
+package store
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrWatcherStopped is returned when a watcher is stopped
+	ErrWatcherStopped = errors.New("watcher stopped")
+	// ErrWatcherOverflow is returned when a watcher is stopped because it fell
+	// behind the changes, so the records watched must be read again
+	ErrWatcherOverflow = errors.New("watcher overflow")
+)
+
+// Watcher returns the changes to the records of a store
+type Watcher interface {
+	// Next is a blocking call
+	Next() (*Event, error)
+	Stop()
+}
+
+// EventType defines the type of change to a record
+type EventType int
+
+const (
+	// Create is emitted when a record is written which didn't exist
+	Create EventType = iota
+	// Update is emitted when an existing record is written
+	Update
+	// Delete is emitted when a record is deleted
+	Delete
+)
+
+// String returns human readable event type
+func (t EventType) String() string {
+	switch t {
+	case Create:
+		return "create"
+	case Update:
+		return "update"
+	case Delete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a change to a record
+type Event struct {
+	// Type of change
+	Type EventType
+	// Record as written, including its new version.
+	// Only the key is set when the record was deleted.
+	Record *Record
+	// Timestamp of the change
+	Timestamp time.Time
+}
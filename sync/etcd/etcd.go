@@ -3,7 +3,6 @@ package etcd
 
 import (
 	"context"
-	"errors"
 	"log"
 	"path"
 	"strings"
@@ -24,8 +23,10 @@ type etcdSync struct {
 }
 
 type etcdLock struct {
-	s *cc.Session
-	m *cc.Mutex
+	id   string
+	s    *cc.Session
+	m    *cc.Mutex
+	sync *etcdSync
 }
 
 type etcdLeader struct {
@@ -83,6 +84,32 @@ func (e *etcdLeader) Resign() error {
 	return e.e.Resign(context.Background())
 }
 
+func (e *etcdLock) Id() string {
+	return e.id
+}
+
+// Token is the revision of etcd the lock was acquired at
+func (e *etcdLock) Token() uint64 {
+	return uint64(e.m.Header().Revision)
+}
+
+// Lost returns a channel which is closed when the lease of the session can't be kept alive
+func (e *etcdLock) Lost() <-chan struct{} {
+	return e.s.Done()
+}
+
+func (e *etcdLock) Unlock() error {
+	e.sync.mtx.Lock()
+	if e.sync.locks[e.id] == e {
+		delete(e.sync.locks, e.id)
+	}
+	e.sync.mtx.Unlock()
+
+	err := e.m.Unlock(context.Background())
+	e.s.Close()
+	return err
+}
+
 func (e *etcdSync) Init(opts ...sync.Option) error {
 	for _, o := range opts {
 		o(&e.options)
@@ -94,7 +121,7 @@ func (e *etcdSync) Options() sync.Options {
 	return e.options
 }
 
-func (e *etcdSync) Lock(id string, opts ...sync.LockOption) error {
+func (e *etcdSync) Lock(id string, opts ...sync.LockOption) (sync.Lock, error) {
 	var options sync.LockOptions
 	for _, o := range opts {
 		o(&options)
@@ -103,6 +130,7 @@ func (e *etcdSync) Lock(id string, opts ...sync.LockOption) error {
 	// make path
 	path := path.Join(e.path, strings.Replace(e.options.Prefix+id, "/", "-", -1))
 
+	// the session keeps the lease alive until it's closed
 	var sopts []cc.SessionOption
 	if options.TTL > 0 {
 		sopts = append(sopts, cc.WithTTL(int(options.TTL.Seconds())))
@@ -110,34 +138,53 @@ func (e *etcdSync) Lock(id string, opts ...sync.LockOption) error {
 
 	s, err := cc.NewSession(e.client, sopts...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	wctx := ctx
+	if options.Wait > 0 {
+		var cancel context.CancelFunc
+		wctx, cancel = context.WithTimeout(ctx, options.Wait)
+		defer cancel()
 	}
 
 	m := cc.NewMutex(s, path)
 
-	if err := m.Lock(context.TODO()); err != nil {
-		return err
+	if err := m.Lock(wctx); err != nil {
+		s.Close()
+		// the wait time passed rather than the context being cancelled
+		if wctx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			return nil, sync.ErrLockTimeout
+		}
+		return nil, err
 	}
 
-	e.mtx.Lock()
-	e.locks[id] = &etcdLock{
-		s: s,
-		m: m,
+	lk := &etcdLock{
+		id:   id,
+		s:    s,
+		m:    m,
+		sync: e,
 	}
+
+	e.mtx.Lock()
+	e.locks[id] = lk
 	e.mtx.Unlock()
-	return nil
+
+	return lk, nil
 }
 
 func (e *etcdSync) Unlock(id string) error {
 	e.mtx.Lock()
-	defer e.mtx.Unlock()
 	v, ok := e.locks[id]
+	e.mtx.Unlock()
 	if !ok {
-		return errors.New("lock not found")
+		return sync.ErrLockNotFound
 	}
-	err := v.m.Unlock(context.Background())
-	delete(e.locks, id)
-	return err
+	return v.Unlock()
 }
 
 func (e *etcdSync) String() string {
@@ -2,6 +2,7 @@
 package memory
 
 import (
+	"context"
 	gosync "sync"
 	"time"
 
@@ -13,13 +14,16 @@ type memorySync struct {
 
 	mtx   gosync.RWMutex
 	locks map[string]*memoryLock
+	// the last fencing token of each lock
+	tokens map[string]uint64
 }
 
 type memoryLock struct {
-	id      string
-	time    time.Time
-	ttl     time.Duration
-	release chan bool
+	id    string
+	token uint64
+	sync  *memorySync
+	// closed when the lock is unlocked
+	release chan struct{}
 }
 
 type memoryLeader struct {
@@ -29,6 +33,35 @@ type memoryLeader struct {
 	status chan bool
 }
 
+func (m *memoryLock) Id() string {
+	return m.id
+}
+
+func (m *memoryLock) Token() uint64 {
+	return m.token
+}
+
+// Lost returns a channel which is closed when the lock is unlocked,
+// locks held in memory are never lost
+func (m *memoryLock) Lost() <-chan struct{} {
+	return m.release
+}
+
+func (m *memoryLock) Unlock() error {
+	m.sync.mtx.Lock()
+	defer m.sync.mtx.Unlock()
+
+	// the lock was already released
+	if m.sync.locks[m.id] != m {
+		return nil
+	}
+
+	delete(m.sync.locks, m.id)
+	close(m.release)
+
+	return nil
+}
+
 func (m *memoryLeader) Resign() error {
 	return m.resign(m.id)
 }
@@ -45,22 +78,28 @@ func (m *memorySync) Leader(id string, opts ...sync.LeaderOption) (sync.Leader,
 	}
 
 	// acquire a lock for the id
-	if err := m.Lock(id); err != nil {
+	lk, err := m.Lock(id)
+	if err != nil {
 		return nil, err
 	}
 
+	status := make(chan bool, 1)
+	go func() {
+		<-lk.Lost()
+		status <- true
+	}()
+
 	// return the leader
 	return &memoryLeader{
 		opts: options,
 		id:   id,
 		resign: func(id string) error {
 			once.Do(func() {
-				m.Unlock(id)
+				lk.Unlock()
 			})
 			return nil
 		},
-		// TODO: signal when Unlock is called
-		status: make(chan bool, 1),
+		status: status,
 	}, nil
 }
 
@@ -75,114 +114,70 @@ func (m *memorySync) Options() sync.Options {
 	return m.options
 }
 
-func (m *memorySync) Lock(id string, opts ...sync.LockOption) error {
-	// lock our access
-	m.mtx.Lock()
-
+// Lock acquires the lock for the id. Locks held in memory can't be lost
+// while the process is running so the ttl of the options isn't used.
+func (m *memorySync) Lock(id string, opts ...sync.LockOption) (sync.Lock, error) {
 	var options sync.LockOptions
 	for _, o := range opts {
 		o(&options)
 	}
 
-	lk, ok := m.locks[id]
-	if !ok {
-		m.locks[id] = &memoryLock{
-			id:      id,
-			time:    time.Now(),
-			ttl:     options.TTL,
-			release: make(chan bool),
-		}
-		// unlock
-		m.mtx.Unlock()
-		return nil
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	m.mtx.Unlock()
-
-	// set wait time
-	var wait <-chan time.Time
-	var ttl <-chan time.Time
-
 	// decide if we should wait
+	var wait <-chan time.Time
 	if options.Wait > time.Duration(0) {
-		wait = time.After(options.Wait)
+		t := time.NewTimer(options.Wait)
+		defer t.Stop()
+		wait = t.C
 	}
 
-	// check the ttl of the lock
-	if lk.ttl > time.Duration(0) {
-		// time lived for the lock
-		live := time.Since(lk.time)
-
-		// set a timer for the leftover ttl
-		if live > lk.ttl {
-			// release the lock if it expired
-			_ = m.Unlock(id)
-		} else {
-			ttl = time.After(live)
-		}
-	}
-
-lockLoop:
 	for {
-		// wait for the lock to be released
-		select {
-		case <-lk.release:
-			m.mtx.Lock()
+		m.mtx.Lock()
 
-			// someone locked before us
-			lk, ok = m.locks[id]
-			if ok {
-				m.mtx.Unlock()
-				continue
-			}
-
-			// got chance to lock
-			m.locks[id] = &memoryLock{
+		lk, ok := m.locks[id]
+		if !ok {
+			m.tokens[id]++
+			lk = &memoryLock{
 				id:      id,
-				time:    time.Now(),
-				ttl:     options.TTL,
-				release: make(chan bool),
+				token:   m.tokens[id],
+				sync:    m,
+				release: make(chan struct{}),
 			}
-
+			m.locks[id] = lk
 			m.mtx.Unlock()
+			return lk, nil
+		}
+
+		m.mtx.Unlock()
 
-			break lockLoop
-		case <-ttl:
-			// ttl exceeded
-			_ = m.Unlock(id)
-			// TODO: check the ttl again above
-			ttl = nil
-			// try acquire
+		// wait for the lock to be released
+		select {
+		case <-lk.release:
+			// someone may lock before us
 			continue
 		case <-wait:
-			return sync.ErrLockTimeout
+			return nil, sync.ErrLockTimeout
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
-
-	return nil
 }
 
 func (m *memorySync) Unlock(id string) error {
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
-
+	m.mtx.RLock()
 	lk, ok := m.locks[id]
+	m.mtx.RUnlock()
+
 	// no lock exists
 	if !ok {
 		return nil
 	}
 
-	// delete the lock
-	delete(m.locks, id)
-
-	select {
-	case <-lk.release:
-		return nil
-	default:
-		close(lk.release)
-	}
-
-	return nil
+	return lk.Unlock()
 }
 
 func (m *memorySync) String() string {
@@ -198,5 +193,6 @@ func NewSync(opts ...sync.Option) sync.Sync {
 	return &memorySync{
 		options: options,
 		locks:   make(map[string]*memoryLock),
+		tokens:  make(map[string]uint64),
 	}
 }
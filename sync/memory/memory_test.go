@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/sync"
+)
+
+func TestLock(t *testing.T) {
+	s := NewSync()
+
+	lk, err := s.Lock("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Lock("foo", sync.LockWait(100*time.Millisecond)); err != sync.ErrLockTimeout {
+		t.Fatalf("Expected %v, got %v", sync.ErrLockTimeout, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := s.Lock("foo", sync.LockContext(ctx)); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	locked := make(chan sync.Lock)
+	go func() {
+		next, err := s.Lock("foo")
+		if err != nil {
+			t.Error(err)
+		}
+		locked <- next
+	}()
+
+	if err := lk.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-lk.Lost():
+	default:
+		t.Fatal("Expected the lock to be lost once unlocked")
+	}
+
+	select {
+	case next := <-locked:
+		if next.Token() <= lk.Token() {
+			t.Fatalf("Expected a token greater than %d, got %d", lk.Token(), next.Token())
+		}
+		if err := s.Unlock("foo"); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the lock to be acquired once released")
+	}
+}
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"context"
 	"time"
 )
 
@@ -18,7 +19,8 @@ func Prefix(p string) Option {
 	}
 }
 
-// LockTTL sets the lock ttl
+// LockTTL sets the lock ttl. The lock is renewed while it's held, so it
+// only expires if the holder stops renewing it, e.g. when its process dies.
 func LockTTL(t time.Duration) LockOption {
 	return func(o *LockOptions) {
 		o.TTL = t
@@ -31,3 +33,10 @@ func LockWait(t time.Duration) LockOption {
 		o.Wait = t
 	}
 }
+
+// LockContext sets the context to cancel acquiring the lock with
+func LockContext(ctx context.Context) LockOption {
+	return func(o *LockOptions) {
+		o.Context = ctx
+	}
+}
@@ -0,0 +1,21 @@
+package store
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/sync"
+)
+
+type storeKey struct{}
+
+// Store sets the store the locks are kept in. Defaults to the memory store,
+// which only synchronises the current process.
+func Store(s store.Store) sync.Option {
+	return func(o *sync.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, storeKey{}, s)
+	}
+}
@@ -0,0 +1,401 @@
+// Package store is a sync implementation on top of a store, for locks and
+// leader election across processes sharing the store
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/go-micro/v2/logger"
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/micro/go-micro/v2/sync"
+)
+
+var (
+	// DefaultTTL is the ttl of locks taken without one. Locks are renewed while
+	// they're held so they only expire if the holder stops renewing them.
+	DefaultTTL = 15 * time.Second
+	// DefaultInterval is how often a lock held by someone else is tried
+	DefaultInterval = 100 * time.Millisecond
+
+	// the key prefixes of locks, leaders and their fencing tokens
+	lockPrefix   = "sync/lock/"
+	leaderPrefix = "sync/leader/"
+	tokenPrefix  = "sync/token/"
+)
+
+type storeSync struct {
+	options sync.Options
+	store   store.Store
+
+	mtx gosync.Mutex
+	// the locks held by this process
+	locks map[string]*storeLock
+}
+
+// lock is the value of a lock record
+type lock struct {
+	Holder string `json:"holder"`
+	Token  uint64 `json:"token"`
+}
+
+type storeLock struct {
+	id    string
+	key   string
+	value lock
+	ttl   time.Duration
+	sync  *storeSync
+
+	once gosync.Once
+	exit chan bool
+	// closed when the lock is lost or released
+	lost chan struct{}
+}
+
+type storeLeader struct {
+	id     string
+	opts   sync.LeaderOptions
+	sync   *storeSync
+	lock   *storeLock
+	status chan bool
+}
+
+func (l *storeLeader) Resign() error {
+	return l.sync.release(l.lock)
+}
+
+// Status returns a channel which receives true once leadership is lost or resigned
+func (l *storeLeader) Status() chan bool {
+	return l.status
+}
+
+func (l *storeLock) Id() string {
+	return l.id
+}
+
+func (l *storeLock) Token() uint64 {
+	return l.value.Token
+}
+
+func (l *storeLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *storeLock) Unlock() error {
+	return l.sync.release(l)
+}
+
+// tokenKey is the key of the last fencing token handed out for the lock
+func tokenKey(key string) string {
+	return tokenPrefix + strings.TrimPrefix(key, "sync/")
+}
+
+// tryLock takes the lock if it isn't held, returning ErrConflict if it is
+func (s *storeSync) tryLock(key string, value *lock, ttl time.Duration) error {
+	tx := store.NewTx(s.store)
+
+	if _, err := tx.Read(key); err == nil {
+		tx.Rollback()
+		return store.ErrConflict
+	} else if err != store.ErrNotFound {
+		tx.Rollback()
+		return err
+	}
+
+	// the token is kept after the lock expires so it keeps increasing
+	records, err := tx.Read(tokenKey(key))
+	switch err {
+	case nil:
+		token, err := strconv.ParseUint(string(records[0].Value), 10, 64)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		value.Token = token + 1
+	case store.ErrNotFound:
+		value.Token = 1
+	default:
+		tx.Rollback()
+		return err
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Write(&store.Record{Key: key, Value: b}, store.WriteIfAbsent(), store.WriteTTL(ttl))
+	tx.Write(&store.Record{Key: tokenKey(key), Value: []byte(strconv.FormatUint(value.Token, 10))})
+
+	return tx.Commit()
+}
+
+// acquire the lock, retrying until it's released by its holder or expires
+func (s *storeSync) acquire(id, key string, options sync.LockOptions) (*storeLock, error) {
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var wait <-chan time.Time
+	if options.Wait > 0 {
+		t := time.NewTimer(options.Wait)
+		defer t.Stop()
+		wait = t.C
+	}
+
+	value := &lock{Holder: uuid.New().String()}
+
+	for {
+		err := s.tryLock(key, value, ttl)
+		if err == nil {
+			break
+		}
+		if err != store.ErrConflict {
+			return nil, err
+		}
+
+		select {
+		case <-wait:
+			return nil, sync.ErrLockTimeout
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(DefaultInterval):
+		}
+	}
+
+	l := &storeLock{
+		id:    id,
+		key:   key,
+		value: *value,
+		ttl:   ttl,
+		sync:  s,
+		exit:  make(chan bool),
+		lost:  make(chan struct{}),
+	}
+
+	go s.renew(l)
+
+	return l, nil
+}
+
+// update the lock record if it's still held by us, deleting it or renewing its ttl
+func (s *storeSync) update(l *storeLock, del bool) error {
+	tx := store.NewTx(s.store)
+
+	records, err := tx.Read(l.key)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var value lock
+	if err := json.Unmarshal(records[0].Value, &value); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if value != l.value {
+		tx.Rollback()
+		return sync.ErrLockNotFound
+	}
+
+	if del {
+		tx.Delete(l.key)
+	} else {
+		tx.Write(records[0], store.WriteTTL(l.ttl))
+	}
+
+	return tx.Commit()
+}
+
+// renew the lock until it's released, closing lost if it can't be renewed
+func (s *storeSync) renew(l *storeLock) {
+	defer l.once.Do(func() { close(l.lost) })
+
+	t := time.NewTicker(l.ttl / 3)
+	defer t.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-l.exit:
+			return
+		case <-t.C:
+		}
+
+		err := s.update(l, false)
+		switch err {
+		case nil:
+			renewed = time.Now()
+			continue
+		case store.ErrNotFound, store.ErrConflict, sync.ErrLockNotFound:
+			// the lock expired and may have been taken by someone else
+		default:
+			// the lock may still be held until the ttl has passed
+			if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+				logger.Warnf("Failed to renew lock %s: %v", l.key, err)
+			}
+			if time.Since(renewed) < l.ttl {
+				continue
+			}
+		}
+
+		if logger.V(logger.WarnLevel, logger.DefaultLogger) {
+			logger.Warnf("Lost lock %s", l.key)
+		}
+
+		s.mtx.Lock()
+		if s.locks[l.key] == l {
+			delete(s.locks, l.key)
+		}
+		s.mtx.Unlock()
+
+		return
+	}
+}
+
+// release stops renewing the lock and deletes it if it's still held
+func (s *storeSync) release(l *storeLock) error {
+	s.mtx.Lock()
+	if s.locks[l.key] != l {
+		s.mtx.Unlock()
+		return sync.ErrLockNotFound
+	}
+	delete(s.locks, l.key)
+	s.mtx.Unlock()
+
+	close(l.exit)
+	l.once.Do(func() { close(l.lost) })
+
+	if err := s.update(l, true); err != nil && err != store.ErrNotFound && err != sync.ErrLockNotFound {
+		return err
+	}
+	return nil
+}
+
+func (s *storeSync) configure() {
+	if st, ok := s.options.Context.Value(storeKey{}).(store.Store); ok {
+		s.store = st
+	}
+	if s.store == nil {
+		s.store = memory.NewStore()
+	}
+}
+
+func (s *storeSync) Init(opts ...sync.Option) error {
+	for _, o := range opts {
+		o(&s.options)
+	}
+	s.configure()
+	return nil
+}
+
+func (s *storeSync) Options() sync.Options {
+	return s.options
+}
+
+func (s *storeSync) Leader(id string, opts ...sync.LeaderOption) (sync.Leader, error) {
+	var options sync.LeaderOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	key := leaderPrefix + s.options.Prefix + id
+
+	// campaign until elected
+	l, err := s.acquire(id, key, sync.LockOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mtx.Lock()
+	s.locks[key] = l
+	s.mtx.Unlock()
+
+	leader := &storeLeader{
+		id:     id,
+		opts:   options,
+		sync:   s,
+		lock:   l,
+		status: make(chan bool, 1),
+	}
+
+	go func() {
+		<-l.lost
+		leader.status <- true
+		close(leader.status)
+	}()
+
+	return leader, nil
+}
+
+func (s *storeSync) Lock(id string, opts ...sync.LockOption) (sync.Lock, error) {
+	var options sync.LockOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	key := lockPrefix + s.options.Prefix + id
+
+	l, err := s.acquire(id, key, options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mtx.Lock()
+	s.locks[key] = l
+	s.mtx.Unlock()
+
+	return l, nil
+}
+
+func (s *storeSync) Unlock(id string) error {
+	key := lockPrefix + s.options.Prefix + id
+
+	s.mtx.Lock()
+	l, ok := s.locks[key]
+	s.mtx.Unlock()
+	if !ok {
+		return sync.ErrLockNotFound
+	}
+
+	return s.release(l)
+}
+
+func (s *storeSync) String() string {
+	return "store"
+}
+
+// NewSync returns a sync implementation which keeps the locks in a store as records
+// with a ttl, which are renewed while they're held. Every time a lock is acquired it
+// gets a fencing token, which is greater than the tokens of all the holders before it.
+func NewSync(opts ...sync.Option) sync.Sync {
+	var options sync.Options
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Context == nil {
+		options.Context = context.Background()
+	}
+
+	s := &storeSync{
+		options: options,
+		locks:   make(map[string]*storeLock),
+	}
+	s.configure()
+
+	return s
+}
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/store"
+	"github.com/micro/go-micro/v2/store/memory"
+	"github.com/micro/go-micro/v2/sync"
+)
+
+func TestLock(t *testing.T) {
+	st := memory.NewStore()
+	// two processes sharing a store
+	a := NewSync(Store(st))
+	b := NewSync(Store(st))
+
+	lk, err := a.Lock("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lk.Id() != "foo" {
+		t.Fatalf("Expected lock foo, got %s", lk.Id())
+	}
+	if _, err := b.Lock("foo", sync.LockWait(200*time.Millisecond)); err != sync.ErrLockTimeout {
+		t.Fatalf("Expected %v, got %v", sync.ErrLockTimeout, err)
+	}
+	if err := b.Unlock("foo"); err != sync.ErrLockNotFound {
+		t.Fatalf("Expected %v, got %v", sync.ErrLockNotFound, err)
+	}
+
+	// acquiring the lock can be cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := b.Lock("foo", sync.LockContext(ctx)); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	locked := make(chan sync.Lock)
+	go func() {
+		next, err := b.Lock("foo")
+		if err != nil {
+			t.Error(err)
+		}
+		locked <- next
+	}()
+
+	if err := lk.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-lk.Lost():
+	default:
+		t.Fatal("Expected the lock to be lost once unlocked")
+	}
+
+	select {
+	case next := <-locked:
+		// the token increases with every holder
+		if next.Token() <= lk.Token() {
+			t.Fatalf("Expected a token greater than %d, got %d", lk.Token(), next.Token())
+		}
+		if err := b.Unlock("foo"); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the lock to be acquired once released")
+	}
+}
+
+func TestLockRenewal(t *testing.T) {
+	st := memory.NewStore()
+	a := NewSync(Store(st))
+	b := NewSync(Store(st))
+
+	ttl := 300 * time.Millisecond
+	lk, err := a.Lock("foo", sync.LockTTL(ttl))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the lock is renewed past its ttl while it's held
+	if _, err := b.Lock("foo", sync.LockWait(3*ttl)); err != sync.ErrLockTimeout {
+		t.Fatalf("Expected %v, got %v", sync.ErrLockTimeout, err)
+	}
+
+	// once it can't be renewed it's lost, and can be taken by someone else
+	keys, err := st.List(store.ListPrefix(lockPrefix))
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("Expected the lock record, got %v %v", keys, err)
+	}
+	if err := st.Delete(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	next, err := b.Lock("foo", sync.LockWait(ttl))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-lk.Lost():
+	case <-time.After(ttl):
+		t.Fatal("Expected the lock to be lost")
+	}
+	if err := a.Unlock("foo"); err != sync.ErrLockNotFound {
+		t.Fatalf("Expected %v, got %v", sync.ErrLockNotFound, err)
+	}
+
+	// b still holds the lock
+	select {
+	case <-next.Lost():
+		t.Fatal("Expected the lock to be held")
+	default:
+	}
+	if err := next.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLeader(t *testing.T) {
+	st := memory.NewStore()
+	a := NewSync(Store(st))
+	b := NewSync(Store(st))
+
+	leader, err := a.Leader("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	elected := make(chan sync.Leader)
+	go func() {
+		l, err := b.Leader("foo")
+		if err != nil {
+			t.Error(err)
+		}
+		elected <- l
+	}()
+
+	select {
+	case <-elected:
+		t.Fatal("Expected a single leader")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := leader.Resign(); err != nil {
+		t.Fatal(err)
+	}
+	if lost := <-leader.Status(); !lost {
+		t.Fatal("Expected leadership to be lost")
+	}
+
+	select {
+	case l := <-elected:
+		l.Resign()
+	case <-time.After(time.Second):
+		t.Fatal("Expected a new leader once resigned")
+	}
+}
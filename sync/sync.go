@@ -2,12 +2,15 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"time"
 )
 
 var (
 	ErrLockTimeout = errors.New("lock timeout")
+	// ErrLockNotFound is returned when a lock isn't held
+	ErrLockNotFound = errors.New("lock not found")
 )
 
 // Sync is an interface for distributed synchronization
@@ -18,14 +21,31 @@ type Sync interface {
 	Options() Options
 	// Elect a leader
 	Leader(id string, opts ...LeaderOption) (Leader, error)
-	// Lock acquires a lock
-	Lock(id string, opts ...LockOption) error
-	// Unlock releases a lock
+	// Lock acquires a lock, blocking until it's acquired, the wait
+	// time passes or the context of the options is cancelled
+	Lock(id string, opts ...LockOption) (Lock, error)
+	// Unlock releases a lock held by this process
 	Unlock(id string) error
 	// Sync implementation
 	String() string
 }
 
+// Lock is a lock held by this process. It's renewed until it's unlocked,
+// and is lost if it can't be renewed before its ttl passes.
+type Lock interface {
+	// Id of the lock
+	Id() string
+	// Token is the fencing token of the lock, which is greater than the token of
+	// every previous holder. A resource protected by the lock can reject requests
+	// with a token lower than the highest it has seen, so a holder which lost the
+	// lock while paused can't overwrite the changes of the next holder.
+	Token() uint64
+	// Lost returns a channel which is closed when the lock is lost or unlocked
+	Lost() <-chan struct{}
+	// Unlock releases the lock
+	Unlock() error
+}
+
 // Leader provides leadership election
 type Leader interface {
 	// resign leadership
@@ -37,6 +57,10 @@ type Leader interface {
 type Options struct {
 	Nodes  []string
 	Prefix string
+
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
 }
 
 type Option func(o *Options)
@@ -48,6 +72,8 @@ type LeaderOption func(o *LeaderOptions)
 type LockOptions struct {
 	TTL  time.Duration
 	Wait time.Duration
+	// Context cancels the acquisition of the lock
+	Context context.Context
 }
 
 type LockOption func(o *LockOptions)
@@ -20,7 +20,7 @@ const (
 
 // Watcher is used to watch for events
 type Watcher interface {
-	// A channel of events
+	// A channel of events, closed when the watch ends
 	Chan() <-chan Event
 	// Stop the watcher
 	Stop()
@@ -66,6 +66,11 @@ func (wr *bodyWatcher) stream() {
 	reader := bufio.NewReader(wr.res.Body)
 
 	go func() {
+		// the results are closed once the body ends, e.g. when the
+		// api server times out the watch or the watcher is stopped
+		defer close(wr.results)
+		defer wr.res.Body.Close()
+
 		for {
 			// read a line
 			b, err := reader.ReadBytes('\n')
@@ -38,6 +38,36 @@ func (s *Scope) Delete(key string, opts ...store.DeleteOption) error {
 	return s.Store.Delete(key, opts...)
 }
 
+func (s *Scope) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	sb := &store.Batch{
+		Reads: make(map[string]uint64, len(b.Reads)),
+	}
+	for k, v := range b.Reads {
+		sb.Reads[fmt.Sprintf("%v/%v", s.prefix, k)] = v
+	}
+	for _, r := range b.Writes {
+		rec := *r
+		rec.Key = fmt.Sprintf("%v/%v", s.prefix, r.Key)
+		sb.Writes = append(sb.Writes, &rec)
+	}
+	for _, k := range b.Deletes {
+		sb.Deletes = append(sb.Deletes, fmt.Sprintf("%v/%v", s.prefix, k))
+	}
+	return s.Store.Batch(sb, opts...)
+}
+
+func (s *Scope) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	var wo store.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+
+	key := fmt.Sprintf("%v/%v", s.prefix, wo.Prefix)
+	opts = append(opts, store.WatchPrefix(key))
+
+	return s.Store.Watch(opts...)
+}
+
 func (s *Scope) List(opts ...store.ListOption) ([]string, error) {
 	var lops store.ListOptions
 	for _, o := range opts {
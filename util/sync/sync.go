@@ -104,6 +104,16 @@ func (c *syncStore) Delete(key string, opts ...store.DeleteOption) error {
 	return c.syncOpts.Stores[0].Delete(key, opts...)
 }
 
+// Batch applies a batch to the sync
+func (c *syncStore) Batch(b *store.Batch, opts ...store.BatchOption) error {
+	return c.syncOpts.Stores[0].Batch(b, opts...)
+}
+
+// Watch the changes to the sync
+func (c *syncStore) Watch(opts ...store.WatchOption) (store.Watcher, error) {
+	return c.syncOpts.Stores[0].Watch(opts...)
+}
+
 func (c *syncStore) Sync() error {
 	return nil
 }
@@ -3,14 +3,18 @@ package wrapper
 import (
 	"context"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/micro/go-micro/v2/auth"
 	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/debug/metrics"
 	"github.com/micro/go-micro/v2/debug/stats"
 	"github.com/micro/go-micro/v2/debug/trace"
 	"github.com/micro/go-micro/v2/errors"
 	"github.com/micro/go-micro/v2/metadata"
+	"github.com/micro/go-micro/v2/registry"
 	"github.com/micro/go-micro/v2/server"
 )
 
@@ -82,9 +86,13 @@ func (c *traceWrapper) Call(ctx context.Context, req client.Request, rsp interfa
 	newCtx, s := c.trace.Start(ctx, req.Service()+"."+req.Endpoint())
 
 	s.Type = trace.SpanTypeRequestOutbound
+	s.SetAttribute("rpc.service", req.Service())
+	s.SetAttribute("rpc.method", req.Endpoint())
+
 	err := c.Client.Call(newCtx, req, rsp, opts...)
 	if err != nil {
 		s.Metadata["error"] = err.Error()
+		s.SetStatus(trace.StatusError, err.Error())
 	}
 
 	// finish the trace
@@ -116,10 +124,13 @@ func TraceHandler(t trace.Tracer) server.HandlerWrapper {
 			// get the span
 			newCtx, s := t.Start(ctx, req.Service()+"."+req.Endpoint())
 			s.Type = trace.SpanTypeRequestInbound
+			s.SetAttribute("rpc.service", req.Service())
+			s.SetAttribute("rpc.method", req.Endpoint())
 
 			err := h(newCtx, req, rsp)
 			if err != nil {
 				s.Metadata["error"] = err.Error()
+				s.SetStatus(trace.StatusError, err.Error())
 			}
 
 			// finish
@@ -310,3 +321,109 @@ func (s *staticClient) Stream(ctx context.Context, req client.Request, opts ...c
 func StaticClient(address string, c client.Client) client.Client {
 	return &staticClient{address, c}
 }
+
+// requestMetrics are the metrics of the requests made or served by a service
+type requestMetrics struct {
+	requests *metrics.Counter
+	errors   *metrics.Counter
+	duration *metrics.Histogram
+}
+
+func newRequestMetrics(r *metrics.Registry, prefix string) *requestMetrics {
+	return &requestMetrics{
+		requests: r.Counter(prefix+"_requests_total", "Total number of requests",
+			"service", "endpoint", "peer"),
+		errors: r.Counter(prefix+"_errors_total", "Total number of requests which failed by error code",
+			"service", "endpoint", "peer", "code"),
+		duration: r.Histogram(prefix+"_request_duration_seconds", "Latency of requests in seconds",
+			metrics.DefaultBuckets, "service", "endpoint", "peer"),
+	}
+}
+
+func (m *requestMetrics) record(service, endpoint, peer string, d time.Duration, err error) {
+	m.requests.Inc(service, endpoint, peer)
+	m.duration.Observe(d.Seconds(), service, endpoint, peer)
+	if err != nil {
+		m.errors.Inc(service, endpoint, peer, errorCode(err))
+	}
+}
+
+// errorCode returns the code of an error as a label value
+func errorCode(err error) string {
+	if e := errors.FromError(err); e != nil && e.Code != 0 {
+		return strconv.Itoa(int(e.Code))
+	}
+	return "unknown"
+}
+
+// CallMetrics is a call wrapper which records the requests made to each node
+func CallMetrics(r *metrics.Registry) client.CallWrapper {
+	m := newRequestMetrics(r, "micro_client")
+
+	return func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			start := time.Now()
+			err := cf(ctx, node, req, rsp, opts)
+			m.record(req.Service(), req.Endpoint(), node.Address, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+// HandlerMetrics wraps a server handler to record the requests from each calling service
+func HandlerMetrics(r *metrics.Registry) server.HandlerWrapper {
+	m := newRequestMetrics(r, "micro_server")
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			peer, _ := metadata.Get(ctx, HeaderPrefix+"From-Service")
+
+			start := time.Now()
+			err := h(ctx, req, rsp)
+			m.record(req.Service(), req.Endpoint(), peer, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+// SubscriberMetrics wraps a subscriber to record the messages consumed per topic
+func SubscriberMetrics(r *metrics.Registry) server.SubscriberWrapper {
+	consumed := r.Counter("micro_broker_consumed_total", "Total number of messages consumed", "topic")
+	failed := r.Counter("micro_broker_consume_errors_total", "Total number of messages which failed to be processed", "topic")
+
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			err := fn(ctx, msg)
+			consumed.Inc(msg.Topic())
+			if err != nil {
+				failed.Inc(msg.Topic())
+			}
+			return err
+		}
+	}
+}
+
+type publishMetricsWrapper struct {
+	client.Client
+
+	published *metrics.Counter
+	failed    *metrics.Counter
+}
+
+func (p *publishMetricsWrapper) Publish(ctx context.Context, msg client.Message, opts ...client.PublishOption) error {
+	err := p.Client.Publish(ctx, msg, opts...)
+	p.published.Inc(msg.Topic())
+	if err != nil {
+		p.failed.Inc(msg.Topic())
+	}
+	return err
+}
+
+// PublishMetrics wraps a client to record the messages published per topic
+func PublishMetrics(r *metrics.Registry, c client.Client) client.Client {
+	return &publishMetricsWrapper{
+		Client:    c,
+		published: r.Counter("micro_broker_published_total", "Total number of messages published", "topic"),
+		failed:    r.Counter("micro_broker_publish_errors_total", "Total number of messages which failed to be published", "topic"),
+	}
+}
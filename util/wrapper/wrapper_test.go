@@ -9,6 +9,7 @@ import (
 
 	"github.com/micro/go-micro/v2/auth"
 	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/debug/metrics"
 	"github.com/micro/go-micro/v2/errors"
 	"github.com/micro/go-micro/v2/metadata"
 	"github.com/micro/go-micro/v2/server"
@@ -460,3 +461,36 @@ func TestCacheWrapper(t *testing.T) {
 		}
 	})
 }
+
+func TestHandlerMetrics(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	h := HandlerMetrics(r)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		if req.Endpoint() == "Foo.Error" {
+			return errors.BadRequest("go.micro.service.foo", "bad request")
+		}
+		return nil
+	})
+
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		"Micro-From-Service": "go.micro.service.bar",
+	})
+
+	h(ctx, testRequest{service: "go.micro.service.foo", endpoint: "Foo.Bar"}, nil)
+	h(ctx, testRequest{service: "go.micro.service.foo", endpoint: "Foo.Error"}, nil)
+
+	requests := r.Counter("micro_server_requests_total", "")
+	if v := requests.Value("go.micro.service.foo", "Foo.Bar", "go.micro.service.bar"); v != 1 {
+		t.Fatalf("expected 1 request got %v", v)
+	}
+
+	errs := r.Counter("micro_server_errors_total", "")
+	if v := errs.Value("go.micro.service.foo", "Foo.Error", "go.micro.service.bar", "400"); v != 1 {
+		t.Fatalf("expected 1 error got %v", v)
+	}
+
+	duration := r.Histogram("micro_server_request_duration_seconds", "", nil)
+	if v := duration.Count("go.micro.service.foo", "Foo.Bar", "go.micro.service.bar"); v != 1 {
+		t.Fatalf("expected 1 observation got %v", v)
+	}
+}